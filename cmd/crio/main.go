@@ -87,6 +87,10 @@ func catchShutdown(ctx context.Context, cancel context.CancelFunc, gserver *grpc
 			gserver.GracefulStop()
 			hserver.Shutdown(ctx) //nolint:errcheck // best-effort shutdown during graceful stop
 
+			if s == signals.Term {
+				streamingServer.AutoCheckpointOnDrain(ctx)
+			}
+
 			if err := streamingServer.StopStreamServer(); err != nil {
 				log.Warnf(ctx, "Error shutting down streaming server: %v", err)
 			}
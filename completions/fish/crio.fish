@@ -2,7 +2,7 @@
 
 function __fish_crio_no_subcommand --description 'Test if there has been any subcommand yet'
     for i in (commandline -opc)
-        if contains -- $i check complete completion help h config man markdown md status config c containers container cs s info i goroutines g heap hp version wipe help h
+        if contains -- $i check complete completion help h config man markdown md status config c containers container cs s info i goroutines g heap hp userns u runtimes r version wipe help h
             return 1
         end
     end
@@ -22,12 +22,23 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l blockio-config-file -r -d
 complete -c crio -n '__fish_crio_no_subcommand' -f -l blockio-reload -d 'Reload blockio-config-file and rescan blockio devices in the system before applying blockio parameters.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l cdi-spec-dirs -r -d 'Directories to scan for CDI Spec files.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l cgroup-manager -r -d 'cgroup manager (cgroupfs or systemd).'
+complete -c crio -n '__fish_crio_no_subcommand' -l checkpoint-restore-auto-checkpoint-directory -r -d 'Directory into which containers of pods with the "auto-checkpoint.crio.io" annotation are checkpointed automatically. Automatic checkpoints are disabled if empty.'
+complete -c crio -n '__fish_crio_no_subcommand' -l checkpoint-restore-auto-checkpoint-drain-file -r -d 'File indicating that the node gets drained. If it exists when CRI-O receives SIGTERM, containers of pods with the "drain" automatic checkpoint policy are checkpointed.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l checkpoint-restore-auto-checkpoint-memory-pressure -r -d 'Memory pressure threshold in percent (1-100) of the last 10 seconds, above which containers of pods with the "memory-pressure" automatic checkpoint policy are checkpointed.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l checkpoint-restore-compression -r -d 'The compression of checkpoint archives and checkpoint images. Must be one of "none", "gzip" or "zstd".'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l checkpoint-restore-container-level-enabled -r -d 'The level of container checkpoint/restore support to enable. Must be one of "none", "checkpoint_only" or "checkpoint_restore". Enabling checkpoint or restore requires that the criu binary is available in $PATH.'
+complete -c crio -n '__fish_crio_no_subcommand' -l checkpoint-restore-encryption-keys-path -r -d 'Path to the directory containing the public keys and certificates for which checkpoint archives and pushed checkpoint images are encrypted. Checkpoints are not encrypted if empty. Encrypted checkpoints are decrypted using the keys of the decryption_keys_path.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l checkpoint-restore-pod-level-enabled -r -d 'The level of pod checkpoint/restore support to enable, which checkpoints all containers of a pod into one archive. Must be one of "none", "checkpoint_only" or "checkpoint_restore". Enabling checkpoint or restore requires that the criu binary is available in $PATH.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l checkpoint-restore-pre-dump-iterations -r -d 'Number of CRIU pre-dump iterations (0-10) copying the memory of a running container before its final checkpoint dump. Requires memory tracking support of the kernel.'
 complete -c crio -n '__fish_crio_no_subcommand' -l clean-shutdown-file -r -d 'Location for CRI-O to lay down the clean shutdown file. It indicates whether we\'ve had time to sync changes to disk before shutting down. If not found, crio wipe will clear the storage directory.'
 complete -c crio -n '__fish_crio_no_subcommand' -l cni-config-dir -r -d 'CNI configuration files directory.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l cni-default-network -r -d 'Name of the default CNI network to select. If not set or "", then CRI-O will pick-up the first one found in --cni-config-dir.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l cni-gc-timeout -r -d 'Maximum duration of the CNI GC operation cleaning up resources of stale pods.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l cni-plugin-dir -r -d 'CNI plugin binaries directory.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l cni-setup-timeout -r -d 'Maximum duration of the CNI ADD and CHECK operations when setting up a pod network. The timeout is added on top of the deadline of the RunPodSandbox request.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l cni-status-grace-period -r -d 'Enable continuous CNI STATUS monitoring with the given grace period. When set to 0 (default), monitoring is disabled and plugin health is only determined at startup. When set to a positive duration (e.g. 1m), a background goroutine polls the plugin every 5s and waits for this grace period before marking the node not-ready.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l cni-teardown-retries -r -d 'Number of retries for a failed CNI DEL operation (0-5). Retries are skipped if the pod network namespace does not exist any more.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l cni-teardown-timeout -r -d 'Maximum duration of a single CNI DEL operation when tearing down a pod network.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l collection-period -r -d 'The number of seconds between collecting pod/container stats and pod sandbox metrics. If set to 0, the metrics/stats are collected on-demand instead.'
 complete -c crio -n '__fish_crio_no_subcommand' -l config -s c -r -d 'Path to configuration file'
 complete -c crio -n '__fish_crio_no_subcommand' -l config-dir -s d -r -d 'Path to the configuration drop-in directory.
@@ -45,6 +56,8 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l conmon-cgroup -r -d 'cgrou
 complete -c crio -n '__fish_crio_no_subcommand' -f -l conmon-env -r -d 'Environment variable list for the conmon process, used for passing necessary environment variables to conmon or the runtime. This option is deprecated and will be removed in the future.'
 complete -c crio -n '__fish_crio_no_subcommand' -l container-attach-socket-dir -r -d 'Path to directory for container attach sockets.'
 complete -c crio -n '__fish_crio_no_subcommand' -l container-exits-dir -r -d 'Path to directory in which container exit files are written to by conmon.'
+complete -c crio -n '__fish_crio_no_subcommand' -l cpu-allocations-file -r -d 'Location CRI-O persists the exclusive CPUs allocated from the CPU allocator pool.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l cpu-allocator-pool -r -d 'CPUs set CRI-O allocates exclusive CPUs for containers of Guaranteed pods from, if the kubelet did not assign a cpuset. The allocator is disabled if empty.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l ctr-stop-timeout -r -d 'The minimal amount of time in seconds to wait before issuing a timeout regarding the proper termination of the container. The lowest possible value is 30s, whereas lower values are not considered by CRI-O.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l decryption-keys-path -r -d 'Path to load keys for image decryption.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l default-capabilities -r -d 'Capabilities to add to the containers.'
@@ -56,6 +69,10 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l default-transport -r -d 'A
 complete -c crio -n '__fish_crio_no_subcommand' -f -l default-ulimits -r -d 'Ulimits to apply to containers by default (name=soft:hard).'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l device-ownership-from-security-context -d 'Set devices\' uid/gid ownership from runAsUser/runAsGroup.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l disable-hostport-mapping -d 'If true, CRI-O would disable the hostport mapping.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l dns-cache-address -r -d 'Node local IP address of the caching DNS resolver for pods. The nameservers in the resolv.conf of non host network pods are replaced by this address. The resolver is disabled if empty.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l dns-cache-max-ttl -r -d 'Maximum duration an answer is kept by the DNS cache, regardless of its TTL.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l dns-cache-size -r -d 'Maximum number of answers kept by the DNS cache.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l dns-cache-upstreams -r -d 'Upstream nameservers (IP or IP:port) of the DNS cache for pods without own nameservers. Queries of unknown clients are dropped.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l drop-infra-ctr -d 'Determines whether pods are created without an infra container, when the pod is not using a pod level PID namespace.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-criu-support -d 'Enable CRIU integration, requires that the criu binary is available in $PATH. DEPRECATED: use the container_level_enabled option in the crio.checkpoint_restore table instead. When set to false it is translated to container_level_enabled = "none".'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-metrics -d 'Enable metrics endpoint for the server.'
@@ -161,6 +178,7 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l rdt-config-file -r -d 'Pat
 complete -c crio -n '__fish_crio_no_subcommand' -f -l read-only -d 'Setup all unprivileged containers to run as read-only. Automatically mounts the containers\' tmpfs on \'/run\', \'/tmp\' and \'/var/tmp\'.'
 complete -c crio -n '__fish_crio_no_subcommand' -l root -s r -r -d 'The CRI-O root directory.'
 complete -c crio -n '__fish_crio_no_subcommand' -l runroot -r -d 'The CRI-O state directory.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l runtime-tuning -r -d 'Language runtimes (jvm, node, dotnet) tuned using environment variables derived from the CPU and memory limits of the containers.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l runtimes -r -d 'OCI runtimes, format is \'runtime_name:runtime_path:runtime_root:runtime_type:privileged_without_host_devices:runtime_config_path:container_min_memory\'.'
 complete -c crio -n '__fish_crio_no_subcommand' -l seccomp-profile -r -d 'Path to the seccomp.json profile to be used as the runtime\'s default. If not specified, then the internal default seccomp profile will be used.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l selinux -d 'Enable selinux support. This option is deprecated, and be interpreted from whether SELinux is enabled on the host in the future.'
@@ -185,6 +203,7 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l tls-min-version -r -d 'Min
 complete -c crio -n '__fish_crio_no_subcommand' -f -l tracing-endpoint -r -d 'Address on which the gRPC tracing collector will listen.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l tracing-sampling-rate-per-million -r -d 'Number of samples to collect per million OpenTelemetry spans. Set to 1000000 to always sample.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l uid-mappings -r -d 'Specify the UID mappings to use for the user namespace. This option is deprecated, and will be replaced with Kubernetes user namespace support (KEP-127) in the future.'
+complete -c crio -n '__fish_crio_no_subcommand' -l userns-allocations-file -r -d 'Location CRI-O persists the user namespace ID ranges allocated from the userns pools.'
 complete -c crio -n '__fish_crio_no_subcommand' -l version-file -r -d 'Location for CRI-O to lay down the temporary version file. It is used to check if crio wipe should wipe containers, which should always happen on a node reboot.'
 complete -c crio -n '__fish_crio_no_subcommand' -l version-file-persist -r -d 'Location for CRI-O to lay down the persistent version file. It is used to check if crio wipe should wipe images, which should only happen when CRI-O has been upgraded.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l help -s h -d 'show help'
@@ -245,6 +264,10 @@ complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'goroutines g' -d
 complete -c crio -n '__fish_seen_subcommand_from heap hp' -f -l help -s h -d 'show help'
 complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'heap hp' -d 'Write the heap dump to a temp file and print its location on disk.'
 complete -c crio -n '__fish_seen_subcommand_from heap hp' -l file -s f -r -d 'Output file of the heap dump.'
+complete -c crio -n '__fish_seen_subcommand_from userns u' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'userns u' -d 'Display the user namespace ID pools, allocations and overlaps.'
+complete -c crio -n '__fish_seen_subcommand_from runtimes r' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'runtimes r' -d 'Display the runtime handlers and the features advertised by their runtimes.'
 complete -c crio -n '__fish_seen_subcommand_from version' -f -l help -s h -d 'show help'
 complete -r -c crio -n '__fish_crio_no_subcommand' -a 'version' -d 'display detailed version information'
 complete -c crio -n '__fish_seen_subcommand_from version' -f -l json -s j -d 'print JSON instead of text'
@@ -74,6 +74,12 @@ const (
 	// ResolvPath is the resolver configuration path annotation.
 	ResolvPath = "io.kubernetes.cri-o.ResolvPath"
 
+	// DNSCacheUpstreams is the upstream nameservers of the DNS cache for the pod annotation.
+	DNSCacheUpstreams = "io.kubernetes.cri-o.DNSCacheUpstreams"
+
+	// DNSConfig is the DNS configuration of the sandbox annotation.
+	DNSConfig = "io.kubernetes.cri-o.DNSConfig"
+
 	// HostnamePath is the path to /etc/hostname to bind mount annotation.
 	HostnamePath = "io.kubernetes.cri-o.HostnamePath"
 
@@ -110,6 +116,15 @@ const (
 	// CNIResult is the JSON string representation of the Result from CNI.
 	CNIResult = "io.kubernetes.cri-o.CNIResult"
 
+	// NUMAPlacement is the JSON string representation of the NUMA memory
+	// placement chosen for the container.
+	NUMAPlacement = "io.kubernetes.cri-o.NUMAPlacement"
+
+	// PodAccounting is the JSON string representation of the resource
+	// accounting of a pod without infra container, reported in the attributes
+	// of its pod sandbox stats.
+	PodAccounting = "io.kubernetes.cri-o.PodAccounting"
+
 	// ContainerManager is the annotation key for indicating the creator and
 	// manager of the container.
 	ContainerManager = "io.container.manager"
@@ -25,6 +25,8 @@ type CrioClient interface {
 	ConfigInfo(context.Context) (string, error)
 	GoRoutinesInfo(context.Context) (string, error)
 	HeapInfo(context.Context) ([]byte, error)
+	UsernsInfo(context.Context) (*types.UsernsInfo, error)
+	RuntimesInfo(context.Context) ([]types.RuntimeHandlerInfo, error)
 }
 
 type crioClientImpl struct {
@@ -148,3 +150,35 @@ func (c *crioClientImpl) HeapInfo(ctx context.Context) ([]byte, error) {
 
 	return body, nil
 }
+
+// UsernsInfo returns the user namespace pools and allocations by querying the
+// cri-o userns endpoint.
+func (c *crioClientImpl) UsernsInfo(ctx context.Context) (*types.UsernsInfo, error) {
+	body, err := c.doGetRequest(ctx, server.InspectUsernsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	info := types.UsernsInfo{}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// RuntimesInfo returns the runtime handlers and their features by querying
+// the cri-o runtimes endpoint.
+func (c *crioClientImpl) RuntimesInfo(ctx context.Context) ([]types.RuntimeHandlerInfo, error) {
+	body, err := c.doGetRequest(ctx, server.InspectRuntimesEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	info := []types.RuntimeHandlerInfo{}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
@@ -14,6 +14,13 @@ import (
 
 type PodNetworkLister func() ([]*ocicni.PodNetwork, error)
 
+// GCObserver gets notified about every plugin GC call, including its start
+// time and the resulting error.
+type GCObserver func(start time.Time, err error)
+
+// defaultGCTimeout is the timeout of a plugin GC call if not configured otherwise.
+const defaultGCTimeout = 30 * time.Second
+
 var errShutdown = errors.New("CNI manager shut down")
 
 type CNIManager struct {
@@ -27,6 +34,8 @@ type CNIManager struct {
 	monitorPollInterval time.Duration
 	gracePeriod         time.Duration
 	firstFailureTime    time.Time
+	gcTimeout           time.Duration
+	gcObserver          GCObserver
 
 	validPodList PodNetworkLister
 }
@@ -49,6 +58,7 @@ func New(defaultNetwork, networkDir string, gracePeriod time.Duration, pluginDir
 		initPollInterval:    500 * time.Millisecond,
 		monitorPollInterval: 5 * time.Second,
 		gracePeriod:         gracePeriod,
+		gcTimeout:           defaultGCTimeout,
 	}
 
 	go mgr.pollContinuously(ctx)
@@ -181,6 +191,23 @@ func (c *CNIManager) AddWatcher() chan bool {
 	return watcher
 }
 
+// SetGCTimeout sets the timeout for plugin GC calls.
+func (c *CNIManager) SetGCTimeout(timeout time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.gcTimeout = timeout
+}
+
+// SetGCObserver sets the observer which gets notified about every plugin GC
+// call, including the ones deferred until the plugin becomes ready.
+func (c *CNIManager) SetGCObserver(observer GCObserver) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.gcObserver = observer
+}
+
 // Shutdown shuts down the CNI manager, and notifies the watcher
 // that the CNI manager is not ready.
 func (c *CNIManager) Shutdown() {
@@ -235,9 +262,21 @@ func (c *CNIManager) doGC(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	// give a GC call 30s
-	stopCtx, stopCancel := context.WithTimeout(ctx, 30*time.Second)
+
+	timeout := c.gcTimeout
+	if timeout <= 0 {
+		timeout = defaultGCTimeout
+	}
+
+	stopCtx, stopCancel := context.WithTimeout(ctx, timeout)
 	defer stopCancel()
 
-	return c.plugin.GC(stopCtx, validPods)
+	start := time.Now()
+	err = c.plugin.GC(stopCtx, validPods)
+
+	if c.gcObserver != nil {
+		c.gcObserver(start, err)
+	}
+
+	return err
 }
@@ -17,9 +17,10 @@ const (
 )
 
 type fakeCNIPlugin struct {
-	mu        sync.Mutex
-	statusErr error
-	gcCalls   atomic.Int32
+	mu         sync.Mutex
+	statusErr  error
+	gcCalls    atomic.Int32
+	gcDeadline time.Time
 }
 
 func (f *fakeCNIPlugin) setStatusErr(err error) {
@@ -40,9 +41,14 @@ func (f *fakeCNIPlugin) Name() string                  { return "fake" }
 func (f *fakeCNIPlugin) GetDefaultNetworkName() string { return "fake-net" }
 func (f *fakeCNIPlugin) Shutdown() error               { return nil }
 
-func (f *fakeCNIPlugin) GC(context.Context, []*ocicni.PodNetwork) error {
+func (f *fakeCNIPlugin) GC(ctx context.Context, _ []*ocicni.PodNetwork) error {
 	f.gcCalls.Add(1)
 
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.gcDeadline, _ = ctx.Deadline()
+
 	return nil
 }
 
@@ -679,3 +685,69 @@ func TestGracePeriod(t *testing.T) {
 		})
 	})
 }
+
+func TestGCSettings(t *testing.T) {
+	t.Run("GC observer is notified", func(t *testing.T) {
+		fake := &fakeCNIPlugin{}
+
+		mgr := newTestManager(fake)
+		defer mgr.Shutdown()
+
+		waitFor(t, "ready", func() bool {
+			return mgr.ReadyOrError() == nil
+		})
+
+		var observed atomic.Int32
+
+		mgr.SetGCObserver(func(start time.Time, err error) {
+			if start.IsZero() {
+				t.Error("expected non-zero start time")
+			}
+
+			if err != nil {
+				t.Errorf("expected nil error, got: %v", err)
+			}
+
+			observed.Add(1)
+		})
+
+		if err := mgr.GC(context.Background(), func() ([]*ocicni.PodNetwork, error) {
+			return nil, nil
+		}); err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+
+		if calls := observed.Load(); calls != 1 {
+			t.Fatalf("expected 1 observed GC call, got %d", calls)
+		}
+	})
+
+	t.Run("GC uses configured timeout", func(t *testing.T) {
+		fake := &fakeCNIPlugin{}
+
+		mgr := newTestManager(fake)
+		defer mgr.Shutdown()
+
+		waitFor(t, "ready", func() bool {
+			return mgr.ReadyOrError() == nil
+		})
+
+		mgr.SetGCTimeout(time.Hour)
+
+		before := time.Now()
+
+		if err := mgr.GC(context.Background(), func() ([]*ocicni.PodNetwork, error) {
+			return nil, nil
+		}); err != nil {
+			t.Fatalf("expected nil error, got: %v", err)
+		}
+
+		fake.mu.Lock()
+		deadline := fake.gcDeadline
+		fake.mu.Unlock()
+
+		if deadline.Before(before.Add(time.Hour)) {
+			t.Fatalf("expected GC deadline after %v, got %v", before.Add(time.Hour), deadline)
+		}
+	})
+}
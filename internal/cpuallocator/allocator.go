@@ -0,0 +1,307 @@
+// Package cpuallocator implements an allocator for exclusive, NUMA aligned
+// CPUs of containers with a persistent allocation table.
+package cpuallocator
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/google/renameio"
+	"github.com/sirupsen/logrus"
+	"k8s.io/utils/cpuset"
+)
+
+// ErrPoolExhausted is returned if the pool has not enough free CPUs left.
+var ErrPoolExhausted = errors.New("CPU pool exhausted")
+
+// Allocation stores the exclusive CPUs of a container.
+type Allocation struct {
+	ContainerID string `json:"container_id"`
+	SandboxID   string `json:"sandbox_id"`
+	CPUs        string `json:"cpus"`
+}
+
+// state is the persisted allocation table.
+type state struct {
+	Allocations []*Allocation `json:"allocations"`
+}
+
+// Allocator allocates exclusive CPUs for containers from the configured pool
+// and keeps track of the CPUs used by all containers.
+type Allocator struct {
+	mutex       sync.Mutex
+	path        string
+	pool        cpuset.CPUSet
+	shared      cpuset.CPUSet
+	nodes       []cpuset.CPUSet
+	allocations map[string]*Allocation
+}
+
+// New creates a new allocator persisting its table at path. The CPUs of the
+// pool are grouped by the NUMA nodes of the topology. CPUs not part of any
+// node form an additional group. The online CPUs not part of the pool are
+// shared by all containers without exclusive CPUs.
+func New(path string, pool, online cpuset.CPUSet, topology Topology) (*Allocator, error) {
+	if pool.IsEmpty() {
+		return nil, errors.New("CPU pool has no CPUs")
+	}
+
+	shared := online.Difference(pool)
+	if shared.IsEmpty() {
+		return nil, fmt.Errorf("CPU pool %s leaves no online CPUs for containers without exclusive CPUs", pool)
+	}
+
+	a := &Allocator{
+		path:        path,
+		pool:        pool,
+		shared:      shared,
+		allocations: make(map[string]*Allocation),
+	}
+
+	remaining := pool
+	for _, id := range topology.Nodes() {
+		if cpus := topology[id].Intersection(pool); !cpus.IsEmpty() {
+			a.nodes = append(a.nodes, cpus)
+			remaining = remaining.Difference(cpus)
+		}
+	}
+
+	if !remaining.IsEmpty() {
+		a.nodes = append(a.nodes, remaining)
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read CPU allocations: %w", err)
+	}
+
+	var s state
+	if err := json.Unmarshal(content, &s); err != nil {
+		// The table gets rebuilt from the existing containers on restore.
+		logrus.Warnf("Ignoring corrupted CPU allocations %s: %v", path, err)
+
+		return a, nil
+	}
+
+	for _, allocation := range s.Allocations {
+		if _, err := cpuset.Parse(allocation.CPUs); err != nil {
+			logrus.Warnf("Ignoring CPU allocation of container %s: %v", allocation.ContainerID, err)
+
+			continue
+		}
+
+		a.allocations[allocation.ContainerID] = allocation
+	}
+
+	return a, nil
+}
+
+// Allocate allocates count exclusive CPUs for the container. The CPUs are
+// taken from a single NUMA node if possible, otherwise from as few nodes as
+// possible.
+func (a *Allocator) Allocate(containerID, sandboxID string, count int) (cpuset.CPUSet, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if count <= 0 {
+		return cpuset.New(), fmt.Errorf("invalid number of CPUs %d", count)
+	}
+
+	used := a.used(containerID)
+
+	free := make([]cpuset.CPUSet, 0, len(a.nodes))
+	total := 0
+
+	for _, node := range a.nodes {
+		cpus := node.Difference(used)
+		free = append(free, cpus)
+		total += cpus.Size()
+	}
+
+	if total < count {
+		return cpuset.New(), fmt.Errorf("%w: %d CPUs requested, %d available", ErrPoolExhausted, count, total)
+	}
+
+	res := a.fit(free, count)
+
+	a.allocations[containerID] = &Allocation{
+		ContainerID: containerID,
+		SandboxID:   sandboxID,
+		CPUs:        res.String(),
+	}
+
+	if err := a.save(); err != nil {
+		delete(a.allocations, containerID)
+
+		return cpuset.New(), err
+	}
+
+	return res, nil
+}
+
+// fit picks count CPUs from the free CPUs per node. The node with the least
+// free CPUs still fitting the request is preferred, so that large nodes stay
+// available for large requests. Requests not fitting a single node are spread
+// across the nodes with the most free CPUs.
+func (*Allocator) fit(free []cpuset.CPUSet, count int) cpuset.CPUSet {
+	best := -1
+
+	for i, cpus := range free {
+		if cpus.Size() >= count && (best < 0 || cpus.Size() < free[best].Size()) {
+			best = i
+		}
+	}
+
+	if best >= 0 {
+		return cpuset.New(free[best].List()[:count]...)
+	}
+
+	sorted := slices.Clone(free)
+	slices.SortStableFunc(sorted, func(x, y cpuset.CPUSet) int {
+		return cmp.Compare(y.Size(), x.Size())
+	})
+
+	res := []int{}
+
+	for _, cpus := range sorted {
+		take := min(count-len(res), cpus.Size())
+		res = append(res, cpus.List()[:take]...)
+
+		if len(res) == count {
+			break
+		}
+	}
+
+	return cpuset.New(res...)
+}
+
+// Shared returns the CPUs of containers without exclusive CPUs, which are the
+// online CPUs not part of the pool.
+func (a *Allocator) Shared() cpuset.CPUSet {
+	return a.shared
+}
+
+// Get returns the CPUs allocated for the container.
+func (a *Allocator) Get(containerID string) (cpuset.CPUSet, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	allocation, ok := a.allocations[containerID]
+	if !ok {
+		return cpuset.New(), false
+	}
+
+	cpus, err := cpuset.Parse(allocation.CPUs)
+	if err != nil {
+		return cpuset.New(), false
+	}
+
+	return cpus, true
+}
+
+// Release removes the allocation of the container. Releasing an unknown
+// container is a no-op.
+func (a *Allocator) Release(containerID string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if _, ok := a.allocations[containerID]; !ok {
+		return nil
+	}
+
+	delete(a.allocations, containerID)
+
+	return a.save()
+}
+
+// Restore reconciles the allocation table with the existing containers.
+// Allocations of removed containers are released.
+func (a *Allocator) Restore(containerIDs []string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	allocations := make(map[string]*Allocation, len(containerIDs))
+
+	for _, id := range containerIDs {
+		if allocation, ok := a.allocations[id]; ok {
+			allocations[id] = allocation
+		}
+	}
+
+	a.allocations = allocations
+
+	return a.save()
+}
+
+// Allocations returns the current allocations sorted by container ID.
+func (a *Allocator) Allocations() []Allocation {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	res := make([]Allocation, 0, len(a.allocations))
+	for _, allocation := range a.sortedAllocations() {
+		res = append(res, *allocation)
+	}
+
+	return res
+}
+
+// used returns the CPUs allocated for all containers except the given one.
+// The caller has to hold the mutex.
+func (a *Allocator) used(containerID string) cpuset.CPUSet {
+	res := cpuset.New()
+
+	for id, allocation := range a.allocations {
+		if id == containerID {
+			continue
+		}
+
+		// The CPUs are validated on load and allocation.
+		if cpus, err := cpuset.Parse(allocation.CPUs); err == nil {
+			res = res.Union(cpus)
+		}
+	}
+
+	return res
+}
+
+func (a *Allocator) sortedAllocations() []*Allocation {
+	res := make([]*Allocation, 0, len(a.allocations))
+	for _, allocation := range a.allocations {
+		res = append(res, allocation)
+	}
+
+	slices.SortFunc(res, func(x, y *Allocation) int {
+		return cmp.Compare(x.ContainerID, y.ContainerID)
+	})
+
+	return res
+}
+
+// save persists the allocation table. The caller has to hold the mutex.
+func (a *Allocator) save() error {
+	content, err := json.Marshal(&state{Allocations: a.sortedAllocations()})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(a.path), 0o700); err != nil {
+		return fmt.Errorf("create directory for CPU allocations: %w", err)
+	}
+
+	if err := renameio.WriteFile(a.path, content, 0o600); err != nil {
+		return fmt.Errorf("write CPU allocations: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+package cpuallocator_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/cpuset"
+
+	"github.com/cri-o/cri-o/internal/cpuallocator"
+)
+
+// The actual test suite.
+var _ = t.Describe("Allocator", func() {
+	var (
+		path     string
+		pool     cpuset.CPUSet
+		online   cpuset.CPUSet
+		topology cpuallocator.Topology
+		sut      *cpuallocator.Allocator
+	)
+
+	newAllocator := func() {
+		var err error
+
+		sut, err = cpuallocator.New(path, pool, online, topology)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		path = filepath.Join(t.MustTempDir("cpuallocator"), "allocations.json")
+		pool = cpuset.New(2, 3, 4, 5, 6, 7, 10, 11, 12, 13)
+		online = cpuset.New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15)
+		topology = cpuallocator.Topology{
+			0: cpuset.New(0, 1, 2, 3, 4, 5, 6, 7),
+			1: cpuset.New(8, 9, 10, 11, 12, 13, 14, 15),
+		}
+	})
+
+	t.Describe("New", func() {
+		It("should fail with empty pool", func() {
+			// Given
+			pool = cpuset.New()
+
+			// When
+			res, err := cpuallocator.New(path, pool, online, topology)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+
+		It("should fail if the pool contains all online CPUs", func() {
+			// Given
+			online = pool
+
+			// When
+			res, err := cpuallocator.New(path, pool, online, topology)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+
+		It("should share the online CPUs outside of the pool", func() {
+			// Given
+			// When
+			newAllocator()
+
+			// Then
+			Expect(sut.Shared().String()).To(Equal("0-1,8-9,14-15"))
+		})
+
+		It("should ignore corrupted allocations", func() {
+			// Given
+			Expect(os.WriteFile(path, []byte("{"), 0o600)).To(Succeed())
+
+			// When
+			newAllocator()
+
+			// Then
+			Expect(sut.Allocations()).To(BeEmpty())
+		})
+	})
+
+	t.Describe("Allocate", func() {
+		BeforeEach(func() {
+			newAllocator()
+		})
+
+		It("should prefer the smallest fitting NUMA node", func() {
+			// Given
+			// When
+			res, err := sut.Allocate("ctr", "sb", 2)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.String()).To(Equal("10-11"))
+		})
+
+		It("should keep the allocation on a single NUMA node", func() {
+			// Given
+			// When
+			res, err := sut.Allocate("ctr", "sb", 5)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.String()).To(Equal("2-6"))
+		})
+
+		It("should spread across NUMA nodes if required", func() {
+			// Given
+			// When
+			res, err := sut.Allocate("ctr", "sb", 8)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.String()).To(Equal("2-7,10-11"))
+		})
+
+		It("should not hand out allocated CPUs", func() {
+			// Given
+			first, err := sut.Allocate("first", "sb", 4)
+			Expect(err).NotTo(HaveOccurred())
+
+			// When
+			second, err := sut.Allocate("second", "sb", 4)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.Intersection(second).IsEmpty()).To(BeTrue())
+		})
+
+		It("should fail if the pool is exhausted", func() {
+			// Given
+			_, err := sut.Allocate("first", "sb", 8)
+			Expect(err).NotTo(HaveOccurred())
+
+			// When
+			_, err = sut.Allocate("second", "sb", 3)
+
+			// Then
+			Expect(errors.Is(err, cpuallocator.ErrPoolExhausted)).To(BeTrue())
+		})
+
+		It("should fail on invalid number of CPUs", func() {
+			// Given
+			// When
+			_, err := sut.Allocate("ctr", "sb", 0)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should work without NUMA topology", func() {
+			// Given
+			topology = cpuallocator.Topology{}
+			newAllocator()
+
+			// When
+			res, err := sut.Allocate("ctr", "sb", 3)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.String()).To(Equal("2-4"))
+		})
+	})
+
+	t.Describe("Release", func() {
+		BeforeEach(func() {
+			newAllocator()
+		})
+
+		It("should free the CPUs", func() {
+			// Given
+			_, err := sut.Allocate("first", "sb", 10)
+			Expect(err).NotTo(HaveOccurred())
+
+			// When
+			Expect(sut.Release("first")).To(Succeed())
+
+			// Then
+			_, ok := sut.Get("first")
+			Expect(ok).To(BeFalse())
+			_, err = sut.Allocate("second", "sb", 10)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should ignore unknown containers", func() {
+			// Given
+			// When
+			err := sut.Release("unknown")
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	t.Describe("Restore", func() {
+		It("should persist allocations across restarts", func() {
+			// Given
+			newAllocator()
+			allocated, err := sut.Allocate("ctr", "sb", 2)
+			Expect(err).NotTo(HaveOccurred())
+
+			// When
+			newAllocator()
+
+			// Then
+			res, ok := sut.Get("ctr")
+			Expect(ok).To(BeTrue())
+			Expect(res.Equals(allocated)).To(BeTrue())
+		})
+
+		It("should release allocations of removed containers", func() {
+			// Given
+			newAllocator()
+			_, err := sut.Allocate("kept", "sb", 2)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = sut.Allocate("removed", "sb", 2)
+			Expect(err).NotTo(HaveOccurred())
+			newAllocator()
+
+			// When
+			err = sut.Restore([]string{"kept"})
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sut.Allocations()).To(HaveLen(1))
+			Expect(sut.Allocations()[0].ContainerID).To(Equal("kept"))
+		})
+	})
+})
@@ -0,0 +1,30 @@
+package cpuallocator_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/cri-o/cri-o/test/framework"
+)
+
+// TestCPUAllocator runs the created specs.
+func TestCPUAllocator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunFrameworkSpecs(t, "CPUAllocator")
+}
+
+var t *TestFramework
+
+var _ = BeforeSuite(func() {
+	t = NewTestFramework(NilFunc, NilFunc)
+	t.Setup()
+
+	logrus.SetLevel(logrus.PanicLevel)
+})
+
+var _ = AfterSuite(func() {
+	t.Teardown()
+})
@@ -0,0 +1,101 @@
+package cpuallocator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/utils/cpuset"
+)
+
+const (
+	// SysNodeDir is the sysfs directory containing the NUMA nodes of the host.
+	SysNodeDir = "/sys/devices/system/node"
+
+	// SysOnlineCPUsFile is the sysfs file listing the online CPUs of the host.
+	SysOnlineCPUsFile = "/sys/devices/system/cpu/online"
+)
+
+// ReadOnlineCPUs reads the online CPUs of the host from the provided sysfs
+// file.
+func ReadOnlineCPUs(path string) (cpuset.CPUSet, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return cpuset.New(), fmt.Errorf("read online CPUs: %w", err)
+	}
+
+	cpus, err := cpuset.Parse(strings.TrimSpace(string(content)))
+	if err != nil {
+		return cpuset.New(), fmt.Errorf("parse online CPUs: %w", err)
+	}
+
+	return cpus, nil
+}
+
+// Topology maps the NUMA nodes of the host to their CPUs.
+type Topology map[int]cpuset.CPUSet
+
+// ReadTopology reads the NUMA topology from the sysfs node directory. An
+// empty topology is returned if the host does not expose NUMA information.
+func ReadTopology(dir string) (Topology, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return Topology{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read NUMA nodes: %w", err)
+	}
+
+	res := Topology{}
+
+	for _, entry := range entries {
+		id, err := strconv.Atoi(strings.TrimPrefix(entry.Name(), "node"))
+		if err != nil || !strings.HasPrefix(entry.Name(), "node") {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name(), "cpulist"))
+		if err != nil {
+			return nil, fmt.Errorf("read CPUs of NUMA node %d: %w", id, err)
+		}
+
+		cpus, err := cpuset.Parse(strings.TrimSpace(string(content)))
+		if err != nil {
+			return nil, fmt.Errorf("parse CPUs of NUMA node %d: %w", id, err)
+		}
+
+		res[id] = cpus
+	}
+
+	return res, nil
+}
+
+// Nodes returns the sorted IDs of the NUMA nodes.
+func (t Topology) Nodes() []int {
+	res := make([]int, 0, len(t))
+	for id := range t {
+		res = append(res, id)
+	}
+
+	slices.Sort(res)
+
+	return res
+}
+
+// NodesOf returns the sorted IDs of the NUMA nodes containing any of the CPUs.
+func (t Topology) NodesOf(cpus cpuset.CPUSet) []int {
+	res := []int{}
+
+	for _, id := range t.Nodes() {
+		if !t[id].Intersection(cpus).IsEmpty() {
+			res = append(res, id)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,105 @@
+package cpuallocator_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/cpuset"
+
+	"github.com/cri-o/cri-o/internal/cpuallocator"
+)
+
+// The actual test suite.
+var _ = t.Describe("Topology", func() {
+	var dir string
+
+	addNode := func(name, cpulist string) {
+		Expect(os.MkdirAll(filepath.Join(dir, name), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, name, "cpulist"), []byte(cpulist+"\n"), 0o644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		dir = t.MustTempDir("nodes")
+	})
+
+	t.Describe("ReadOnlineCPUs", func() {
+		It("should read the online CPUs", func() {
+			// Given
+			path := filepath.Join(dir, "online")
+			Expect(os.WriteFile(path, []byte("0-3,8\n"), 0o644)).To(Succeed())
+
+			// When
+			res, err := cpuallocator.ReadOnlineCPUs(path)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.String()).To(Equal("0-3,8"))
+		})
+
+		It("should fail if the file does not exist", func() {
+			// Given
+			// When
+			_, err := cpuallocator.ReadOnlineCPUs(filepath.Join(dir, "online"))
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	t.Describe("ReadTopology", func() {
+		It("should read the NUMA nodes", func() {
+			// Given
+			addNode("node0", "0-3")
+			addNode("node1", "4-7")
+			Expect(os.WriteFile(filepath.Join(dir, "possible"), []byte("0-1\n"), 0o644)).To(Succeed())
+
+			// When
+			res, err := cpuallocator.ReadTopology(dir)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Nodes()).To(Equal([]int{0, 1}))
+			Expect(res[1].String()).To(Equal("4-7"))
+		})
+
+		It("should return an empty topology without NUMA information", func() {
+			// Given
+			// When
+			res, err := cpuallocator.ReadTopology(filepath.Join(dir, "missing"))
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(BeEmpty())
+		})
+
+		It("should fail on invalid CPU lists", func() {
+			// Given
+			addNode("node0", "invalid")
+
+			// When
+			_, err := cpuallocator.ReadTopology(dir)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	t.Describe("NodesOf", func() {
+		It("should return the NUMA nodes of the CPUs", func() {
+			// Given
+			topology := cpuallocator.Topology{
+				0: cpuset.New(0, 1),
+				1: cpuset.New(2, 3),
+				2: cpuset.New(4, 5),
+			}
+
+			// When
+			res := topology.NodesOf(cpuset.New(1, 4))
+
+			// Then
+			Expect(res).To(Equal([]int{0, 2}))
+		})
+	})
+})
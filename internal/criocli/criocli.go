@@ -61,6 +61,34 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 		config.ContainerLevelEnabled = libconfig.ContainerCheckpointRestoreLevel(ctx.String("checkpoint-restore-container-level-enabled"))
 	}
 
+	if ctx.IsSet("checkpoint-restore-pod-level-enabled") {
+		config.PodLevelEnabled = libconfig.ContainerCheckpointRestoreLevel(ctx.String("checkpoint-restore-pod-level-enabled"))
+	}
+
+	if ctx.IsSet("checkpoint-restore-compression") {
+		config.CheckpointRestoreConfig.Compression = libconfig.CheckpointCompression(ctx.String("checkpoint-restore-compression"))
+	}
+
+	if ctx.IsSet("checkpoint-restore-pre-dump-iterations") {
+		config.PreDumpIterations = ctx.Int("checkpoint-restore-pre-dump-iterations")
+	}
+
+	if ctx.IsSet("checkpoint-restore-encryption-keys-path") {
+		config.EncryptionKeysPath = ctx.String("checkpoint-restore-encryption-keys-path")
+	}
+
+	if ctx.IsSet("checkpoint-restore-auto-checkpoint-directory") {
+		config.AutoCheckpointDirectory = ctx.String("checkpoint-restore-auto-checkpoint-directory")
+	}
+
+	if ctx.IsSet("checkpoint-restore-auto-checkpoint-drain-file") {
+		config.AutoCheckpointDrainFile = ctx.String("checkpoint-restore-auto-checkpoint-drain-file")
+	}
+
+	if ctx.IsSet("checkpoint-restore-auto-checkpoint-memory-pressure") {
+		config.AutoCheckpointMemoryPressure = ctx.Int("checkpoint-restore-auto-checkpoint-memory-pressure")
+	}
+
 	mergeNetworkConfig(config, ctx)
 	mergeAPIConfig(config, ctx)
 	mergeMetricsConfig(config, ctx)
@@ -293,6 +321,10 @@ func mergeRuntimeConfig(config *libconfig.Config, ctx *cli.Context) error {
 		config.MinInjectedGOMAXPROCS = ctx.Int64("min-injected-gomaxprocs")
 	}
 
+	if ctx.IsSet("runtime-tuning") {
+		config.RuntimeTuning = StringSliceTrySplit(ctx, "runtime-tuning")
+	}
+
 	if ctx.IsSet("default-sysctls") {
 		config.DefaultSysctls = StringSliceTrySplit(ctx, "default-sysctls")
 	}
@@ -364,6 +396,14 @@ func mergeRuntimeConfig(config *libconfig.Config, ctx *cli.Context) error {
 		config.SharedCPUSet = ctx.String("shared-cpuset")
 	}
 
+	if ctx.IsSet("cpu-allocator-pool") {
+		config.CPUAllocatorPool = ctx.String("cpu-allocator-pool")
+	}
+
+	if ctx.IsSet("cpu-allocations-file") {
+		config.CPUAllocationsFile = ctx.String("cpu-allocations-file")
+	}
+
 	// Container configuration
 	if ctx.IsSet("container-exits-dir") {
 		config.ContainerExitsDir = ctx.String("container-exits-dir")
@@ -398,6 +438,10 @@ func mergeRuntimeConfig(config *libconfig.Config, ctx *cli.Context) error {
 		config.MinimumMappableGID = ctx.Int64("minimum-mappable-gid")
 	}
 
+	if ctx.IsSet("userns-allocations-file") {
+		config.UsernsAllocationsFile = ctx.String("userns-allocations-file")
+	}
+
 	// Logging
 	if ctx.IsSet("log-level") {
 		config.LogLevel = ctx.String("log-level")
@@ -536,6 +580,38 @@ func mergeNetworkConfig(config *libconfig.Config, ctx *cli.Context) {
 	if ctx.IsSet("cni-status-grace-period") {
 		config.CNIStatusGracePeriod = ctx.Duration("cni-status-grace-period")
 	}
+
+	if ctx.IsSet("cni-setup-timeout") {
+		config.CNISetupTimeout = ctx.Duration("cni-setup-timeout")
+	}
+
+	if ctx.IsSet("cni-teardown-timeout") {
+		config.CNITeardownTimeout = ctx.Duration("cni-teardown-timeout")
+	}
+
+	if ctx.IsSet("cni-teardown-retries") {
+		config.CNITeardownRetries = ctx.Int("cni-teardown-retries")
+	}
+
+	if ctx.IsSet("cni-gc-timeout") {
+		config.CNIGCTimeout = ctx.Duration("cni-gc-timeout")
+	}
+
+	if ctx.IsSet("dns-cache-address") {
+		config.DNSCacheAddress = ctx.String("dns-cache-address")
+	}
+
+	if ctx.IsSet("dns-cache-upstreams") {
+		config.DNSCacheUpstreams = StringSliceTrySplit(ctx, "dns-cache-upstreams")
+	}
+
+	if ctx.IsSet("dns-cache-size") {
+		config.DNSCacheSize = ctx.Int("dns-cache-size")
+	}
+
+	if ctx.IsSet("dns-cache-max-ttl") {
+		config.DNSCacheMaxTTL = ctx.Duration("dns-cache-max-ttl")
+	}
 }
 
 // mergeAPIConfig merges APIConfig-related CLI flags into the config, including gRPC and streaming settings.
@@ -1037,6 +1113,54 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Value:   defConf.CNIStatusGracePeriod,
 			EnvVars: []string{"CNI_STATUS_GRACE_PERIOD"},
 		},
+		&cli.DurationFlag{
+			Name:    "cni-setup-timeout",
+			Usage:   "Maximum duration of the CNI ADD and CHECK operations when setting up a pod network. The timeout is added on top of the deadline of the RunPodSandbox request.",
+			Value:   defConf.CNISetupTimeout,
+			EnvVars: []string{"CONTAINER_CNI_SETUP_TIMEOUT"},
+		},
+		&cli.DurationFlag{
+			Name:    "cni-teardown-timeout",
+			Usage:   "Maximum duration of a single CNI DEL operation when tearing down a pod network.",
+			Value:   defConf.CNITeardownTimeout,
+			EnvVars: []string{"CONTAINER_CNI_TEARDOWN_TIMEOUT"},
+		},
+		&cli.IntFlag{
+			Name:    "cni-teardown-retries",
+			Usage:   "Number of retries for a failed CNI DEL operation (0-5). Retries are skipped if the pod network namespace does not exist any more.",
+			Value:   defConf.CNITeardownRetries,
+			EnvVars: []string{"CONTAINER_CNI_TEARDOWN_RETRIES"},
+		},
+		&cli.DurationFlag{
+			Name:    "cni-gc-timeout",
+			Usage:   "Maximum duration of the CNI GC operation cleaning up resources of stale pods.",
+			Value:   defConf.CNIGCTimeout,
+			EnvVars: []string{"CONTAINER_CNI_GC_TIMEOUT"},
+		},
+		&cli.StringFlag{
+			Name:    "dns-cache-address",
+			Usage:   "Node local IP address of the caching DNS resolver for pods. The nameservers in the resolv.conf of non host network pods are replaced by this address. The resolver is disabled if empty.",
+			Value:   defConf.DNSCacheAddress,
+			EnvVars: []string{"CONTAINER_DNS_CACHE_ADDRESS"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "dns-cache-upstreams",
+			Usage:   "Upstream nameservers (IP or IP:port) of the DNS cache for pods without own nameservers. Queries of unknown clients are dropped.",
+			Value:   cli.NewStringSlice(defConf.DNSCacheUpstreams...),
+			EnvVars: []string{"CONTAINER_DNS_CACHE_UPSTREAMS"},
+		},
+		&cli.IntFlag{
+			Name:    "dns-cache-size",
+			Usage:   "Maximum number of answers kept by the DNS cache.",
+			Value:   defConf.DNSCacheSize,
+			EnvVars: []string{"CONTAINER_DNS_CACHE_SIZE"},
+		},
+		&cli.DurationFlag{
+			Name:    "dns-cache-max-ttl",
+			Usage:   "Maximum duration an answer is kept by the DNS cache, regardless of its TTL.",
+			Value:   defConf.DNSCacheMaxTTL,
+			EnvVars: []string{"CONTAINER_DNS_CACHE_MAX_TTL"},
+		},
 		&cli.StringFlag{
 			Name:  "image-volumes",
 			Value: string(libconfig.ImageVolumesMkdir),
@@ -1315,6 +1439,13 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Value:   defConf.MinimumMappableGID,
 			EnvVars: []string{"CONTAINER_MINIMUM_MAPPABLE_GID"},
 		},
+		&cli.StringFlag{
+			Name:      "userns-allocations-file",
+			Usage:     "Location CRI-O persists the user namespace ID ranges allocated from the userns pools.",
+			Value:     defConf.UsernsAllocationsFile,
+			EnvVars:   []string{"CONTAINER_USERNS_ALLOCATIONS_FILE"},
+			TakesFile: true,
+		},
 		&cli.StringSliceFlag{
 			Name:    "allowed-devices",
 			Usage:   "Devices a user is allowed to specify with the \"devices.crio.io\" allowed annotation.",
@@ -1356,6 +1487,12 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Usage:   "Enable GOMAXPROCS injection. Burstable pods auto-calculate from CPU request, with this value as the minimum floor. Best-effort pods use this value directly. 0 to disable.",
 			EnvVars: []string{"CONTAINER_INJECT_GOMAXPROCS"},
 		},
+		&cli.StringSliceFlag{
+			Name:    "runtime-tuning",
+			Value:   cli.NewStringSlice(defConf.RuntimeTuning...),
+			Usage:   "Language runtimes (jvm, node, dotnet) tuned using environment variables derived from the CPU and memory limits of the containers.",
+			EnvVars: []string{"CONTAINER_RUNTIME_TUNING"},
+		},
 		&cli.StringFlag{
 			Name:      "container-attach-socket-dir",
 			Usage:     "Path to directory for container attach sockets.",
@@ -1519,6 +1656,19 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"CONTAINER_SHARED_CPUSET"},
 			Value:   defConf.SharedCPUSet,
 		},
+		&cli.StringFlag{
+			Name:    "cpu-allocator-pool",
+			Usage:   "CPUs set CRI-O allocates exclusive CPUs for containers of Guaranteed pods from, if the kubelet did not assign a cpuset. The allocator is disabled if empty.",
+			EnvVars: []string{"CONTAINER_CPU_ALLOCATOR_POOL"},
+			Value:   defConf.CPUAllocatorPool,
+		},
+		&cli.StringFlag{
+			Name:      "cpu-allocations-file",
+			Usage:     "Location CRI-O persists the exclusive CPUs allocated from the CPU allocator pool.",
+			Value:     defConf.CPUAllocationsFile,
+			EnvVars:   []string{"CONTAINER_CPU_ALLOCATIONS_FILE"},
+			TakesFile: true,
+		},
 		&cli.StringFlag{
 			Name:      "clean-shutdown-file",
 			Usage:     "Location for CRI-O to lay down the clean shutdown file. It indicates whether we've had time to sync changes to disk before shutting down. If not found, crio wipe will clear the storage directory.",
@@ -1566,6 +1716,51 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"CONTAINER_CHECKPOINT_RESTORE_CONTAINER_LEVEL_ENABLED"},
 			Value:   string(defConf.ContainerLevelEnabled),
 		},
+		&cli.StringFlag{
+			Name:    "checkpoint-restore-pod-level-enabled",
+			Usage:   "The level of pod checkpoint/restore support to enable, which checkpoints all containers of a pod into one archive. Must be one of \"none\", \"checkpoint_only\" or \"checkpoint_restore\". Enabling checkpoint or restore requires that the criu binary is available in $PATH.",
+			EnvVars: []string{"CONTAINER_CHECKPOINT_RESTORE_POD_LEVEL_ENABLED"},
+			Value:   string(defConf.PodLevelEnabled),
+		},
+		&cli.StringFlag{
+			Name:    "checkpoint-restore-compression",
+			Usage:   "The compression of checkpoint archives and checkpoint images. Must be one of \"none\", \"gzip\" or \"zstd\".",
+			EnvVars: []string{"CONTAINER_CHECKPOINT_RESTORE_COMPRESSION"},
+			Value:   string(defConf.CheckpointRestoreConfig.Compression),
+		},
+		&cli.IntFlag{
+			Name:    "checkpoint-restore-pre-dump-iterations",
+			Usage:   "Number of CRIU pre-dump iterations (0-10) copying the memory of a running container before its final checkpoint dump. Requires memory tracking support of the kernel.",
+			EnvVars: []string{"CONTAINER_CHECKPOINT_RESTORE_PRE_DUMP_ITERATIONS"},
+			Value:   defConf.PreDumpIterations,
+		},
+		&cli.StringFlag{
+			Name:      "checkpoint-restore-encryption-keys-path",
+			Usage:     "Path to the directory containing the public keys and certificates for which checkpoint archives and pushed checkpoint images are encrypted. Checkpoints are not encrypted if empty. Encrypted checkpoints are decrypted using the keys of the decryption_keys_path.",
+			EnvVars:   []string{"CONTAINER_CHECKPOINT_RESTORE_ENCRYPTION_KEYS_PATH"},
+			Value:     defConf.EncryptionKeysPath,
+			TakesFile: true,
+		},
+		&cli.StringFlag{
+			Name:      "checkpoint-restore-auto-checkpoint-directory",
+			Usage:     "Directory into which containers of pods with the \"auto-checkpoint.crio.io\" annotation are checkpointed automatically. Automatic checkpoints are disabled if empty.",
+			EnvVars:   []string{"CONTAINER_CHECKPOINT_RESTORE_AUTO_CHECKPOINT_DIRECTORY"},
+			Value:     defConf.AutoCheckpointDirectory,
+			TakesFile: true,
+		},
+		&cli.StringFlag{
+			Name:      "checkpoint-restore-auto-checkpoint-drain-file",
+			Usage:     "File indicating that the node gets drained. If it exists when CRI-O receives SIGTERM, containers of pods with the \"drain\" automatic checkpoint policy are checkpointed.",
+			EnvVars:   []string{"CONTAINER_CHECKPOINT_RESTORE_AUTO_CHECKPOINT_DRAIN_FILE"},
+			Value:     defConf.AutoCheckpointDrainFile,
+			TakesFile: true,
+		},
+		&cli.IntFlag{
+			Name:    "checkpoint-restore-auto-checkpoint-memory-pressure",
+			Usage:   "Memory pressure threshold in percent (1-100) of the last 10 seconds, above which containers of pods with the \"memory-pressure\" automatic checkpoint policy are checkpointed.",
+			EnvVars: []string{"CONTAINER_CHECKPOINT_RESTORE_AUTO_CHECKPOINT_MEMORY_PRESSURE"},
+			Value:   defConf.AutoCheckpointMemoryPressure,
+		},
 		&cli.BoolFlag{
 			Name:    "enable-pod-events",
 			Usage:   "If true, CRI-O starts sending the container events to the kubelet",
@@ -1,7 +1,28 @@
 package criocli
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/containerd/containerd/api/events"
 	"github.com/urfave/cli/v2"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/cri-o/cri-o/internal/oci"
+)
+
+const (
+	// taskExitTopic is the topic of the events published by shimv2 runtimes
+	// once a task exits.
+	taskExitTopic = "/tasks/exit"
+
+	// taskOOMTopic is the topic of the events published by shimv2 runtimes
+	// once a task got OOM killed.
+	taskOOMTopic = "/tasks/oom"
 )
 
 var PublishCommand = &cli.Command{
@@ -20,6 +41,76 @@ var PublishCommand = &cli.Command{
 	HideHelp: true,
 	Hidden:   true,
 	Action: func(c *cli.Context) error {
-		return nil
+		// The address is the container exits directory for shimv2 runtimes,
+		// while other events than exits and OOM kills are not of interest.
+		exitsDir := c.String("address")
+		topic := c.String("topic")
+
+		if exitsDir == "" || (topic != taskExitTopic && topic != taskOOMTopic) {
+			return nil
+		}
+
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("read event: %w", err)
+		}
+
+		return PublishTaskEvent(exitsDir, topic, data)
 	},
 }
+
+// PublishTaskEvent writes the exit file of the container into the exitsDir
+// if the marshaled event is the exit of its init process, and the OOM file
+// of the container if it is an OOM kill.
+func PublishTaskEvent(exitsDir, topic string, data []byte) error {
+	event := &anypb.Any{}
+	if err := proto.Unmarshal(data, event); err != nil {
+		return fmt.Errorf("unmarshal event: %w", err)
+	}
+
+	switch topic {
+	case taskExitTopic:
+		taskExit := &events.TaskExit{}
+		if err := event.UnmarshalTo(taskExit); err != nil {
+			return fmt.Errorf("unmarshal task exit event: %w", err)
+		}
+
+		// Exec processes have their own ID.
+		if taskExit.GetID() != "" && taskExit.GetID() != taskExit.GetContainerID() {
+			return nil
+		}
+
+		if err := validateEventContainerID(taskExit.GetContainerID()); err != nil {
+			return err
+		}
+
+		path := filepath.Join(exitsDir, taskExit.GetContainerID())
+		if err := os.WriteFile(path, []byte(strconv.FormatUint(uint64(taskExit.GetExitStatus()), 10)), 0o644); err != nil {
+			return fmt.Errorf("write exit file: %w", err)
+		}
+
+	case taskOOMTopic:
+		taskOOM := &events.TaskOOM{}
+		if err := event.UnmarshalTo(taskOOM); err != nil {
+			return fmt.Errorf("unmarshal task OOM event: %w", err)
+		}
+
+		if err := validateEventContainerID(taskOOM.GetContainerID()); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(oci.ShimOOMFilePath(exitsDir, taskOOM.GetContainerID()), nil, 0o644); err != nil {
+			return fmt.Errorf("write OOM file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func validateEventContainerID(id string) error {
+	if id == "" || filepath.Base(id) != id {
+		return fmt.Errorf("invalid container ID %q in task event", id)
+	}
+
+	return nil
+}
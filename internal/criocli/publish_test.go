@@ -0,0 +1,95 @@
+package criocli_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/containerd/api/events"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/cri-o/cri-o/internal/criocli"
+	"github.com/cri-o/cri-o/internal/oci"
+)
+
+// The actual test suite.
+var _ = t.Describe("Publish", func() {
+	const containerID = "container"
+
+	var exitsDir string
+
+	marshal := func(event proto.Message) []byte {
+		a, err := anypb.New(event)
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := proto.Marshal(a)
+		Expect(err).NotTo(HaveOccurred())
+
+		return data
+	}
+
+	BeforeEach(func() {
+		exitsDir = t.MustTempDir("exits")
+	})
+
+	It("should write the exit file of a task exit", func() {
+		// Given
+		data := marshal(&events.TaskExit{ContainerID: containerID, ID: containerID, ExitStatus: 137})
+
+		// When
+		err := criocli.PublishTaskEvent(exitsDir, "/tasks/exit", data)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.ReadFile(filepath.Join(exitsDir, containerID))).To(BeEquivalentTo("137"))
+	})
+
+	It("should ignore the exit of an exec process", func() {
+		// Given
+		data := marshal(&events.TaskExit{ContainerID: containerID, ID: "exec"})
+
+		// When
+		err := criocli.PublishTaskEvent(exitsDir, "/tasks/exit", data)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filepath.Join(exitsDir, containerID)).NotTo(BeAnExistingFile())
+	})
+
+	It("should write the OOM file of a task OOM kill", func() {
+		// Given
+		data := marshal(&events.TaskOOM{ContainerID: containerID})
+
+		// When
+		err := criocli.PublishTaskEvent(exitsDir, "/tasks/oom", data)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(oci.ShimOOMFilePath(exitsDir, containerID)).To(BeAnExistingFile())
+		Expect(filepath.Join(exitsDir, containerID)).NotTo(BeAnExistingFile())
+	})
+
+	It("should fail with an invalid container ID", func() {
+		// Given
+		data := marshal(&events.TaskOOM{ContainerID: "../container"})
+
+		// When
+		err := criocli.PublishTaskEvent(exitsDir, "/tasks/oom", data)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail with an event not matching the topic", func() {
+		// Given
+		data := marshal(&events.TaskOOM{ContainerID: containerID})
+
+		// When
+		err := criocli.PublishTaskEvent(exitsDir, "/tasks/exit", data)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+	})
+})
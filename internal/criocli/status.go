@@ -68,6 +68,16 @@ var StatusCommand = &cli.Command{
 				TakesFile: true,
 			},
 		},
+	}, {
+		Action:  usernsSubCommand,
+		Aliases: []string{"u"},
+		Name:    "userns",
+		Usage:   "Display the user namespace ID pools, allocations and overlaps.",
+	}, {
+		Action:  runtimesSubCommand,
+		Aliases: []string{"r"},
+		Name:    "runtimes",
+		Usage:   "Display the runtime handlers and the features advertised by their runtimes.",
 	}},
 }
 
@@ -223,3 +233,96 @@ func heap(c *cli.Context) error {
 
 	return nil
 }
+
+func usernsSubCommand(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	info, err := crioClient.UsernsInfo(c.Context)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("pools (format <name>: <host>:<size>, <allocated> allocated):\n")
+
+	for _, p := range info.Pools {
+		fmt.Printf("  %s: %d:%d, %d allocated\n", p.Name, p.HostID, p.Size, p.Allocated)
+	}
+
+	fmt.Printf("allocations (format <host>:<size>):\n")
+
+	for _, a := range info.Allocations {
+		pool := a.Pool
+		if pool == "" {
+			pool = "<none>"
+		}
+
+		fmt.Printf("  %s (%s/%s, pool %s):\n", a.SandboxID, a.Namespace, a.Name, pool)
+
+		for _, r := range a.Uids {
+			fmt.Printf("    uids: %d:%d\n", r.HostID, r.Size)
+		}
+
+		for _, r := range a.Gids {
+			fmt.Printf("    gids: %d:%d\n", r.HostID, r.Size)
+		}
+	}
+
+	fmt.Printf("overlaps:\n")
+
+	for _, o := range info.Overlaps {
+		fmt.Printf("  %s (uids: %v, gids: %v)\n", strings.Join(o.SandboxIDs, ", "), o.Uids, o.Gids)
+	}
+
+	return nil
+}
+
+func runtimesSubCommand(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	info, err := crioClient.RuntimesInfo(c.Context)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range info {
+		name := h.Name
+		if h.Default {
+			name += " (default)"
+		}
+
+		fmt.Printf("%s:\n", name)
+		fmt.Printf("  runtime path: %s\n", h.RuntimePath)
+		fmt.Printf("  runtime type: %s\n", h.RuntimeType)
+		fmt.Printf("  recursive read-only mounts: %v\n", h.RecursiveReadOnlyMounts)
+		fmt.Printf("  user namespaces: %v\n", h.UserNamespaces)
+
+		if h.Features == nil {
+			fmt.Printf("  features: <unknown>\n")
+
+			continue
+		}
+
+		fmt.Printf("  OCI version: %s - %s\n", h.Features.OCIVersionMin, h.Features.OCIVersionMax)
+		fmt.Printf("  mount options: %s\n", strings.Join(h.Features.MountOptions, ", "))
+
+		if h.Features.Linux == nil {
+			continue
+		}
+
+		fmt.Printf("  namespaces: %s\n", strings.Join(h.Features.Linux.Namespaces, ", "))
+
+		if seccomp := h.Features.Linux.Seccomp; seccomp != nil {
+			fmt.Printf("  seccomp actions: %s\n", strings.Join(seccomp.Actions, ", "))
+			fmt.Printf("  seccomp architectures: %s\n", strings.Join(seccomp.Archs, ", "))
+			fmt.Printf("  seccomp flags: %s\n", strings.Join(seccomp.SupportedFlags, ", "))
+		}
+	}
+
+	return nil
+}
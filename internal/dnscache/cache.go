@@ -0,0 +1,206 @@
+package dnscache
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// cacheKey identifies a cached DNS answer.
+type cacheKey struct {
+	name      string
+	qtype     dnsmessage.Type
+	qclass    dnsmessage.Class
+	upstreams string
+}
+
+// cacheEntry is a single cached DNS answer.
+type cacheEntry struct {
+	key     cacheKey
+	msg     dnsmessage.Message
+	stored  time.Time
+	expires time.Time
+}
+
+// cache is a size bounded LRU cache for DNS answers.
+type cache struct {
+	mutex   sync.Mutex
+	size    int
+	entries map[cacheKey]*list.Element
+	lru     *list.List
+}
+
+func newCache(size int) *cache {
+	return &cache{
+		size:    size,
+		entries: make(map[cacheKey]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// get returns a copy of the cached message for the key with all TTLs reduced
+// by the time the message spent in the cache. It returns false if the key is
+// not cached or the entry is expired.
+func (c *cache) get(key cacheKey, now time.Time) (dnsmessage.Message, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return dnsmessage.Message{}, false
+	}
+
+	entry, ok := elem.Value.(*cacheEntry)
+	if !ok {
+		return dnsmessage.Message{}, false
+	}
+
+	if !now.Before(entry.expires) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+
+		return dnsmessage.Message{}, false
+	}
+
+	c.lru.MoveToFront(elem)
+
+	elapsed := uint32(now.Sub(entry.stored) / time.Second)
+	msg := entry.msg
+	msg.Questions = slices.Clone(entry.msg.Questions)
+	msg.Answers = decreaseTTLs(entry.msg.Answers, elapsed)
+	msg.Authorities = decreaseTTLs(entry.msg.Authorities, elapsed)
+	msg.Additionals = decreaseTTLs(entry.msg.Additionals, elapsed)
+
+	return msg, true
+}
+
+// put stores the message for the key for the provided TTL and evicts the
+// least recently used entry if the cache is full.
+func (c *cache) put(key cacheKey, msg *dnsmessage.Message, ttl time.Duration, now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry := &cacheEntry{
+		key:     key,
+		msg:     *msg,
+		stored:  now,
+		expires: now.Add(ttl),
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		if oldest == nil {
+			break
+		}
+
+		c.lru.Remove(oldest)
+
+		if e, ok := oldest.Value.(*cacheEntry); ok {
+			delete(c.entries, e.key)
+		}
+	}
+}
+
+// len returns the number of cached entries.
+func (c *cache) len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.lru.Len()
+}
+
+// decreaseTTLs returns a copy of the resources with their TTLs reduced by
+// the provided amount of seconds. The TTL of OPT pseudo records is not
+// touched because it carries the extended RCODE and flags.
+func decreaseTTLs(resources []dnsmessage.Resource, elapsed uint32) []dnsmessage.Resource {
+	res := slices.Clone(resources)
+
+	for i := range res {
+		if res[i].Header.Type == dnsmessage.TypeOPT {
+			continue
+		}
+
+		if res[i].Header.TTL > elapsed {
+			res[i].Header.TTL -= elapsed
+		} else {
+			res[i].Header.TTL = 0
+		}
+	}
+
+	return res
+}
+
+// cacheTTL returns the duration the message can be cached, capped to maxTTL.
+// Only successful answers and negative answers carrying a SOA record are
+// cacheable, a zero duration is returned otherwise.
+func cacheTTL(msg *dnsmessage.Message, maxTTL time.Duration) time.Duration {
+	if msg.Truncated {
+		return 0
+	}
+
+	var (
+		ttl   uint32
+		found bool
+	)
+
+	minTTL := func(t uint32) {
+		if !found || t < ttl {
+			ttl = t
+			found = true
+		}
+	}
+
+	switch msg.RCode {
+	case dnsmessage.RCodeSuccess:
+		for _, sections := range [][]dnsmessage.Resource{msg.Answers, msg.Authorities, msg.Additionals} {
+			for i := range sections {
+				if sections[i].Header.Type == dnsmessage.TypeOPT {
+					continue
+				}
+
+				minTTL(sections[i].Header.TTL)
+			}
+		}
+
+		if len(msg.Answers) > 0 {
+			break
+		}
+
+		// NODATA answers are cached like NXDOMAIN ones.
+		fallthrough
+
+	case dnsmessage.RCodeNameError:
+		found = false
+
+		for i := range msg.Authorities {
+			soa, ok := msg.Authorities[i].Body.(*dnsmessage.SOAResource)
+			if !ok {
+				continue
+			}
+
+			minTTL(msg.Authorities[i].Header.TTL)
+			minTTL(soa.MinTTL)
+		}
+
+	default:
+		return 0
+	}
+
+	if !found {
+		return 0
+	}
+
+	return min(time.Duration(ttl)*time.Second, maxTTL)
+}
@@ -0,0 +1,636 @@
+// Package dnscache implements a node-local caching DNS stub resolver for pods.
+package dnscache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/cri-o/cri-o/server/metrics"
+)
+
+const (
+	// dnsPort is the port the resolver listens on. resolv.conf does not
+	// support any other port for nameservers.
+	dnsPort = "53"
+
+	// upstreamTimeout is the timeout for a single upstream exchange.
+	upstreamTimeout = 2 * time.Second
+
+	// tcpIdleTimeout is the timeout for idle client TCP connections.
+	tcpIdleTimeout = 10 * time.Second
+
+	// maxMessageSize is the maximum size of a DNS message.
+	maxMessageSize = 65535
+
+	// defaultUDPSize is the maximum UDP response size for clients not
+	// announcing a different size using EDNS(0).
+	defaultUDPSize = 512
+
+	// maxConcurrentQueries is the maximum number of UDP queries being
+	// resolved at the same time. Further queries are dropped.
+	maxConcurrentQueries = 256
+
+	// maxTCPConnections is the maximum number of open client TCP
+	// connections. Further connections are closed immediately.
+	maxTCPConnections = 64
+
+	// Query results used for metrics.
+	resultHit   = "hit"
+	resultMiss  = "miss"
+	resultError = "error"
+)
+
+// Resolver is a caching DNS stub resolver listening on a node local address.
+// Queries are attributed to pods by their source IP and forwarded to the
+// upstream nameservers of the pod. Queries of unknown clients are dropped.
+type Resolver struct {
+	address   string
+	port      string
+	upstreams []string
+	maxTTL    time.Duration
+	cache     *cache
+
+	mutex  sync.RWMutex
+	pods   map[string]*pod
+	podIPs map[netip.Addr]*pod
+
+	udpConn     net.PacketConn
+	tcpListener net.Listener
+	queries     chan struct{}
+	tcpConns    chan struct{}
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+// pod is a registered pod sandbox.
+type pod struct {
+	id        string
+	namespace string
+	name      string
+	ips       []netip.Addr
+	upstreams []string
+}
+
+// New creates a new resolver for the provided listen address. The upstreams
+// are used for queries of registered pods without own upstream nameservers.
+func New(address string, upstreams []string, size int, maxTTL time.Duration) (*Resolver, error) {
+	if net.ParseIP(address) == nil {
+		return nil, fmt.Errorf("invalid DNS cache address %q", address)
+	}
+
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid DNS cache size %d", size)
+	}
+
+	normalized, err := NormalizeUpstreams(upstreams)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Resolver{
+		address:   address,
+		port:      dnsPort,
+		upstreams: normalized,
+		maxTTL:    maxTTL,
+		cache:     newCache(size),
+		pods:      make(map[string]*pod),
+		podIPs:    make(map[netip.Addr]*pod),
+		queries:   make(chan struct{}, maxConcurrentQueries),
+		tcpConns:  make(chan struct{}, maxTCPConnections),
+	}, nil
+}
+
+// ErrInvalidUpstream is returned if an upstream nameserver is no IP or
+// IP:port.
+var ErrInvalidUpstream = errors.New("invalid DNS upstream")
+
+// NormalizeUpstreams validates the provided upstream nameservers and adds
+// the default DNS port to entries without a port.
+func NormalizeUpstreams(upstreams []string) ([]string, error) {
+	res := make([]string, 0, len(upstreams))
+
+	for _, upstream := range upstreams {
+		if ip := net.ParseIP(upstream); ip != nil {
+			res = append(res, net.JoinHostPort(ip.String(), dnsPort))
+
+			continue
+		}
+
+		host, port, err := net.SplitHostPort(upstream)
+		if err != nil || net.ParseIP(host) == nil || port == "" {
+			return nil, fmt.Errorf("%w %q: must be an IP or IP:port", ErrInvalidUpstream, upstream)
+		}
+
+		res = append(res, upstream)
+	}
+
+	return res, nil
+}
+
+// Address returns the address the resolver listens on.
+func (r *Resolver) Address() string {
+	return r.address
+}
+
+// Start starts serving DNS queries via UDP and TCP in the background.
+func (r *Resolver) Start(ctx context.Context) error {
+	listenAddress := net.JoinHostPort(r.address, r.port)
+
+	udpConn, err := net.ListenPacket("udp", listenAddress)
+	if err != nil {
+		return fmt.Errorf("listen on udp %s: %w", listenAddress, err)
+	}
+
+	tcpListener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		udpConn.Close()
+
+		return fmt.Errorf("listen on tcp %s: %w", listenAddress, err)
+	}
+
+	r.udpConn = udpConn
+	r.tcpListener = tcpListener
+
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	r.wg.Add(2)
+
+	go r.serveUDP(ctx)
+	go r.serveTCP(ctx)
+
+	logrus.Infof("Serving DNS cache on %s", listenAddress)
+
+	return nil
+}
+
+// Shutdown stops serving DNS queries and waits for in-flight queries to
+// finish.
+func (r *Resolver) Shutdown() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	if r.udpConn != nil {
+		r.udpConn.Close()
+	}
+
+	if r.tcpListener != nil {
+		r.tcpListener.Close()
+	}
+
+	r.wg.Wait()
+}
+
+// Register registers a pod sandbox by its IPs, so that its queries are
+// forwarded to the provided upstreams and accounted to the pod in the
+// metrics. An already registered pod sandbox gets updated.
+func (r *Resolver) Register(id, namespace, name string, ips, upstreams []string) error {
+	normalized, err := NormalizeUpstreams(upstreams)
+	if err != nil {
+		return err
+	}
+
+	p := &pod{
+		id:        id,
+		namespace: namespace,
+		name:      name,
+		upstreams: normalized,
+	}
+
+	for _, ip := range ips {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return fmt.Errorf("invalid pod IP %q: %w", ip, err)
+		}
+
+		p.ips = append(p.ips, addr.Unmap())
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.unregister(id)
+
+	r.pods[id] = p
+	for _, addr := range p.ips {
+		r.podIPs[addr] = p
+	}
+
+	return nil
+}
+
+// Unregister removes a pod sandbox from the resolver. Unregistering an
+// unknown pod sandbox is a no-op.
+func (r *Resolver) Unregister(id string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if p := r.unregister(id); p != nil {
+		metrics.Instance().MetricDNSCacheQueriesDelete(p.namespace, p.name)
+	}
+}
+
+func (r *Resolver) unregister(id string) *pod {
+	p, ok := r.pods[id]
+	if !ok {
+		return nil
+	}
+
+	for _, addr := range p.ips {
+		if r.podIPs[addr] == p {
+			delete(r.podIPs, addr)
+		}
+	}
+
+	delete(r.pods, id)
+
+	return p
+}
+
+// podFor returns the pod registered for the source address, or nil.
+func (r *Resolver) podFor(source netip.Addr) *pod {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.podIPs[source.Unmap()]
+}
+
+func (r *Resolver) serveUDP(ctx context.Context) {
+	defer r.wg.Done()
+
+	buf := make([]byte, maxMessageSize)
+
+	for {
+		n, addr, err := r.udpConn.ReadFrom(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logrus.Errorf("Unable to read DNS query: %v", err)
+			}
+
+			return
+		}
+
+		udpAddr, ok := addr.(*net.UDPAddr)
+		if !ok {
+			continue
+		}
+
+		select {
+		case r.queries <- struct{}{}:
+		default:
+			logrus.Debugf("Dropping DNS query from %s: too many concurrent queries", addr)
+
+			continue
+		}
+
+		query := make([]byte, n)
+		copy(query, buf[:n])
+
+		r.wg.Go(func() {
+			defer func() { <-r.queries }()
+
+			resp := r.handle(ctx, query, udpAddr.AddrPort().Addr(), false)
+			if resp == nil {
+				return
+			}
+
+			if _, err := r.udpConn.WriteTo(resp, addr); err != nil {
+				logrus.Debugf("Unable to write DNS response to %s: %v", addr, err)
+			}
+		})
+	}
+}
+
+func (r *Resolver) serveTCP(ctx context.Context) {
+	defer r.wg.Done()
+
+	for {
+		conn, err := r.tcpListener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logrus.Errorf("Unable to accept DNS connection: %v", err)
+			}
+
+			return
+		}
+
+		select {
+		case r.tcpConns <- struct{}{}:
+		default:
+			logrus.Debugf("Closing DNS connection from %s: too many open connections", conn.RemoteAddr())
+			conn.Close()
+
+			continue
+		}
+
+		r.wg.Go(func() {
+			defer func() { <-r.tcpConns }()
+			defer conn.Close()
+
+			// Close idle connections on shutdown.
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			defer stop()
+
+			tcpAddr, ok := conn.RemoteAddr().(*net.TCPAddr)
+			if !ok {
+				return
+			}
+
+			for {
+				if err := conn.SetDeadline(time.Now().Add(tcpIdleTimeout)); err != nil {
+					return
+				}
+
+				query, err := readTCPMessage(conn)
+				if err != nil {
+					return
+				}
+
+				resp := r.handle(ctx, query, tcpAddr.AddrPort().Addr(), true)
+				if resp == nil {
+					return
+				}
+
+				if err := writeTCPMessage(conn, resp); err != nil {
+					return
+				}
+			}
+		})
+	}
+}
+
+// handle answers a single query either from the cache or by forwarding it to
+// the upstreams. It returns nil if the query should be dropped.
+func (r *Resolver) handle(ctx context.Context, query []byte, source netip.Addr, tcp bool) []byte {
+	var parser dnsmessage.Parser
+
+	header, err := parser.Start(query)
+	if err != nil || header.Response {
+		return nil
+	}
+
+	question, err := parser.Question()
+	if err != nil {
+		return nil
+	}
+
+	udpSize := clientUDPSize(&parser)
+
+	// Only answer registered pods, otherwise the cache would be an open
+	// resolver for everything able to reach the address.
+	p := r.podFor(source)
+	if p == nil {
+		logrus.Debugf("Dropping DNS query from unknown client %s", source)
+
+		return nil
+	}
+
+	namespace, name := p.namespace, p.name
+
+	upstreams := r.upstreams
+	if len(p.upstreams) > 0 {
+		upstreams = p.upstreams
+	}
+
+	key := cacheKey{
+		name:      strings.ToLower(question.Name.String()),
+		qtype:     question.Type,
+		qclass:    question.Class,
+		upstreams: strings.Join(upstreams, ","),
+	}
+
+	if msg, ok := r.cache.get(key, time.Now()); ok {
+		msg.ID = header.ID
+		msg.RecursionDesired = header.RecursionDesired
+
+		resp, err := packResponse(&msg, tcp, udpSize)
+		if err == nil {
+			r.observe(namespace, name, resultHit)
+
+			return resp
+		}
+
+		logrus.Debugf("Unable to pack cached DNS response for %s: %v", key.name, err)
+	}
+
+	resp, msg, err := r.forward(ctx, query, header.ID, upstreams, tcp)
+	if err != nil {
+		logrus.Debugf("Unable to resolve %s %s for pod %s/%s: %v", question.Type, key.name, namespace, name, err)
+		r.observe(namespace, name, resultError)
+
+		return serverFailure(header, question)
+	}
+
+	if ttl := cacheTTL(msg, r.maxTTL); ttl > 0 {
+		r.cache.put(key, msg, ttl, time.Now())
+	}
+
+	r.observe(namespace, name, resultMiss)
+
+	return resp
+}
+
+func (r *Resolver) observe(namespace, name, result string) {
+	metrics.Instance().MetricDNSCacheQueriesInc(namespace, name, result)
+}
+
+// forward sends the query to the upstreams in order and returns the first
+// valid response in its raw and parsed form.
+func (r *Resolver) forward(ctx context.Context, query []byte, id uint16, upstreams []string, tcp bool) ([]byte, *dnsmessage.Message, error) {
+	if len(upstreams) == 0 {
+		return nil, nil, errors.New("no upstream nameservers available")
+	}
+
+	var errs []error
+
+	for _, upstream := range upstreams {
+		resp, err := exchange(ctx, upstream, query, tcp)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", upstream, err))
+
+			continue
+		}
+
+		msg := &dnsmessage.Message{}
+		if err := msg.Unpack(resp); err != nil {
+			errs = append(errs, fmt.Errorf("%s: unpack response: %w", upstream, err))
+
+			continue
+		}
+
+		if msg.ID != id || !msg.Response {
+			errs = append(errs, fmt.Errorf("%s: response does not match query", upstream))
+
+			continue
+		}
+
+		if msg.RCode == dnsmessage.RCodeServerFailure || msg.RCode == dnsmessage.RCodeRefused {
+			errs = append(errs, fmt.Errorf("%s: %s", upstream, msg.RCode))
+
+			continue
+		}
+
+		return resp, msg, nil
+	}
+
+	return nil, nil, errors.Join(errs...)
+}
+
+// exchange sends a single query to the upstream and returns the raw response.
+func exchange(ctx context.Context, upstream string, query []byte, tcp bool) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, upstreamTimeout)
+	defer cancel()
+
+	network := "udp"
+	if tcp {
+		network = "tcp"
+	}
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, network, upstream)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	if tcp {
+		if err := writeTCPMessage(conn, query); err != nil {
+			return nil, err
+		}
+
+		return readTCPMessage(conn)
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, maxMessageSize)
+
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf[:n], nil
+}
+
+// clientUDPSize returns the maximum UDP response size the client supports.
+// The parser has to be positioned after the question section.
+func clientUDPSize(parser *dnsmessage.Parser) int {
+	if err := parser.SkipAllQuestions(); err != nil {
+		return defaultUDPSize
+	}
+
+	if err := parser.SkipAllAnswers(); err != nil {
+		return defaultUDPSize
+	}
+
+	if err := parser.SkipAllAuthorities(); err != nil {
+		return defaultUDPSize
+	}
+
+	for {
+		header, err := parser.AdditionalHeader()
+		if err != nil {
+			return defaultUDPSize
+		}
+
+		if header.Type == dnsmessage.TypeOPT {
+			return max(int(header.Class), defaultUDPSize)
+		}
+
+		if err := parser.SkipAdditional(); err != nil {
+			return defaultUDPSize
+		}
+	}
+}
+
+// packResponse packs the message and truncates it if it exceeds the maximum
+// UDP size of the client, so that the client retries using TCP.
+func packResponse(msg *dnsmessage.Message, tcp bool, udpSize int) ([]byte, error) {
+	resp, err := msg.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	if tcp || len(resp) <= udpSize {
+		return resp, nil
+	}
+
+	truncated := dnsmessage.Message{
+		Header:    msg.Header,
+		Questions: msg.Questions,
+	}
+	truncated.Truncated = true
+
+	return truncated.Pack()
+}
+
+// serverFailure returns a SERVFAIL response for the query.
+func serverFailure(header dnsmessage.Header, question dnsmessage.Question) []byte {
+	msg := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:                 header.ID,
+			Response:           true,
+			OpCode:             header.OpCode,
+			RecursionDesired:   header.RecursionDesired,
+			RecursionAvailable: true,
+			RCode:              dnsmessage.RCodeServerFailure,
+		},
+		Questions: []dnsmessage.Question{question},
+	}
+
+	resp, err := msg.Pack()
+	if err != nil {
+		return nil
+	}
+
+	return resp
+}
+
+func readTCPMessage(r io.Reader) ([]byte, error) {
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+
+	msg := make([]byte, length)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+func writeTCPMessage(w io.Writer, msg []byte) error {
+	if len(msg) > maxMessageSize {
+		return fmt.Errorf("message too large: %d bytes", len(msg))
+	}
+
+	buf := make([]byte, 2+len(msg))
+	binary.BigEndian.PutUint16(buf, uint16(len(msg)))
+	copy(buf[2:], msg)
+
+	_, err := w.Write(buf)
+
+	return err
+}
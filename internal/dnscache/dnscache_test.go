@@ -0,0 +1,407 @@
+package dnscache_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/cri-o/cri-o/internal/dnscache"
+)
+
+const (
+	testName      = "cri-o.io."
+	testLargeName = "large.cri-o.io."
+	testNXName    = "nx.cri-o.io."
+)
+
+// fakeUpstream is a nameserver answering queries via UDP and TCP on the same
+// port.
+type fakeUpstream struct {
+	udpConn     net.PacketConn
+	tcpListener net.Listener
+	queries     atomic.Int32
+}
+
+func newFakeUpstream() *fakeUpstream {
+	udpConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+
+	tcpListener, err := net.Listen("tcp", udpConn.LocalAddr().String())
+	Expect(err).NotTo(HaveOccurred())
+
+	u := &fakeUpstream{udpConn: udpConn, tcpListener: tcpListener}
+
+	go func() {
+		buf := make([]byte, 65535)
+
+		for {
+			n, addr, err := udpConn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			udpConn.WriteTo(u.answer(buf[:n]), addr) //nolint:errcheck // best effort in tests
+		}
+	}()
+
+	go func() {
+		for {
+			conn, err := tcpListener.Accept()
+			if err != nil {
+				return
+			}
+
+			query, err := readTCP(conn)
+			if err == nil {
+				writeTCP(conn, u.answer(query))
+			}
+
+			conn.Close()
+		}
+	}()
+
+	return u
+}
+
+func (u *fakeUpstream) address() string {
+	return u.udpConn.LocalAddr().String()
+}
+
+func (u *fakeUpstream) close() {
+	u.udpConn.Close()
+	u.tcpListener.Close()
+}
+
+func (u *fakeUpstream) answer(query []byte) []byte {
+	u.queries.Add(1)
+
+	var msg dnsmessage.Message
+	Expect(msg.Unpack(query)).To(Succeed())
+
+	msg.Response = true
+	msg.RecursionAvailable = true
+	msg.Additionals = nil
+	question := msg.Questions[0]
+
+	switch question.Name.String() {
+	case testNXName:
+		msg.RCode = dnsmessage.RCodeNameError
+		msg.Authorities = []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{
+				Name:  dnsmessage.MustNewName("cri-o.io."),
+				Type:  dnsmessage.TypeSOA,
+				Class: dnsmessage.ClassINET,
+				TTL:   300,
+			},
+			Body: &dnsmessage.SOAResource{
+				NS:     dnsmessage.MustNewName("ns.cri-o.io."),
+				MBox:   dnsmessage.MustNewName("admin.cri-o.io."),
+				MinTTL: 30,
+			},
+		}}
+
+	case testLargeName:
+		for i := range 64 {
+			msg.Answers = append(msg.Answers, aRecord(question.Name, byte(i)))
+		}
+
+	default:
+		msg.Answers = []dnsmessage.Resource{aRecord(question.Name, 1)}
+	}
+
+	resp, err := msg.Pack()
+	Expect(err).NotTo(HaveOccurred())
+
+	return resp
+}
+
+func aRecord(name dnsmessage.Name, last byte) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{
+			Name:  name,
+			Type:  dnsmessage.TypeA,
+			Class: dnsmessage.ClassINET,
+			TTL:   60,
+		},
+		Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, last}},
+	}
+}
+
+func readTCP(r io.Reader) ([]byte, error) {
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+
+	msg := make([]byte, length)
+	_, err := io.ReadFull(r, msg)
+
+	return msg, err
+}
+
+func writeTCP(w io.Writer, msg []byte) {
+	buf := binary.BigEndian.AppendUint16(nil, uint16(len(msg)))
+	w.Write(append(buf, msg...)) //nolint:errcheck // best effort in tests
+}
+
+// query resolves the A record of name via the provided network and address.
+func query(network, address, name string) *dnsmessage.Message {
+	q := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: 42, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{
+			Name:  dnsmessage.MustNewName(name),
+			Type:  dnsmessage.TypeA,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+	req, err := q.Pack()
+	Expect(err).NotTo(HaveOccurred())
+
+	conn, err := net.Dial(network, address)
+	Expect(err).NotTo(HaveOccurred())
+
+	defer conn.Close()
+
+	Expect(conn.SetDeadline(time.Now().Add(5 * time.Second))).To(Succeed())
+
+	var resp []byte
+
+	if network == "tcp" {
+		writeTCP(conn, req)
+		resp, err = readTCP(conn)
+		Expect(err).NotTo(HaveOccurred())
+	} else {
+		_, err = conn.Write(req)
+		Expect(err).NotTo(HaveOccurred())
+
+		buf := make([]byte, 65535)
+		n, err := conn.Read(buf)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp = buf[:n]
+	}
+
+	var msg dnsmessage.Message
+	Expect(msg.Unpack(resp)).To(Succeed())
+	Expect(msg.ID).To(BeEquivalentTo(42))
+
+	return &msg
+}
+
+// dropped returns true if the resolver does not answer a query.
+func dropped(network, address string) bool {
+	q := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: 42, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{
+			Name:  dnsmessage.MustNewName(testName),
+			Type:  dnsmessage.TypeA,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+	req, err := q.Pack()
+	Expect(err).NotTo(HaveOccurred())
+
+	conn, err := net.Dial(network, address)
+	Expect(err).NotTo(HaveOccurred())
+
+	defer conn.Close()
+
+	Expect(conn.SetDeadline(time.Now().Add(500 * time.Millisecond))).To(Succeed())
+
+	if network == "tcp" {
+		writeTCP(conn, req)
+		_, err = readTCP(conn)
+
+		return err != nil
+	}
+
+	_, err = conn.Write(req)
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = conn.Read(make([]byte, 65535))
+
+	return err != nil
+}
+
+// The actual test suite.
+var _ = t.Describe("Resolver", func() {
+	var (
+		upstream *fakeUpstream
+		sut      *dnscache.Resolver
+	)
+
+	startResolver := func(upstreams ...string) {
+		var err error
+
+		sut, err = dnscache.New("127.0.0.1", upstreams, 100, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+
+		sut.SetPort("0")
+		Expect(sut.Start(context.Background())).To(Succeed())
+
+		// Queries of unknown clients are dropped.
+		Expect(sut.Register("id", "namespace", "name", []string{"127.0.0.1"}, nil)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		upstream = newFakeUpstream()
+	})
+
+	AfterEach(func() {
+		if sut != nil {
+			sut.Shutdown()
+			sut = nil
+		}
+
+		upstream.close()
+	})
+
+	t.Describe("New", func() {
+		It("should fail with invalid address", func() {
+			// Given
+			// When
+			res, err := dnscache.New("invalid", nil, 100, time.Minute)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+
+		It("should fail with invalid upstream", func() {
+			// Given
+			// When
+			res, err := dnscache.New("127.0.0.1", []string{"cri-o.io"}, 100, time.Minute)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+
+		It("should fail with invalid size", func() {
+			// Given
+			// When
+			res, err := dnscache.New("127.0.0.1", nil, 0, time.Minute)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+	})
+
+	t.Describe("NormalizeUpstreams", func() {
+		It("should add the default port", func() {
+			// Given
+			// When
+			res, err := dnscache.NormalizeUpstreams([]string{"10.0.0.1", "10.0.0.2:5353", "fd00::1"})
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal([]string{"10.0.0.1:53", "10.0.0.2:5353", "[fd00::1]:53"}))
+		})
+	})
+
+	t.Describe("Resolve", func() {
+		It("should cache answers", func() {
+			// Given
+			startResolver(upstream.address())
+
+			// When
+			first := query("udp", sut.UDPAddress(), testName)
+			second := query("udp", sut.UDPAddress(), testName)
+
+			// Then
+			Expect(first.RCode).To(Equal(dnsmessage.RCodeSuccess))
+			Expect(first.Answers).To(HaveLen(1))
+			Expect(second.Answers).To(HaveLen(1))
+			Expect(second.Answers[0].Header.TTL).To(BeNumerically("<=", 60))
+			Expect(second.Answers[0].Body).To(Equal(first.Answers[0].Body))
+			Expect(upstream.queries.Load()).To(BeEquivalentTo(1))
+		})
+
+		It("should cache negative answers", func() {
+			// Given
+			startResolver(upstream.address())
+
+			// When
+			first := query("udp", sut.UDPAddress(), testNXName)
+			second := query("tcp", sut.TCPAddress(), testNXName)
+
+			// Then
+			Expect(first.RCode).To(Equal(dnsmessage.RCodeNameError))
+			Expect(second.RCode).To(Equal(dnsmessage.RCodeNameError))
+			Expect(upstream.queries.Load()).To(BeEquivalentTo(1))
+		})
+
+		It("should truncate large cached answers for UDP", func() {
+			// Given
+			startResolver(upstream.address())
+			full := query("tcp", sut.TCPAddress(), testLargeName)
+
+			// When
+			res := query("udp", sut.UDPAddress(), testLargeName)
+
+			// Then
+			Expect(full.Answers).To(HaveLen(64))
+			Expect(res.Truncated).To(BeTrue())
+			Expect(res.Answers).To(BeEmpty())
+			Expect(upstream.queries.Load()).To(BeEquivalentTo(1))
+		})
+
+		It("should fail without upstreams", func() {
+			// Given
+			startResolver()
+
+			// When
+			res := query("udp", sut.UDPAddress(), testName)
+
+			// Then
+			Expect(res.RCode).To(Equal(dnsmessage.RCodeServerFailure))
+		})
+
+		It("should use the upstreams of a registered pod", func() {
+			// Given
+			startResolver()
+			Expect(sut.Register("id", "namespace", "name", []string{"127.0.0.1"}, []string{upstream.address()})).To(Succeed())
+
+			// When
+			res := query("tcp", sut.TCPAddress(), testName)
+
+			// Then
+			Expect(res.RCode).To(Equal(dnsmessage.RCodeSuccess))
+			Expect(res.Answers).To(HaveLen(1))
+		})
+
+		It("should drop queries of an unregistered pod", func() {
+			// Given
+			startResolver(upstream.address())
+			sut.Unregister("id")
+
+			// When
+			udpDropped := dropped("udp", sut.UDPAddress())
+			tcpDropped := dropped("tcp", sut.TCPAddress())
+
+			// Then
+			Expect(udpDropped).To(BeTrue())
+			Expect(tcpDropped).To(BeTrue())
+			Expect(upstream.queries.Load()).To(BeZero())
+		})
+
+		It("should fail to register with invalid IP", func() {
+			// Given
+			startResolver()
+
+			// When
+			err := sut.Register("id", "namespace", "name", []string{"invalid"}, nil)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,21 @@
+//go:build test
+
+// All *_inject.go files are meant to be used by tests only. Purpose of this
+// files is to provide a way to inject mocked data into the current setup.
+
+package dnscache
+
+// SetPort sets the port the resolver listens on.
+func (r *Resolver) SetPort(port string) {
+	r.port = port
+}
+
+// UDPAddress returns the UDP address the resolver listens on.
+func (r *Resolver) UDPAddress() string {
+	return r.udpConn.LocalAddr().String()
+}
+
+// TCPAddress returns the TCP address the resolver listens on.
+func (r *Resolver) TCPAddress() string {
+	return r.tcpListener.Addr().String()
+}
@@ -0,0 +1,68 @@
+package dnscache
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RewriteResolvConf replaces all nameserver entries of the resolv.conf at
+// path with a single entry pointing to the provided address. The search and
+// options entries are retained. It returns the replaced nameservers, which
+// are the upstreams for the queries of the pod. The file is not modified if
+// it does not contain any nameserver or if a nameserver is not usable as
+// upstream, in which case ErrInvalidUpstream is returned.
+func RewriteResolvConf(path, address string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read resolv.conf: %w", err)
+	}
+
+	var (
+		upstreams []string
+		out       bytes.Buffer
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			out.WriteString(line + "\n")
+
+			continue
+		}
+
+		if len(upstreams) == 0 {
+			out.WriteString("nameserver " + address + "\n")
+		}
+
+		upstreams = append(upstreams, fields[1])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("parse resolv.conf: %w", err)
+	}
+
+	if len(upstreams) == 0 {
+		return nil, nil
+	}
+
+	if _, err := NormalizeUpstreams(upstreams); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat resolv.conf: %w", err)
+	}
+
+	if err := os.WriteFile(path, out.Bytes(), info.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("write resolv.conf: %w", err)
+	}
+
+	return upstreams, nil
+}
@@ -0,0 +1,89 @@
+package dnscache_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cri-o/cri-o/internal/dnscache"
+)
+
+// The actual test suite.
+var _ = t.Describe("RewriteResolvConf", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(t.MustTempDir("dnscache"), "resolv.conf")
+	})
+
+	It("should replace the nameservers", func() {
+		// Given
+		Expect(os.WriteFile(path, []byte(
+			"search default.svc.cluster.local svc.cluster.local\n"+
+				"nameserver 10.96.0.10\n"+
+				"nameserver 10.96.0.11\n"+
+				"options ndots:5\n",
+		), 0o644)).To(Succeed())
+
+		// When
+		upstreams, err := dnscache.RewriteResolvConf(path, "169.254.20.10")
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upstreams).To(Equal([]string{"10.96.0.10", "10.96.0.11"}))
+
+		content, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal(
+			"search default.svc.cluster.local svc.cluster.local\n" +
+				"nameserver 169.254.20.10\n" +
+				"options ndots:5\n",
+		))
+	})
+
+	It("should not modify a file without nameservers", func() {
+		// Given
+		const content = "search cri-o.io\n"
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+
+		// When
+		upstreams, err := dnscache.RewriteResolvConf(path, "169.254.20.10")
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upstreams).To(BeEmpty())
+
+		res, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(res)).To(Equal(content))
+	})
+
+	It("should not modify a file with invalid nameservers", func() {
+		// Given
+		const content = "nameserver 10.96.0.10\nnameserver fe80::1%eth0\n"
+		Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+
+		// When
+		upstreams, err := dnscache.RewriteResolvConf(path, "169.254.20.10")
+
+		// Then
+		Expect(err).To(MatchError(dnscache.ErrInvalidUpstream))
+		Expect(upstreams).To(BeNil())
+
+		res, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(res)).To(Equal(content))
+	})
+
+	It("should fail if the file does not exist", func() {
+		// Given
+		// When
+		upstreams, err := dnscache.RewriteResolvConf(path, "169.254.20.10")
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(upstreams).To(BeNil())
+	})
+})
@@ -0,0 +1,30 @@
+package dnscache_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/cri-o/cri-o/test/framework"
+)
+
+// TestDNSCache runs the created specs.
+func TestDNSCache(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunFrameworkSpecs(t, "DNSCache")
+}
+
+var t *TestFramework
+
+var _ = BeforeSuite(func() {
+	t = NewTestFramework(NilFunc, NilFunc)
+	t.Setup()
+
+	logrus.SetLevel(logrus.PanicLevel)
+})
+
+var _ = AfterSuite(func() {
+	t.Teardown()
+})
@@ -4,20 +4,36 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	metadata "github.com/checkpoint-restore/checkpointctl/lib"
 	"github.com/checkpoint-restore/go-criu/v8/stats"
+	encconfig "github.com/containers/ocicrypt/config"
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
 	"go.podman.io/common/pkg/crutils"
+	"go.podman.io/image/v5/docker/reference"
+	"go.podman.io/image/v5/types"
 	"go.podman.io/storage/pkg/archive"
 
 	"github.com/cri-o/cri-o/internal/annotations"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/server/metrics"
+)
+
+const (
+	checkpointPhasePreDump = "pre_dump"
+	checkpointPhaseDump    = "dump"
+	checkpointPhaseExport  = "export"
+
+	// preCheckpointDirectoryPrefix is the prefix of the CRIU pre-dump
+	// directories in the container directory.
+	preCheckpointDirectoryPrefix = "pre-" + metadata.CheckpointDirectory + "-"
 )
 
 // ContainerCheckpointOptions is the relevant subset of libpod.ContainerCheckpointOptions.
@@ -30,6 +46,24 @@ type ContainerCheckpointOptions struct {
 	// TargetFile tells the API to read (or write) the checkpoint image
 	// from (or to) the filename set in TargetFile
 	TargetFile string
+	// Compression is the compression of the written checkpoint archive
+	Compression archive.Compression
+	// ImageName tells the API to write the checkpoint as OCI image with
+	// this name into the local containers-storage instead of TargetFile
+	ImageName reference.Named
+	// Push tells the API to push the checkpoint image to its registry
+	Push bool
+	// SystemContext is used for pushing the checkpoint image
+	SystemContext *types.SystemContext
+	// PreDumpIterations is the number of CRIU pre-dumps of the running
+	// container before it gets paused for the final dump
+	PreDumpIterations int
+	// EncryptConfig tells the API to encrypt the checkpoint archive and the
+	// pushed checkpoint image for its recipients
+	EncryptConfig *encconfig.EncryptConfig
+	// DecryptConfig is used to decrypt encrypted checkpoint archives on
+	// restore
+	DecryptConfig *encconfig.DecryptConfig
 }
 
 // ContainerCheckpoint checkpoints a running container.
@@ -38,6 +72,12 @@ func (c *ContainerServer) ContainerCheckpoint(
 	config *metadata.ContainerConfig,
 	opts *ContainerCheckpointOptions,
 ) (string, error) {
+	// The containers-storage can not hold encrypted layers, so encrypted
+	// checkpoint images are only pushed.
+	if opts.EncryptConfig != nil && opts.ImageName != nil && !opts.Push {
+		return "", errCheckpointImageNotEncrypted
+	}
+
 	ctr, err := c.LookupContainer(ctx, config.ID)
 	if err != nil {
 		return "", fmt.Errorf("failed to find container %s: %w", config.ID, err)
@@ -55,6 +95,10 @@ func (c *ContainerServer) ContainerCheckpoint(
 		return "", fmt.Errorf("container %s is not running", ctr.ID())
 	}
 
+	// The pre-dumps copy the memory of the still running container, so that
+	// the final dump of the paused container only copies the changed pages.
+	parentPath, preDumpDirs := c.preDumpContainer(ctx, ctr, specgen.Config, opts.PreDumpIterations)
+
 	// At this point the container needs to be paused. As we first checkpoint
 	// the processes in the container and the container will continue to run
 	// after checkpointing, there is a chance that the changed files we include
@@ -86,32 +130,72 @@ func (c *ContainerServer) ContainerCheckpoint(
 		}
 	}()
 
-	if opts.TargetFile != "" {
+	export := opts.TargetFile
+
+	// An encrypted checkpoint archive is written from the plain one.
+	encrypt := opts.EncryptConfig != nil && opts.ImageName == nil && opts.TargetFile != ""
+
+	if opts.ImageName != nil || encrypt {
+		// The checkpoint archive becomes the layer of the image or gets
+		// encrypted into the target file.
+		exportFile, err := os.CreateTemp(ctr.Dir(), "checkpoint-*.tar")
+		if err != nil {
+			return "", fmt.Errorf("create checkpoint archive for container %s: %w", ctr.ID(), err)
+		}
+
+		exportFile.Close()
+		export = exportFile.Name()
+
+		defer func() {
+			if err := os.Remove(export); err != nil {
+				log.Warnf(ctx, "Unable to remove checkpoint archive %s: %v", export, err)
+			}
+		}()
+	}
+
+	if export != "" {
 		if err := c.prepareCheckpointExport(ctr); err != nil {
 			return "", fmt.Errorf("failed to write config dumps for container %s: %w", ctr.ID(), err)
 		}
 	}
 
-	if err := c.runtime.CheckpointContainer(ctx, ctr, specgen.Config, opts.KeepRunning); err != nil {
+	dumpStart := time.Now()
+
+	if err := c.runtime.CheckpointContainer(ctx, ctr, specgen.Config, opts.KeepRunning, parentPath); err != nil {
 		// in the case of an error, clean up any leftover CRIU images
-		if err := os.RemoveAll(ctr.CheckpointPath()); err != nil {
-			log.Warnf(ctx, "Unable to remove checkpoint directory %s: %v", ctr.CheckpointPath(), err)
-		}
+		removeCheckpointDirectories(ctx, ctr, preDumpDirs)
 
 		return "", fmt.Errorf("failed to checkpoint container %s: %w", ctr.ID(), err)
 	}
 
-	if opts.TargetFile != "" {
-		if err := c.exportCheckpoint(ctx, ctr, specgen.Config, opts.TargetFile); err != nil {
+	metrics.Instance().MetricCheckpointPhaseDurationObserve(checkpointPhaseDump, dumpStart)
+	observeCheckpointSize(ctx, ctr, checkpointPhaseDump, ctr.CheckpointPath())
+
+	if export != "" {
+		exportStart := time.Now()
+
+		if err := c.exportCheckpoint(ctx, ctr, specgen.Config, export, opts.Compression, preDumpDirs); err != nil {
 			return "", fmt.Errorf("failed to write file system changes of container %s: %w", ctr.ID(), err)
 		}
 
 		defer func() {
 			// clean up checkpoint directory
-			if err := os.RemoveAll(ctr.CheckpointPath()); err != nil {
-				log.Warnf(ctx, "Unable to remove checkpoint directory %s: %v", ctr.CheckpointPath(), err)
-			}
+			removeCheckpointDirectories(ctx, ctr, preDumpDirs)
 		}()
+
+		if opts.ImageName != nil {
+			if err := c.commitCheckpointImage(ctx, ctr, export, opts); err != nil {
+				return "", err
+			}
+		}
+
+		if encrypt {
+			if err := EncryptCheckpointArchive(opts.EncryptConfig, export, opts.TargetFile); err != nil {
+				return "", fmt.Errorf("failed to encrypt checkpoint of container %s: %w", ctr.ID(), err)
+			}
+		}
+
+		metrics.Instance().MetricCheckpointPhaseDurationObserve(checkpointPhaseExport, exportStart)
 	}
 
 	if !opts.KeepRunning {
@@ -298,7 +382,7 @@ func (c *ContainerServer) prepareCheckpointExport(ctr *oci.Container) error {
 	return nil
 }
 
-func (c *ContainerServer) exportCheckpoint(ctx context.Context, ctr *oci.Container, specgen *rspec.Spec, export string) error {
+func (c *ContainerServer) exportCheckpoint(ctx context.Context, ctr *oci.Container, specgen *rspec.Spec, export string, compression archive.Compression, preDumpDirs []string) error {
 	id := ctr.ID()
 	dest := ctr.Dir()
 	log.Debugf(ctx, "Exporting checkpoint image of container %q to %q", id, dest)
@@ -354,13 +438,14 @@ func (c *ContainerServer) exportCheckpoint(ctx context.Context, ctr *oci.Contain
 		metadata.SpecDumpFile,
 		"bind.mounts",
 	}
-	includeFiles := make([]string, 0, len(baseFiles)+len(addToTarFiles))
+	includeFiles := make([]string, 0, len(baseFiles)+len(preDumpDirs)+len(addToTarFiles))
 	includeFiles = append(includeFiles, baseFiles...)
+	// The final dump refers to the memory pages of the pre-dumps.
+	includeFiles = append(includeFiles, preDumpDirs...)
 	includeFiles = append(includeFiles, addToTarFiles...)
 
 	input, err := archive.TarWithOptions(ctr.Dir(), &archive.TarOptions{
-		// This should be configurable via api.proti
-		Compression:      archive.Uncompressed,
+		Compression:      compression,
 		IncludeSourceDir: true,
 		IncludeFiles:     includeFiles,
 	})
@@ -370,7 +455,7 @@ func (c *ContainerServer) exportCheckpoint(ctx context.Context, ctr *oci.Contain
 
 	// The resulting tar archive should not be readable by everyone as it contains
 	// every memory page of the checkpointed processes.
-	outFile, err := os.OpenFile(export, os.O_RDWR|os.O_CREATE, 0o600)
+	outFile, err := os.OpenFile(export, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
 	if err != nil {
 		return fmt.Errorf("error creating checkpoint export file %q: %w", export, err)
 	}
@@ -387,3 +472,88 @@ func (c *ContainerServer) exportCheckpoint(ctx context.Context, ctr *oci.Contain
 
 	return nil
 }
+
+// preCheckpointDirectory returns the directory of a CRIU pre-dump iteration
+// relative to the container directory.
+func preCheckpointDirectory(iteration int) string {
+	return preCheckpointDirectoryPrefix + strconv.Itoa(iteration)
+}
+
+// preDumpContainer runs the CRIU pre-dump iterations of the running container.
+// It returns the last pre-dump directory relative to the checkpoint directory
+// and all pre-dump directories relative to the container directory. A failing
+// pre-dump, for example due to missing memory tracking support of the kernel,
+// falls back to a full dump.
+func (c *ContainerServer) preDumpContainer(ctx context.Context, ctr *oci.Container, specgen *rspec.Spec, iterations int) (parentPath string, preDumpDirs []string) {
+	for iteration := 1; iteration <= iterations; iteration++ {
+		dir := preCheckpointDirectory(iteration)
+		imagePath := filepath.Join(ctr.Dir(), dir)
+		start := time.Now()
+
+		if err := c.runtime.PreDumpContainer(ctx, ctr, specgen, imagePath, parentPath); err != nil {
+			log.Warnf(ctx, "Unable to pre-dump container %s, falling back to a full dump: %v", ctr.ID(), err)
+
+			for _, dir := range append(preDumpDirs, dir) {
+				if err := os.RemoveAll(filepath.Join(ctr.Dir(), dir)); err != nil {
+					log.Warnf(ctx, "Unable to remove pre-dump directory %s: %v", dir, err)
+				}
+			}
+
+			return "", nil
+		}
+
+		metrics.Instance().MetricCheckpointPhaseDurationObserve(checkpointPhasePreDump, start)
+		observeCheckpointSize(ctx, ctr, checkpointPhasePreDump, imagePath)
+
+		preDumpDirs = append(preDumpDirs, dir)
+		parentPath = filepath.Join("..", dir)
+	}
+
+	return parentPath, preDumpDirs
+}
+
+// observeCheckpointSize records the size of the CRIU images written by a
+// checkpoint phase.
+func observeCheckpointSize(ctx context.Context, ctr *oci.Container, phase, imagePath string) {
+	var size int64
+
+	if err := filepath.WalkDir(imagePath, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+
+		return nil
+	}); err != nil {
+		log.Warnf(ctx, "Unable to get size of checkpoint directory %s: %v", imagePath, err)
+
+		return
+	}
+
+	log.Infof(ctx, "Checkpoint phase %s of container %s wrote %d bytes", phase, ctr.ID(), size)
+	metrics.Instance().MetricCheckpointPhaseSizeObserve(phase, size)
+}
+
+// removeCheckpointDirectories removes the CRIU images of the container.
+func removeCheckpointDirectories(ctx context.Context, ctr *oci.Container, preDumpDirs []string) {
+	dirs := []string{ctr.CheckpointPath()}
+	for _, dir := range preDumpDirs {
+		dirs = append(dirs, filepath.Join(ctr.Dir(), dir))
+	}
+
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Warnf(ctx, "Unable to remove checkpoint directory %s: %v", dir, err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package lib
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/containers/ocicrypt"
+	encconfig "github.com/containers/ocicrypt/config"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// checkpointEncryptedArchive is the name of the encrypted checkpoint
+	// archive within an encrypted checkpoint archive.
+	checkpointEncryptedArchive = "checkpoint.tar.enc"
+
+	// checkpointEncryptionFile is the name of the file within an encrypted
+	// checkpoint archive which contains the wrapped keys.
+	checkpointEncryptionFile = "encryption.json"
+)
+
+// checkpointEncryption is the content of the checkpointEncryptionFile.
+type checkpointEncryption struct {
+	// Annotations are the ocicrypt annotations containing the wrapped keys
+	// and the public options of the cipher.
+	Annotations map[string]string `json:"annotations"`
+}
+
+// EncryptCheckpointArchive encrypts the checkpoint archive source for the
+// recipients of the encryption config and writes it to target. The encrypted
+// archive is an uncompressed tar containing the encrypted source followed by
+// the keys wrapped for the recipients.
+func EncryptCheckpointArchive(ec *encconfig.EncryptConfig, source, target string) error {
+	input, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("open checkpoint archive: %w", err)
+	}
+	defer input.Close()
+
+	info, err := input.Stat()
+	if err != nil {
+		return fmt.Errorf("stat checkpoint archive: %w", err)
+	}
+
+	encReader, finalizer, err := ocicrypt.EncryptLayer(ec, input, imgspecv1.Descriptor{})
+	if err != nil {
+		return fmt.Errorf("encrypt checkpoint archive: %w", err)
+	}
+
+	outFile, err := os.OpenFile(target, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	now := time.Now()
+	tw := tar.NewWriter(outFile)
+
+	// The cipher does not change the size of the archive.
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     checkpointEncryptedArchive,
+		Typeflag: tar.TypeReg,
+		Mode:     0o600,
+		Size:     info.Size(),
+		ModTime:  now,
+	}); err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(tw, encReader); err != nil {
+		return fmt.Errorf("encrypt checkpoint archive: %w", err)
+	}
+
+	annotations, err := finalizer()
+	if err != nil {
+		return fmt.Errorf("wrap checkpoint archive keys: %w", err)
+	}
+
+	encryption, err := json.Marshal(&checkpointEncryption{Annotations: annotations})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     checkpointEncryptionFile,
+		Typeflag: tar.TypeReg,
+		Mode:     0o600,
+		Size:     int64(len(encryption)),
+		ModTime:  now,
+	}); err != nil {
+		return err
+	}
+
+	if _, err := tw.Write(encryption); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return outFile.Close()
+}
+
+// readCheckpointEncryption returns the wrapped keys of an encrypted checkpoint
+// archive, or nil if the archive is not encrypted.
+func readCheckpointEncryption(archivePath string) (*checkpointEncryption, error) {
+	archiveFile, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("open checkpoint archive: %w", err)
+	}
+	defer archiveFile.Close()
+
+	tr := tar.NewReader(archiveFile)
+
+	// Plain checkpoint archives are either compressed, which is not readable
+	// as tar, or start with another file.
+	header, err := tr.Next()
+	if err != nil || header.Name != checkpointEncryptedArchive {
+		return nil, nil
+	}
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("encrypted checkpoint archive %s does not contain %s", archivePath, checkpointEncryptionFile)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("read encrypted checkpoint archive %s: %w", archivePath, err)
+		}
+
+		if header.Name != checkpointEncryptionFile {
+			continue
+		}
+
+		encryption := &checkpointEncryption{}
+		if err := json.NewDecoder(tr).Decode(encryption); err != nil {
+			return nil, fmt.Errorf("read %s: %w", checkpointEncryptionFile, err)
+		}
+
+		return encryption, nil
+	}
+}
+
+// DecryptCheckpointArchive decrypts an encrypted checkpoint archive into a new
+// file within dir and returns its path. The path of archives which are not
+// encrypted is returned unchanged.
+func DecryptCheckpointArchive(dc *encconfig.DecryptConfig, archivePath, dir string) (path string, retErr error) {
+	encryption, err := readCheckpointEncryption(archivePath)
+	if err != nil {
+		return "", err
+	}
+
+	if encryption == nil {
+		return archivePath, nil
+	}
+
+	if dc == nil {
+		return "", fmt.Errorf("checkpoint archive %s is encrypted, but no decryption keys are available", archivePath)
+	}
+
+	archiveFile, err := os.Open(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("open checkpoint archive: %w", err)
+	}
+	defer archiveFile.Close()
+
+	tr := tar.NewReader(archiveFile)
+	if _, err := tr.Next(); err != nil {
+		return "", fmt.Errorf("read encrypted checkpoint archive %s: %w", archivePath, err)
+	}
+
+	plainReader, _, err := ocicrypt.DecryptLayer(dc, tr, imgspecv1.Descriptor{
+		Annotations: encryption.Annotations,
+	}, false)
+	if err != nil {
+		return "", fmt.Errorf("decrypt checkpoint archive %s: %w", archivePath, err)
+	}
+
+	outFile, err := os.CreateTemp(dir, "checkpoint-*.tar")
+	if err != nil {
+		return "", fmt.Errorf("create decrypted checkpoint archive: %w", err)
+	}
+	defer outFile.Close()
+
+	defer func() {
+		if retErr != nil {
+			os.Remove(outFile.Name())
+		}
+	}()
+
+	// The integrity of the archive is verified once it has been read
+	// completely.
+	if _, err := io.Copy(outFile, plainReader); err != nil {
+		return "", fmt.Errorf("decrypt checkpoint archive %s: %w", archivePath, err)
+	}
+
+	if err := outFile.Close(); err != nil {
+		return "", err
+	}
+
+	return outFile.Name(), nil
+}
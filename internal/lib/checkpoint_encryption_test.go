@@ -0,0 +1,89 @@
+package lib_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+
+	encconfig "github.com/containers/ocicrypt/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cri-o/cri-o/internal/lib"
+)
+
+// The actual test suite.
+var _ = t.Describe("CheckpointEncryption", func() {
+	var (
+		encryptConfig *encconfig.EncryptConfig
+		decryptConfig *encconfig.DecryptConfig
+		archivePath   string
+	)
+
+	BeforeEach(func() {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+
+		publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		Expect(err).NotTo(HaveOccurred())
+
+		cc, err := encconfig.EncryptWithJwe([][]byte{publicKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		encryptConfig = cc.EncryptConfig
+
+		cc, err = encconfig.DecryptWithPrivKeys([][]byte{x509.MarshalPKCS1PrivateKey(privateKey)}, [][]byte{nil})
+		Expect(err).NotTo(HaveOccurred())
+
+		decryptConfig = cc.DecryptConfig
+
+		archivePath = filepath.Join(t.MustTempDir("checkpoint"), "checkpoint.tar")
+		Expect(os.WriteFile(archivePath, []byte("checkpoint"), 0o600)).To(Succeed())
+	})
+
+	It("should return plain archives unchanged", func() {
+		// Given
+		// When
+		res, err := lib.DecryptCheckpointArchive(decryptConfig, archivePath, t.MustTempDir("restore"))
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(archivePath))
+	})
+
+	It("should succeed to encrypt and decrypt", func() {
+		// Given
+		encryptedPath := filepath.Join(t.MustTempDir("checkpoint"), "encrypted.tar")
+
+		// When
+		err := lib.EncryptCheckpointArchive(encryptConfig, archivePath, encryptedPath)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		res, err := lib.DecryptCheckpointArchive(decryptConfig, encryptedPath, t.MustTempDir("restore"))
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).NotTo(Equal(encryptedPath))
+		Expect(os.ReadFile(res)).To(Equal([]byte("checkpoint")))
+	})
+
+	It("should fail to decrypt without the private key", func() {
+		// Given
+		encryptedPath := filepath.Join(t.MustTempDir("checkpoint"), "encrypted.tar")
+		Expect(lib.EncryptCheckpointArchive(encryptConfig, archivePath, encryptedPath)).To(Succeed())
+		dir := t.MustTempDir("restore")
+
+		// When
+		res, err := lib.DecryptCheckpointArchive(&encconfig.DecryptConfig{}, encryptedPath, dir)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(res).To(BeEmpty())
+		Expect(os.ReadDir(dir)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,173 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	criu "github.com/checkpoint-restore/go-criu/v8/utils"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.podman.io/image/v5/copy"
+	"go.podman.io/image/v5/docker"
+	"go.podman.io/image/v5/docker/reference"
+	"go.podman.io/image/v5/signature"
+	istorage "go.podman.io/image/v5/storage"
+	"go.podman.io/image/v5/tarball"
+
+	"github.com/cri-o/cri-o/internal/annotations"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/internal/version"
+)
+
+const (
+	// CheckpointImageStoragePrefix is the prefix of checkpoint locations which
+	// are written as OCI image into the local containers-storage.
+	CheckpointImageStoragePrefix = "containers-storage:"
+
+	// CheckpointImageRegistryPrefix is the prefix of checkpoint locations
+	// which are written as OCI image into the local containers-storage and
+	// pushed to a registry.
+	CheckpointImageRegistryPrefix = "docker://"
+)
+
+var errCheckpointImageNotEncrypted = errors.New("checkpoint images in the local containers-storage can not be encrypted, push them to a registry instead")
+
+// ParseCheckpointImageLocation parses a checkpoint location referring to an
+// OCI image. It returns a nil name for locations referring to a file and
+// whether the image should be pushed to its registry.
+func ParseCheckpointImageLocation(location string) (name reference.Named, push bool, err error) {
+	var image string
+
+	switch {
+	case strings.HasPrefix(location, CheckpointImageStoragePrefix):
+		image = strings.TrimPrefix(location, CheckpointImageStoragePrefix)
+	case strings.HasPrefix(location, CheckpointImageRegistryPrefix):
+		image = strings.TrimPrefix(location, CheckpointImageRegistryPrefix)
+		push = true
+	default:
+		return nil, false, nil
+	}
+
+	name, err = reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil, false, fmt.Errorf("parse checkpoint image name %q: %w", image, err)
+	}
+
+	if _, ok := name.(reference.Digested); ok {
+		return nil, false, fmt.Errorf("checkpoint image name %q must not contain a digest", image)
+	}
+
+	return reference.TagNameOnly(name), push, nil
+}
+
+// checkpointImageAnnotations returns the annotations of the checkpoint image
+// of the container, which identify the image as checkpoint on restore.
+func checkpointImageAnnotations(ctr *oci.Container) map[string]string {
+	imageAnnotations := map[string]string{
+		annotations.CheckpointAnnotationName:         ctr.Name(),
+		annotations.CheckpointAnnotationRawImageName: ctr.UserRequestedImage(),
+		annotations.CheckpointAnnotationCRIOVersion:  version.Version,
+	}
+
+	if imageID := ctr.ImageID(); imageID != nil {
+		imageAnnotations[annotations.CheckpointAnnotationRootfsImageID] = imageID.IDStringForOutOfProcessConsumptionOnly()
+	}
+
+	if imageName := ctr.SomeNameOfTheImage(); imageName != nil {
+		imageAnnotations[annotations.CheckpointAnnotationRootfsImageName] = imageName.StringForOutOfProcessConsumptionOnly()
+	}
+
+	if criuVersion, err := criu.GetCriuVersion(); err == nil {
+		imageAnnotations[annotations.CheckpointAnnotationCriuVersion] = strconv.Itoa(criuVersion)
+	}
+
+	return imageAnnotations
+}
+
+// commitCheckpointImage writes the checkpoint archive of the container as
+// single layer OCI image into the local containers-storage, and pushes it to
+// its registry if requested.
+func (c *ContainerServer) commitCheckpointImage(ctx context.Context, ctr *oci.Container, archivePath string, opts *ContainerCheckpointOptions) error {
+	srcRef, err := tarball.NewReference([]string{archivePath}, nil)
+	if err != nil {
+		return fmt.Errorf("create checkpoint image source: %w", err)
+	}
+
+	updater, ok := srcRef.(tarball.ConfigUpdater)
+	if !ok {
+		return fmt.Errorf("checkpoint image source %s can not be configured", srcRef.StringWithinTransport())
+	}
+
+	created := time.Now().UTC()
+	if err := updater.ConfigUpdate(imgspecv1.Image{
+		Created: &created,
+		Platform: imgspecv1.Platform{
+			OS:           runtime.GOOS,
+			Architecture: runtime.GOARCH,
+		},
+	}, checkpointImageAnnotations(ctr)); err != nil {
+		return fmt.Errorf("configure checkpoint image: %w", err)
+	}
+
+	// The image is built from our own checkpoint, so there is no signature
+	// to verify.
+	policyContext, err := signature.NewPolicyContext(&signature.Policy{
+		Default: []signature.PolicyRequirement{signature.NewPRInsecureAcceptAnything()},
+	})
+	if err != nil {
+		return fmt.Errorf("create checkpoint image policy: %w", err)
+	}
+
+	defer func() {
+		if err := policyContext.Destroy(); err != nil {
+			log.Warnf(ctx, "Unable to destroy checkpoint image policy: %v", err)
+		}
+	}()
+
+	// The containers-storage can not hold encrypted layers, so encrypted
+	// checkpoint images are only pushed to not leave a plain copy behind.
+	if opts.EncryptConfig == nil {
+		destRef, err := istorage.Transport.NewStoreReference(c.store, opts.ImageName, "")
+		if err != nil {
+			return fmt.Errorf("create checkpoint image reference: %w", err)
+		}
+
+		if _, err := copy.Image(ctx, policyContext, destRef, srcRef, &copy.Options{}); err != nil {
+			return fmt.Errorf("write checkpoint image %s: %w", opts.ImageName, err)
+		}
+
+		log.Infof(ctx, "Wrote checkpoint of container %s as image %s", ctr.ID(), opts.ImageName)
+	}
+
+	if !opts.Push {
+		return nil
+	}
+
+	pushRef, err := docker.NewReference(opts.ImageName)
+	if err != nil {
+		return fmt.Errorf("create checkpoint image registry reference: %w", err)
+	}
+
+	pushOptions := &copy.Options{
+		DestinationCtx: opts.SystemContext,
+	}
+
+	if opts.EncryptConfig != nil {
+		pushOptions.OciEncryptConfig = opts.EncryptConfig
+		pushOptions.OciEncryptLayers = &[]int{}
+	}
+
+	// Pushing from the archive keeps the layer compression.
+	if _, err := copy.Image(ctx, policyContext, pushRef, srcRef, pushOptions); err != nil {
+		return fmt.Errorf("push checkpoint image %s: %w", opts.ImageName, err)
+	}
+
+	log.Infof(ctx, "Pushed checkpoint image %s", opts.ImageName)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package lib_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cri-o/cri-o/internal/lib"
+)
+
+// The actual test suite.
+var _ = t.Describe("ParseCheckpointImageLocation", func() {
+	It("should return no image for files", func() {
+		// Given
+		// When
+		name, push, err := lib.ParseCheckpointImageLocation("/var/lib/kubelet/checkpoints/checkpoint.tar")
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(BeNil())
+		Expect(push).To(BeFalse())
+	})
+
+	It("should parse local images", func() {
+		// Given
+		// When
+		name, push, err := lib.ParseCheckpointImageLocation("containers-storage:localhost/checkpoint")
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name.String()).To(Equal("localhost/checkpoint:latest"))
+		Expect(push).To(BeFalse())
+	})
+
+	It("should parse registry images", func() {
+		// Given
+		// When
+		name, push, err := lib.ParseCheckpointImageLocation("docker://quay.io/crio/checkpoint:v1")
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name.String()).To(Equal("quay.io/crio/checkpoint:v1"))
+		Expect(push).To(BeTrue())
+	})
+
+	It("should fail with invalid image name", func() {
+		// Given
+		// When
+		name, _, err := lib.ParseCheckpointImageLocation("docker://Invalid")
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(name).To(BeNil())
+	})
+
+	It("should fail with digested image name", func() {
+		// Given
+		// When
+		name, _, err := lib.ParseCheckpointImageLocation(
+			"containers-storage:localhost/checkpoint@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(name).To(BeNil())
+	})
+})
@@ -0,0 +1,259 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+	encconfig "github.com/containers/ocicrypt/config"
+	"go.podman.io/storage/pkg/archive"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+)
+
+// PodCheckpointContainersDirectory is the directory of a pod checkpoint
+// archive containing the checkpoint archives of the containers, named after
+// the Kubernetes container name.
+const PodCheckpointContainersDirectory = "containers"
+
+// PodCheckpointOptions are the options for checkpointing a pod sandbox.
+type PodCheckpointOptions struct {
+	// TargetFile is the pod checkpoint archive to be written.
+	TargetFile string
+	// Compression is the compression of the pod checkpoint archive.
+	Compression archive.Compression
+	// EncryptConfig tells the API to encrypt the pod checkpoint archive for
+	// its recipients.
+	EncryptConfig *encconfig.EncryptConfig
+	// Networks are the names of the networks the pod sandbox is attached to.
+	Networks []string
+}
+
+// PodCheckpointConfig is the sandbox metadata stored as pod.dump in a pod
+// checkpoint archive.
+type PodCheckpointConfig struct {
+	ID               string                   `json:"id"`
+	Name             string                   `json:"name"`
+	Namespace        string                   `json:"namespace"`
+	UID              string                   `json:"uid"`
+	Attempt          uint32                   `json:"attempt"`
+	RuntimeHandler   string                   `json:"runtimeHandler"`
+	Hostname         string                   `json:"hostname"`
+	Labels           map[string]string        `json:"labels,omitempty"`
+	Annotations      map[string]string        `json:"annotations,omitempty"`
+	NamespaceOptions *types.NamespaceOption   `json:"namespaceOptions,omitempty"`
+	DNSConfig        *types.DNSConfig         `json:"dnsConfig,omitempty"`
+	PortMappings     []*hostport.PortMapping  `json:"portMappings,omitempty"`
+	Networks         []string                 `json:"networks,omitempty"`
+	Containers       []PodCheckpointContainer `json:"containers"`
+	CheckpointedAt   time.Time                `json:"checkpointedAt"`
+}
+
+// NewPodCheckpointConfig returns the metadata of the sandbox attached to the
+// networks, without any containers.
+func NewPodCheckpointConfig(sb *sandbox.Sandbox, networks []string) *PodCheckpointConfig {
+	return &PodCheckpointConfig{
+		ID:               sb.ID(),
+		Name:             sb.Metadata().GetName(),
+		Namespace:        sb.Metadata().GetNamespace(),
+		UID:              sb.Metadata().GetUid(),
+		Attempt:          sb.Metadata().GetAttempt(),
+		RuntimeHandler:   sb.RuntimeHandler(),
+		Hostname:         sb.Hostname(),
+		Labels:           sb.Labels(),
+		Annotations:      sb.Annotations(),
+		NamespaceOptions: sb.NamespaceOptions(),
+		DNSConfig:        sb.DNSConfig(),
+		PortMappings:     sb.PortMappings(),
+		Networks:         networks,
+		CheckpointedAt:   time.Now(),
+	}
+}
+
+// PodCheckpointContainer is a container stored in a pod checkpoint archive.
+type PodCheckpointContainer struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PodCheckpointContainerArchive returns the path of the checkpoint archive of
+// the container in the extracted pod checkpoint directory.
+func PodCheckpointContainerArchive(dir, name string) string {
+	return filepath.Join(dir, PodCheckpointContainersDirectory, name+".tar")
+}
+
+// PodCheckpoint checkpoints all running containers of a pod sandbox together
+// with the sandbox metadata, including its network configuration, and the
+// content of its /dev/shm into one archive. The containers keep running.
+func (c *ContainerServer) PodCheckpoint(ctx context.Context, sb *sandbox.Sandbox, opts *PodCheckpointOptions) error {
+	if opts.TargetFile == "" {
+		return errors.New("no target file provided for pod checkpoint")
+	}
+
+	// The directory may contain every memory page of the pod, so it is
+	// placed next to the target instead of a possibly small tmpfs.
+	dir, err := os.MkdirTemp(filepath.Dir(opts.TargetFile), ".pod-checkpoint-")
+	if err != nil {
+		return fmt.Errorf("create pod checkpoint directory: %w", err)
+	}
+
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Warnf(ctx, "Unable to remove pod checkpoint directory %s: %v", dir, err)
+		}
+	}()
+
+	if err := os.Mkdir(filepath.Join(dir, PodCheckpointContainersDirectory), 0o700); err != nil {
+		return fmt.Errorf("create pod checkpoint directory: %w", err)
+	}
+
+	config := NewPodCheckpointConfig(sb, opts.Networks)
+
+	for _, ctr := range sb.Containers().List() {
+		if ctr.State().Status != oci.ContainerStateRunning {
+			log.Infof(ctx, "Skipping checkpoint of container %s which is not running", ctr.ID())
+
+			continue
+		}
+
+		name := ctr.Metadata().GetName()
+		if _, err := c.ContainerCheckpoint(
+			ctx,
+			&metadata.ContainerConfig{ID: ctr.ID()},
+			&ContainerCheckpointOptions{
+				TargetFile:  PodCheckpointContainerArchive(dir, name),
+				KeepRunning: true,
+			},
+		); err != nil {
+			return fmt.Errorf("checkpoint container %s of pod sandbox %s: %w", ctr.ID(), sb.ID(), err)
+		}
+
+		config.Containers = append(config.Containers, PodCheckpointContainer{ID: ctr.ID(), Name: name})
+	}
+
+	if len(config.Containers) == 0 {
+		return fmt.Errorf("pod sandbox %s has no running containers", sb.ID())
+	}
+
+	includeFiles := []string{metadata.PodDumpFile, PodCheckpointContainersDirectory}
+
+	// A shm path of /dev/shm means that the pod shares the IPC namespace of
+	// the host, whose shared memory is not part of the pod.
+	if shmPath := sb.ShmPath(); shmPath != "" && shmPath != sandbox.DevShmPath {
+		if err := writeTar(shmPath, filepath.Join(dir, metadata.DevShmCheckpointTar), nil, archive.Uncompressed); err != nil {
+			return fmt.Errorf("write shm of pod sandbox %s: %w", sb.ID(), err)
+		}
+
+		includeFiles = append(includeFiles, metadata.DevShmCheckpointTar)
+	}
+
+	if _, err := metadata.WriteJSONFile(config, dir, metadata.PodDumpFile); err != nil {
+		return fmt.Errorf("write %s: %w", metadata.PodDumpFile, err)
+	}
+
+	if opts.EncryptConfig == nil {
+		if err := writeTar(dir, opts.TargetFile, includeFiles, opts.Compression); err != nil {
+			return fmt.Errorf("write pod checkpoint archive %s: %w", opts.TargetFile, err)
+		}
+
+		return nil
+	}
+
+	// The plain archive is placed next to the checkpoint directory, which
+	// gets removed afterwards.
+	plainArchive := dir + ".tar"
+	if err := writeTar(dir, plainArchive, includeFiles, opts.Compression); err != nil {
+		return fmt.Errorf("write pod checkpoint archive %s: %w", opts.TargetFile, err)
+	}
+
+	defer func() {
+		if err := os.Remove(plainArchive); err != nil {
+			log.Warnf(ctx, "Unable to remove pod checkpoint archive %s: %v", plainArchive, err)
+		}
+	}()
+
+	if err := EncryptCheckpointArchive(opts.EncryptConfig, plainArchive, opts.TargetFile); err != nil {
+		return fmt.Errorf("write pod checkpoint archive %s: %w", opts.TargetFile, err)
+	}
+
+	return nil
+}
+
+// ImportPodCheckpoint extracts a pod checkpoint archive into the directory
+// and returns the stored sandbox metadata.
+func ImportPodCheckpoint(archivePath, dir string) (*PodCheckpointConfig, error) {
+	archiveFile, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("open pod checkpoint archive: %w", err)
+	}
+	defer archiveFile.Close()
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create pod checkpoint directory: %w", err)
+	}
+
+	if err := archive.Untar(archiveFile, dir, &archive.TarOptions{}); err != nil {
+		return nil, fmt.Errorf("unpack pod checkpoint archive %s: %w", archivePath, err)
+	}
+
+	config := &PodCheckpointConfig{}
+	if _, err := metadata.ReadJSONFile(config, dir, metadata.PodDumpFile); err != nil {
+		return nil, fmt.Errorf("read %s: %w", metadata.PodDumpFile, err)
+	}
+
+	return config, nil
+}
+
+// RestorePodShm restores the /dev/shm content of an extracted pod checkpoint
+// into the shm path of the new sandbox. Checkpoints without shm content are
+// ignored.
+func RestorePodShm(dir, shmPath string) error {
+	shmFile, err := os.Open(filepath.Join(dir, metadata.DevShmCheckpointTar))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("open shm checkpoint: %w", err)
+	}
+	defer shmFile.Close()
+
+	if err := archive.Untar(shmFile, shmPath, &archive.TarOptions{}); err != nil {
+		return fmt.Errorf("unpack shm checkpoint into %s: %w", shmPath, err)
+	}
+
+	return nil
+}
+
+// writeTar writes the content of the source directory into the target file,
+// which is not readable by everyone as it may contain memory pages of the
+// checkpointed processes.
+func writeTar(source, target string, includeFiles []string, compression archive.Compression) error {
+	input, err := archive.TarWithOptions(source, &archive.TarOptions{
+		Compression:  compression,
+		IncludeFiles: includeFiles,
+	})
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	outFile, err := os.OpenFile(target, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	_, err = io.Copy(outFile, input)
+
+	return err
+}
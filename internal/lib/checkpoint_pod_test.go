@@ -0,0 +1,181 @@
+package lib_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.podman.io/storage/pkg/archive"
+	"google.golang.org/protobuf/proto"
+	v1 "k8s.io/api/core/v1"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib"
+)
+
+// The actual test suite.
+var _ = t.Describe("PodCheckpoint", func() {
+	// Prepare the sut
+	BeforeEach(func() {
+		beforeEach()
+	})
+
+	writeArchive := func(dir string) string {
+		archivePath := filepath.Join(t.MustTempDir("pod-checkpoint"), "pod.tar")
+		input, err := archive.Tar(dir, archive.Uncompressed)
+		Expect(err).NotTo(HaveOccurred())
+
+		defer input.Close()
+
+		output, err := os.Create(archivePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		defer output.Close()
+
+		_, err = output.ReadFrom(input)
+		Expect(err).NotTo(HaveOccurred())
+
+		return archivePath
+	}
+
+	t.Describe("PodCheckpoint", func() {
+		It("should fail without target file", func() {
+			// Given
+			addContainerAndSandbox()
+
+			// When
+			err := sut.PodCheckpoint(context.Background(), mySandbox, &lib.PodCheckpointOptions{})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail without running containers", func() {
+			// Given
+			addContainerAndSandbox()
+			dir := t.MustTempDir("pod-checkpoint")
+
+			// When
+			err := sut.PodCheckpoint(context.Background(), mySandbox, &lib.PodCheckpointOptions{
+				TargetFile: filepath.Join(dir, "pod.tar"),
+			})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("has no running containers"))
+			Expect(os.ReadDir(dir)).To(BeEmpty())
+		})
+	})
+
+	t.Describe("ImportPodCheckpoint", func() {
+		It("should fail with missing archive", func() {
+			// Given
+			// When
+			res, err := lib.ImportPodCheckpoint("/not-existing", t.MustTempDir("pod-restore"))
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+
+		It("should fail without pod.dump", func() {
+			// Given
+			archivePath := writeArchive(t.MustTempDir("pod-checkpoint"))
+
+			// When
+			res, err := lib.ImportPodCheckpoint(archivePath, t.MustTempDir("pod-restore"))
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+
+		It("should succeed", func() {
+			// Given
+			dir := t.MustTempDir("pod-checkpoint")
+			config := &lib.PodCheckpointConfig{
+				ID:         sandboxID,
+				Name:       "name",
+				Namespace:  "namespace",
+				Containers: []lib.PodCheckpointContainer{{ID: containerID, Name: "ctr"}},
+			}
+			_, err := metadata.WriteJSONFile(config, dir, metadata.PodDumpFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Mkdir(filepath.Join(dir, lib.PodCheckpointContainersDirectory), 0o700)).To(Succeed())
+			Expect(os.WriteFile(lib.PodCheckpointContainerArchive(dir, "ctr"), []byte{}, 0o600)).To(Succeed())
+			archivePath := writeArchive(dir)
+			restoreDir := filepath.Join(t.MustTempDir("pod-restore"), "restore")
+
+			// When
+			res, err := lib.ImportPodCheckpoint(archivePath, restoreDir)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.ID).To(Equal(sandboxID))
+			Expect(res.Containers).To(Equal(config.Containers))
+			Expect(lib.PodCheckpointContainerArchive(restoreDir, "ctr")).To(BeAnExistingFile())
+		})
+
+		It("should round-trip the network configuration", func() {
+			// Given
+			dnsConfig := &types.DNSConfig{
+				Servers:  []string{"10.96.0.10"},
+				Searches: []string{"default.svc.cluster.local"},
+				Options:  []string{"ndots:5"},
+			}
+			mySandbox.SetDNSConfig(dnsConfig)
+			config := lib.NewPodCheckpointConfig(mySandbox, []string{"default"})
+			config.PortMappings = []*hostport.PortMapping{
+				{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP, HostIP: "127.0.0.1"},
+			}
+			dir := t.MustTempDir("pod-checkpoint")
+			_, err := metadata.WriteJSONFile(config, dir, metadata.PodDumpFile)
+			Expect(err).NotTo(HaveOccurred())
+			archivePath := writeArchive(dir)
+
+			// When
+			res, err := lib.ImportPodCheckpoint(archivePath, t.MustTempDir("pod-restore"))
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.DNSConfig).To(Equal(dnsConfig))
+			Expect(proto.Equal(res.DNSConfig, dnsConfig)).To(BeTrue())
+			Expect(res.PortMappings).To(Equal(config.PortMappings))
+			Expect(res.Networks).To(Equal([]string{"default"}))
+		})
+	})
+
+	t.Describe("RestorePodShm", func() {
+		It("should succeed without shm checkpoint", func() {
+			// Given
+			shmPath := t.MustTempDir("shm")
+
+			// When
+			err := lib.RestorePodShm(t.MustTempDir("pod-restore"), shmPath)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.ReadDir(shmPath)).To(BeEmpty())
+		})
+
+		It("should restore the shm content", func() {
+			// Given
+			shmDir := t.MustTempDir("shm-checkpoint")
+			Expect(os.WriteFile(filepath.Join(shmDir, "segment"), []byte("data"), 0o600)).To(Succeed())
+			dir := t.MustTempDir("pod-restore")
+			Expect(os.Rename(writeArchive(shmDir), filepath.Join(dir, metadata.DevShmCheckpointTar))).To(Succeed())
+			shmPath := t.MustTempDir("shm")
+
+			// When
+			err := lib.RestorePodShm(dir, shmPath)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.ReadFile(filepath.Join(shmPath, "segment"))).To(Equal([]byte("data")))
+		})
+	})
+})
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	metadata "github.com/checkpoint-restore/checkpointctl/lib"
 	criu "github.com/checkpoint-restore/go-criu/v8/utils"
+	encconfig "github.com/containers/ocicrypt/config"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -87,6 +89,60 @@ var _ = t.Describe("ContainerCheckpoint", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(res).To(Equal(config.ID))
 		})
+
+		It("should succeed with pre-dumps", func() {
+			// Given
+			argsFile := mockRuntimeRecordingArgsInLibConfig()
+			addContainerAndSandbox()
+
+			config := &metadata.ContainerConfig{
+				ID: containerID,
+			}
+
+			myContainer.SetState(&oci.ContainerState{
+				State: specs.State{Status: oci.ContainerStateRunning},
+			})
+			myContainer.SetSpec(&specs.Spec{Version: "1.0.0"})
+
+			gomock.InOrder(
+				storeMock.EXPECT().Container(gomock.Any()).Return(&cstorage.Container{}, nil),
+				storeMock.EXPECT().Unmount(gomock.Any(), gomock.Any()).Return(true, nil),
+			)
+
+			// When
+			res, err := sut.ContainerCheckpoint(
+				context.Background(),
+				config,
+				&lib.ContainerCheckpointOptions{PreDumpIterations: 2},
+			)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(config.ID))
+
+			args, err := os.ReadFile(argsFile)
+			Expect(err).ToNot(HaveOccurred())
+
+			var preDumps, dumps []string
+
+			for line := range strings.SplitSeq(strings.TrimSpace(string(args)), "\n") {
+				switch {
+				case !strings.Contains(line, " checkpoint ") || strings.Contains(line, "--help"):
+				case strings.Contains(line, "--pre-dump"):
+					preDumps = append(preDumps, line)
+				default:
+					dumps = append(dumps, line)
+				}
+			}
+
+			Expect(preDumps).To(HaveLen(2))
+			Expect(preDumps[0]).To(ContainSubstring("--image-path pre-checkpoint-1 "))
+			Expect(preDumps[0]).NotTo(ContainSubstring("--parent-path"))
+			Expect(preDumps[1]).To(ContainSubstring("--image-path pre-checkpoint-2 "))
+			Expect(preDumps[1]).To(ContainSubstring("--parent-path ../pre-checkpoint-1 "))
+			Expect(dumps).To(HaveLen(1))
+			Expect(dumps[0]).To(ContainSubstring("--parent-path ../pre-checkpoint-2 "))
+		})
 	})
 	t.Describe("ContainerCheckpoint", func() {
 		It("should fail because runtime failure (/bin/false)", func() {
@@ -236,6 +292,33 @@ var _ = t.Describe("ContainerCheckpoint", func() {
 			Expect(err.Error()).To(Equal(`failed to find container invalid: container with ID starting with invalid not found: ID does not exist`))
 		})
 	})
+	t.Describe("ContainerCheckpoint", func() {
+		It("should fail to encrypt a local checkpoint image", func() {
+			// Given
+			imageName, push, err := lib.ParseCheckpointImageLocation("containers-storage:localhost/checkpoint")
+			Expect(err).ToNot(HaveOccurred())
+
+			config := &metadata.ContainerConfig{
+				ID: containerID,
+			}
+
+			// When
+			res, err := sut.ContainerCheckpoint(
+				context.Background(),
+				config,
+				&lib.ContainerCheckpointOptions{
+					ImageName:     imageName,
+					Push:          push,
+					EncryptConfig: &encconfig.EncryptConfig{},
+				},
+			)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(Equal(""))
+			Expect(err.Error()).To(ContainSubstring("can not be encrypted"))
+		})
+	})
 	t.Describe("ContainerCheckpoint", func() {
 		It("should fail with invalid config", func() {
 			// Given
@@ -360,6 +360,21 @@ func (c *ContainerServer) LoadSandbox(ctx context.Context, id string) (sb *sandb
 		}
 	}
 
+	var dnsCacheUpstreams []string
+	if v, found := m.Annotations[annotations.DNSCacheUpstreams]; found {
+		if err := json.Unmarshal([]byte(v), &dnsCacheUpstreams); err != nil {
+			return nil, fmt.Errorf("error unmarshalling %s annotation: %w", annotations.DNSCacheUpstreams, err)
+		}
+	}
+
+	var dnsConfig *types.DNSConfig
+	if v, found := m.Annotations[annotations.DNSConfig]; found {
+		dnsConfig = &types.DNSConfig{}
+		if err := json.Unmarshal([]byte(v), dnsConfig); err != nil {
+			return nil, fmt.Errorf("error unmarshalling %s annotation: %w", annotations.DNSConfig, err)
+		}
+	}
+
 	sbox.SetLogDir(filepath.Dir(m.Annotations[annotations.LogPath]))
 	sbox.SetContainers(memorystore.New[*oci.Container]())
 	sbox.SetShmPath(m.Annotations[annotations.ShmPath])
@@ -393,6 +408,9 @@ func (c *ContainerServer) LoadSandbox(ctx context.Context, id string) (sb *sandb
 		return nil, err
 	}
 
+	sb.SetDNSCacheUpstreams(dnsCacheUpstreams)
+	sb.SetDNSConfig(dnsConfig)
+
 	defer func() {
 		if retErr != nil {
 			if err := sb.RemoveManagedNamespaces(); err != nil {
@@ -459,6 +477,10 @@ func (c *ContainerServer) LoadSandbox(ctx context.Context, id string) (sb *sandb
 	scontainer.SetSpec(&m)
 	scontainer.SetMountPoint(m.Annotations[annotations.MountPoint])
 
+	if runtimePath, ok := v2.GetAnnotationValue(m.Annotations, v2.PlatformRuntimePath); ok {
+		scontainer.SetRuntimePathForPlatform(runtimePath)
+	}
+
 	// Restore ID mappings from the OCI spec if user namespace is in use
 	if m.Linux != nil && len(m.Linux.UIDMappings) > 0 && len(m.Linux.GIDMappings) > 0 {
 		if mappings := ConvertOCIToStorageIDMappings(m.Linux.UIDMappings, m.Linux.GIDMappings); mappings != nil {
@@ -652,7 +674,14 @@ func (c *ContainerServer) LoadContainer(ctx context.Context, id string) (retErr
 		someRepoDigest = strings.SplitN(repoDigests, ",", 2)[0]
 	}
 
-	ctr, err := oci.NewContainer(id, name, containerPath, m.Annotations[annotations.LogPath], labels, m.Annotations, kubeAnnotations, userRequestedImage, someNameOfTheImage, imageID, someRepoDigest, &metadata, sb.ID(), tty, stdin, stdinOnce, sb.RuntimeHandler(), containerDir, created, stopSignal)
+	// Containers of WebAssembly images may be run by another runtime handler
+	// than the pod sandbox.
+	runtimeHandler := sb.RuntimeHandler()
+	if handler, ok := m.Annotations[annotations.RuntimeHandler]; ok && handler != "" {
+		runtimeHandler = handler
+	}
+
+	ctr, err := oci.NewContainer(id, name, containerPath, m.Annotations[annotations.LogPath], labels, m.Annotations, kubeAnnotations, userRequestedImage, someNameOfTheImage, imageID, someRepoDigest, &metadata, sb.ID(), tty, stdin, stdinOnce, runtimeHandler, containerDir, created, stopSignal)
 	if err != nil {
 		return err
 	}
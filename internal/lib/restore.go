@@ -98,6 +98,17 @@ func (c *ContainerServer) ContainerRestore(
 				"bind.mounts",
 				annotations.LogPath,
 			}
+
+			// The checkpoint refers to the memory pages of its pre-dumps.
+			preDumpDirs, err := filepath.Glob(filepath.Join(imageMountPoint, preCheckpointDirectoryPrefix+"*"))
+			if err != nil {
+				return "", err
+			}
+
+			for _, dir := range preDumpDirs {
+				checkpoint = append(checkpoint, filepath.Base(dir))
+			}
+
 			for _, name := range checkpoint {
 				src := filepath.Join(imageMountPoint, name)
 				dst := filepath.Join(ctr.Dir(), name)
@@ -107,7 +118,20 @@ func (c *ContainerServer) ContainerRestore(
 				}
 			}
 		} else {
-			if err := crutils.CRImportCheckpointWithoutConfig(ctr.Dir(), ctr.RestoreArchivePath()); err != nil {
+			archivePath, err := DecryptCheckpointArchive(opts.DecryptConfig, ctr.RestoreArchivePath(), ctr.Dir())
+			if err != nil {
+				return "", err
+			}
+
+			if archivePath != ctr.RestoreArchivePath() {
+				defer func() {
+					if err := os.Remove(archivePath); err != nil {
+						log.Warnf(ctx, "Unable to remove decrypted checkpoint archive %s: %v", archivePath, err)
+					}
+				}()
+			}
+
+			if err := crutils.CRImportCheckpointWithoutConfig(ctr.Dir(), archivePath); err != nil {
 				return "", err
 			}
 		}
@@ -355,6 +355,7 @@ func (b *sandboxBuilder) ResolvPath() string {
 // SetDNSConfig sets the DNSConfig.
 func (b *sandboxBuilder) SetDNSConfig(dnsConfig *types.DNSConfig) {
 	b.config.DnsConfig = dnsConfig
+	b.sandboxRef.dnsConfig = dnsConfig
 }
 
 // SetCRISandbox sets the CRISandbox.
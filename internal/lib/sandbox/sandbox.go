@@ -56,6 +56,7 @@ type Sandbox struct {
 	// ipv4 or ipv6 cache
 	ips                []string
 	seccompProfilePath string
+	dnsCacheUpstreams  []string
 	infraContainer     *oci.Container
 	nsOpts             *types.NamespaceOption
 	dnsConfig          *types.DNSConfig
@@ -108,6 +109,18 @@ func (s *Sandbox) SeccompProfilePath() string {
 	return s.seccompProfilePath
 }
 
+// SetDNSCacheUpstreams sets the upstream nameservers of the DNS cache for
+// the sandbox.
+func (s *Sandbox) SetDNSCacheUpstreams(upstreams []string) {
+	s.dnsCacheUpstreams = upstreams
+}
+
+// DNSCacheUpstreams returns the upstream nameservers of the DNS cache for the
+// sandbox. It returns nil if the sandbox does not use the DNS cache.
+func (s *Sandbox) DNSCacheUpstreams() []string {
+	return s.dnsCacheUpstreams
+}
+
 // AddIPs stores the ip in the sandbox.
 func (s *Sandbox) AddIPs(ips []string) {
 	s.ips = ips
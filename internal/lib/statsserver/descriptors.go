@@ -328,3 +328,32 @@ var (
 		LabelKeys: baseLabelKeys,
 	}
 )
+
+// Pod accounting metrics.
+var (
+	containerPodContainersCPUUsageNanoCores = &types.MetricDescriptor{
+		Name:      "container_pod_containers_cpu_usage_nano_cores",
+		Help:      "CPU usage of all containers of the pod without infra container in nano cores.",
+		LabelKeys: baseLabelKeys,
+	}
+	containerPodContainersMemoryWorkingSetBytes = &types.MetricDescriptor{
+		Name:      "container_pod_containers_memory_working_set_bytes",
+		Help:      "Working set of all containers of the pod without infra container in bytes.",
+		LabelKeys: baseLabelKeys,
+	}
+	containerPodOverheadCPUUsageNanoCores = &types.MetricDescriptor{
+		Name:      "container_pod_overhead_cpu_usage_nano_cores",
+		Help:      "CPU usage of the pod cgroup beyond its containers in nano cores.",
+		LabelKeys: baseLabelKeys,
+	}
+	containerPodOverheadMemoryWorkingSetBytes = &types.MetricDescriptor{
+		Name:      "container_pod_overhead_memory_working_set_bytes",
+		Help:      "Working set of the pod cgroup beyond its containers in bytes.",
+		LabelKeys: baseLabelKeys,
+	}
+	containerPodOverheadExceeded = &types.MetricDescriptor{
+		Name:      "container_pod_overhead_exceeded",
+		Help:      "1 if the usage of the pod cgroup beyond its containers exceeds the declared pod overhead, 0 otherwise.",
+		LabelKeys: baseLabelKeys,
+	}
+)
@@ -8,6 +8,7 @@ import (
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/oci"
 	"github.com/cri-o/cri-o/pkg/config"
+	criotypes "github.com/cri-o/cri-o/pkg/types"
 )
 
 var (
@@ -31,6 +32,9 @@ type containerMetric struct {
 
 type SandboxMetrics struct {
 	metric *types.PodSandboxMetrics
+	// podAccounting is the latest accounting of a pod without infra
+	// container, nil for other pods.
+	podAccounting *criotypes.PodAccounting
 }
 
 func (s *SandboxMetrics) GetMetric() *types.PodSandboxMetrics {
@@ -128,6 +132,13 @@ var availableMetricDescriptors = map[string][]*types.MetricDescriptor{
 		containerPressureIOStalledSecondsTotal,
 		containerPressureIOWaitingSecondsTotal,
 	},
+	config.PodAccountingMetrics: {
+		containerPodContainersCPUUsageNanoCores,
+		containerPodContainersMemoryWorkingSetBytes,
+		containerPodOverheadCPUUsageNanoCores,
+		containerPodOverheadMemoryWorkingSetBytes,
+		containerPodOverheadExceeded,
+	},
 }
 
 // PopulateMetricDescriptors stores metricdescriptors statically at startup and populates the list.
@@ -10,6 +10,7 @@ import (
 
 	"github.com/cri-o/cri-o/internal/lib/stats"
 	"github.com/cri-o/cri-o/internal/oci"
+	criotypes "github.com/cri-o/cri-o/pkg/types"
 )
 
 // TestMetricLabelCardinality verifies that every metric produced by each
@@ -58,6 +59,7 @@ func TestMetricLabelCardinality(t *testing.T) {
 		{"process", generateContainerProcessMetrics(ctr, &pidsStats, &processStats)},
 		{"spec", generateContainerSpecMetrics(ctr)},
 		{"pressure", generateContainerPressureMetrics(ctr, &cpuStats, &memStats, &blkioStats)},
+		{"podAccounting", generatePodAccountingMetrics(newTestSandbox(t, "sb"), &criotypes.PodAccounting{})},
 	}
 
 	for _, tt := range tests {
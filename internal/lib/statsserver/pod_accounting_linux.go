@@ -0,0 +1,144 @@
+package statsserver
+
+import (
+	"encoding/json"
+	"maps"
+	"time"
+
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/cri-o/cri-o/internal/annotations"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	criotypes "github.com/cri-o/cri-o/pkg/types"
+)
+
+// cpuSharesPerCore are the CPU shares of a single core.
+const cpuSharesPerCore = 1024
+
+// hasInfraProcess returns false if the sandbox has no infra process, which is
+// the case if drop_infra_ctr applied to it.
+func hasInfraProcess(sb *sandbox.Sandbox) bool {
+	infra := sb.InfraContainer()
+
+	return infra == nil || !infra.Spoofed()
+}
+
+// computePodAccounting sums the usage of the containers of a pod without
+// infra process and attributes the remaining usage of the pod cgroup to the
+// pod overhead, which is compared to the declared one. It returns nil for
+// pods with infra process.
+func computePodAccounting(sb *sandbox.Sandbox, sboxStats *types.PodSandboxStats) *criotypes.PodAccounting {
+	if hasInfraProcess(sb) {
+		return nil
+	}
+
+	accounting := &criotypes.PodAccounting{}
+
+	for _, ctrStats := range sboxStats.GetLinux().GetContainers() {
+		accounting.ContainersCPUUsageNanoCores += ctrStats.GetCpu().GetUsageNanoCores().GetValue()
+		accounting.ContainersMemoryWorkingSetBytes += ctrStats.GetMemory().GetWorkingSetBytes().GetValue()
+	}
+
+	podCPU := sboxStats.GetLinux().GetCpu().GetUsageNanoCores().GetValue()
+	if podCPU > accounting.ContainersCPUUsageNanoCores {
+		accounting.OverheadCPUUsageNanoCores = podCPU - accounting.ContainersCPUUsageNanoCores
+	}
+
+	podMemory := sboxStats.GetLinux().GetMemory().GetWorkingSetBytes().GetValue()
+	if podMemory > accounting.ContainersMemoryWorkingSetBytes {
+		accounting.OverheadMemoryWorkingSetBytes = podMemory - accounting.ContainersMemoryWorkingSetBytes
+	}
+
+	if overhead := sb.PodLinuxOverhead(); overhead != nil {
+		switch {
+		case overhead.GetCpuQuota() > 0 && overhead.GetCpuPeriod() > 0:
+			accounting.DeclaredOverheadCPUNanoCores = uint64(overhead.GetCpuQuota()) * uint64(time.Second) / uint64(overhead.GetCpuPeriod())
+		case overhead.GetCpuShares() > 0:
+			accounting.DeclaredOverheadCPUNanoCores = uint64(overhead.GetCpuShares()) * uint64(time.Second) / cpuSharesPerCore
+		}
+
+		if overhead.GetMemoryLimitInBytes() > 0 {
+			accounting.DeclaredOverheadMemoryLimitBytes = uint64(overhead.GetMemoryLimitInBytes())
+		}
+	}
+
+	accounting.OverheadExceeded = (accounting.DeclaredOverheadCPUNanoCores > 0 &&
+		accounting.OverheadCPUUsageNanoCores > accounting.DeclaredOverheadCPUNanoCores) ||
+		(accounting.DeclaredOverheadMemoryLimitBytes > 0 &&
+			accounting.OverheadMemoryWorkingSetBytes > accounting.DeclaredOverheadMemoryLimitBytes)
+
+	return accounting
+}
+
+// updatePodAccounting computes the accounting of a pod without infra process,
+// records it in the sandbox metrics and adds it to the annotations of the
+// sandbox stats attributes.
+// Note: caller must hold the lock on the StatsServer.
+func (ss *StatsServer) updatePodAccounting(sb *sandbox.Sandbox, sboxStats *types.PodSandboxStats, sm *SandboxMetrics) {
+	accounting := computePodAccounting(sb, sboxStats)
+
+	previous := sm.podAccounting
+	sm.podAccounting = accounting
+
+	if accounting == nil {
+		return
+	}
+
+	// Only warn when the pod starts to exceed its overhead, the metrics carry
+	// the state of every collection.
+	if overheadExceededTransition(previous, accounting) {
+		log.Warnf(ss.ctx, "Usage of pod sandbox %s beyond its containers exceeds the declared pod overhead", sb.ID())
+	}
+
+	content, err := json.Marshal(accounting)
+	if err != nil {
+		log.Errorf(ss.ctx, "Unable to marshal pod accounting of sandbox %s: %v", sb.ID(), err)
+
+		return
+	}
+
+	// The annotations are shared with the sandbox, so they must not be modified.
+	attributesAnnotations := maps.Clone(sboxStats.GetAttributes().GetAnnotations())
+	if attributesAnnotations == nil {
+		attributesAnnotations = map[string]string{}
+	}
+
+	attributesAnnotations[annotations.PodAccounting] = string(content)
+	sboxStats.Attributes.Annotations = attributesAnnotations
+}
+
+// overheadExceededTransition returns true if the current accounting exceeds
+// the declared pod overhead while the previous one did not.
+func overheadExceededTransition(previous, current *criotypes.PodAccounting) bool {
+	return current.OverheadExceeded && (previous == nil || !previous.OverheadExceeded)
+}
+
+// generatePodAccountingMetrics generates the pod accounting metrics of a pod
+// without infra process.
+func generatePodAccountingMetrics(sb *sandbox.Sandbox, accounting *criotypes.PodAccounting) []*types.Metric {
+	var exceeded uint64
+	if accounting.OverheadExceeded {
+		exceeded = 1
+	}
+
+	gauge := func(desc *types.MetricDescriptor, value uint64) *containerMetric {
+		return &containerMetric{
+			desc: desc,
+			valueFunc: func() metricValues {
+				return metricValues{{
+					value:      value,
+					metricType: types.MetricType_GAUGE,
+				}}
+			},
+		}
+	}
+
+	return computeSandboxMetrics(sb, []*containerMetric{
+		gauge(containerPodContainersCPUUsageNanoCores, accounting.ContainersCPUUsageNanoCores),
+		gauge(containerPodContainersMemoryWorkingSetBytes, accounting.ContainersMemoryWorkingSetBytes),
+		gauge(containerPodOverheadCPUUsageNanoCores, accounting.OverheadCPUUsageNanoCores),
+		gauge(containerPodOverheadMemoryWorkingSetBytes, accounting.OverheadMemoryWorkingSetBytes),
+		gauge(containerPodOverheadExceeded, exceeded),
+	})
+}
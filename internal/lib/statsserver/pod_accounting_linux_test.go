@@ -0,0 +1,268 @@
+package statsserver
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/cri-o/cri-o/internal/annotations"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/memorystore"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/pkg/config"
+	criotypes "github.com/cri-o/cri-o/pkg/types"
+)
+
+func newTestSandboxWithoutInfra(t *testing.T, overhead *types.LinuxContainerResources) *sandbox.Sandbox {
+	t.Helper()
+
+	b := sandbox.NewBuilder()
+	b.SetID("sb")
+	b.SetName("test-sandbox")
+	b.SetLogDir("/tmp")
+	b.SetShmPath("/dev/shm")
+	b.SetNamespace("default")
+	b.SetKubeName("test-pod")
+	b.SetMountLabel("")
+	b.SetProcessLabel("")
+	b.SetCgroupParent("/kubepods/test")
+	b.SetRuntimeHandler("runc")
+	b.SetResolvPath("/etc/resolv.conf")
+	b.SetHostname("test-host")
+	b.SetPortMappings(nil)
+	b.SetPrivileged(false)
+	b.SetHostNetwork(false)
+	b.SetUsernsMode("")
+	b.SetPodLinuxOverhead(overhead)
+	b.SetPodLinuxResources(nil)
+	b.SetCreatedAt(time.Now())
+	b.SetContainers(memorystore.New[*oci.Container]())
+
+	if err := b.SetCRISandbox("sb", nil, map[string]string{"foo": "bar"}, &types.PodSandboxMetadata{Name: "test-pod", Namespace: "default"}); err != nil {
+		t.Fatalf("SetCRISandbox: %v", err)
+	}
+
+	sb, err := b.GetSandbox()
+	if err != nil {
+		t.Fatalf("GetSandbox: %v", err)
+	}
+
+	if err := sb.SetInfraContainer(oci.NewSpoofedContainer("sb", "infra", nil, "sb", time.Now(), "")); err != nil {
+		t.Fatalf("SetInfraContainer: %v", err)
+	}
+
+	return sb
+}
+
+func testPodSandboxStats(sb *sandbox.Sandbox, podCPU, podMemory uint64, containers ...[2]uint64) *types.PodSandboxStats {
+	sboxStats := &types.PodSandboxStats{
+		Attributes: &types.PodSandboxAttributes{
+			Id:          sb.ID(),
+			Annotations: sb.Annotations(),
+		},
+		Linux: &types.LinuxPodSandboxStats{
+			Cpu:    &types.CpuUsage{UsageNanoCores: &types.UInt64Value{Value: podCPU}},
+			Memory: &types.MemoryUsage{WorkingSetBytes: &types.UInt64Value{Value: podMemory}},
+		},
+	}
+
+	for _, usage := range containers {
+		sboxStats.Linux.Containers = append(sboxStats.Linux.Containers, &types.ContainerStats{
+			Cpu:    &types.CpuUsage{UsageNanoCores: &types.UInt64Value{Value: usage[0]}},
+			Memory: &types.MemoryUsage{WorkingSetBytes: &types.UInt64Value{Value: usage[1]}},
+		})
+	}
+
+	return sboxStats
+}
+
+func TestComputePodAccounting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		overhead *types.LinuxContainerResources
+		podCPU   uint64
+		podMem   uint64
+		expected criotypes.PodAccounting
+	}{
+		{
+			name:   "without declared overhead",
+			podCPU: 400_000_000,
+			podMem: 300 << 20,
+			expected: criotypes.PodAccounting{
+				ContainersCPUUsageNanoCores:     300_000_000,
+				ContainersMemoryWorkingSetBytes: 250 << 20,
+				OverheadCPUUsageNanoCores:       100_000_000,
+				OverheadMemoryWorkingSetBytes:   50 << 20,
+			},
+		},
+		{
+			name:     "within declared overhead",
+			overhead: &types.LinuxContainerResources{CpuPeriod: 100000, CpuQuota: 20000, MemoryLimitInBytes: 100 << 20},
+			podCPU:   400_000_000,
+			podMem:   300 << 20,
+			expected: criotypes.PodAccounting{
+				ContainersCPUUsageNanoCores:      300_000_000,
+				ContainersMemoryWorkingSetBytes:  250 << 20,
+				OverheadCPUUsageNanoCores:        100_000_000,
+				OverheadMemoryWorkingSetBytes:    50 << 20,
+				DeclaredOverheadCPUNanoCores:     200_000_000,
+				DeclaredOverheadMemoryLimitBytes: 100 << 20,
+			},
+		},
+		{
+			name:     "exceeding declared CPU shares",
+			overhead: &types.LinuxContainerResources{CpuShares: 51},
+			podCPU:   400_000_000,
+			podMem:   300 << 20,
+			expected: criotypes.PodAccounting{
+				ContainersCPUUsageNanoCores:     300_000_000,
+				ContainersMemoryWorkingSetBytes: 250 << 20,
+				OverheadCPUUsageNanoCores:       100_000_000,
+				OverheadMemoryWorkingSetBytes:   50 << 20,
+				DeclaredOverheadCPUNanoCores:    49_804_687,
+				OverheadExceeded:                true,
+			},
+		},
+		{
+			name:     "pod usage below containers usage",
+			overhead: &types.LinuxContainerResources{MemoryLimitInBytes: 10 << 20},
+			podCPU:   200_000_000,
+			podMem:   200 << 20,
+			expected: criotypes.PodAccounting{
+				ContainersCPUUsageNanoCores:      300_000_000,
+				ContainersMemoryWorkingSetBytes:  250 << 20,
+				DeclaredOverheadMemoryLimitBytes: 10 << 20,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sb := newTestSandboxWithoutInfra(t, tt.overhead)
+			sboxStats := testPodSandboxStats(sb, tt.podCPU, tt.podMem,
+				[2]uint64{100_000_000, 100 << 20}, [2]uint64{200_000_000, 150 << 20})
+
+			res := computePodAccounting(sb, sboxStats)
+			if res == nil {
+				t.Fatal("expected pod accounting")
+			}
+
+			if *res != tt.expected {
+				t.Errorf("got %+v, want %+v", *res, tt.expected)
+			}
+		})
+	}
+}
+
+func TestComputePodAccountingWithInfraProcess(t *testing.T) {
+	t.Parallel()
+
+	sb := newTestSandbox(t, "sb")
+
+	if res := computePodAccounting(sb, testPodSandboxStats(sb, 1, 1)); res != nil {
+		t.Errorf("expected no pod accounting, got %+v", res)
+	}
+}
+
+func TestUpdatePodAccounting(t *testing.T) {
+	t.Parallel()
+
+	ss := newTestStatsServer(t, nil, nil)
+	sb := newTestSandboxWithoutInfra(t, &types.LinuxContainerResources{MemoryLimitInBytes: 10 << 20})
+	sboxStats := testPodSandboxStats(sb, 0, 100<<20, [2]uint64{0, 50 << 20})
+	sm := NewSandboxMetrics(sb)
+
+	ss.updatePodAccounting(sb, sboxStats, sm)
+
+	if sm.podAccounting == nil || !sm.podAccounting.OverheadExceeded {
+		t.Fatalf("expected exceeded pod accounting, got %+v", sm.podAccounting)
+	}
+
+	value, ok := sboxStats.GetAttributes().GetAnnotations()[annotations.PodAccounting]
+	if !ok {
+		t.Fatal("expected pod accounting annotation in the sandbox stats")
+	}
+
+	accounting := &criotypes.PodAccounting{}
+	if err := json.Unmarshal([]byte(value), accounting); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if *accounting != *sm.podAccounting {
+		t.Errorf("got %+v, want %+v", *accounting, *sm.podAccounting)
+	}
+
+	if _, ok := sb.Annotations()[annotations.PodAccounting]; ok {
+		t.Error("expected the sandbox annotations to be unchanged")
+	}
+}
+
+func TestOverheadExceededTransition(t *testing.T) {
+	t.Parallel()
+
+	exceeded := &criotypes.PodAccounting{OverheadExceeded: true}
+	notExceeded := &criotypes.PodAccounting{}
+
+	for _, tc := range []struct {
+		name              string
+		previous, current *criotypes.PodAccounting
+		want              bool
+	}{
+		{name: "first exceeded collection", current: exceeded, want: true},
+		{name: "entering exceeded state", previous: notExceeded, current: exceeded, want: true},
+		{name: "staying in exceeded state", previous: exceeded, current: exceeded},
+		{name: "leaving exceeded state", previous: exceeded, current: notExceeded},
+		{name: "never exceeded", previous: notExceeded, current: notExceeded},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := overheadExceededTransition(tc.previous, tc.current); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdatePodSandboxMetricsPodAccounting(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		t.Fatalf("DefaultConfig: %v", err)
+	}
+
+	cfg.IncludedPodMetrics = []string{config.PodAccountingMetrics}
+	if err := cfg.StatsConfig.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	rt := oci.NewTestRuntime()
+	sb := newTestSandboxWithoutInfra(t, nil)
+	ss := newTestStatsServer(t, rt, cfg)
+
+	// A stale accounting left by an earlier collection.
+	stale := NewSandboxMetrics(sb)
+	stale.podAccounting = &criotypes.PodAccounting{OverheadExceeded: true}
+	ss.sboxMetrics[sb.ID()] = stale
+
+	sm := ss.updatePodSandboxMetrics(sb)
+
+	if sm == nil || sm.podAccounting == nil {
+		t.Fatalf("expected pod accounting in the sandbox metrics, got %+v", sm)
+	}
+
+	if sm.podAccounting.OverheadExceeded {
+		t.Errorf("expected the pod accounting to be refreshed, got %+v", *sm.podAccounting)
+	}
+
+	if _, ok := ss.sboxStats[sb.ID()]; !ok {
+		t.Error("expected the sandbox stats to be collected")
+	}
+}
@@ -3,6 +3,7 @@ package statsserver
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -13,22 +14,47 @@ import (
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/oci"
 	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/server/metrics"
+)
+
+const (
+	// statsCollectionModePeriodic collects the stats of all containers every
+	// collection period.
+	statsCollectionModePeriodic = "periodic"
+
+	// statsCollectionModeAdaptive collects the stats of busy containers in
+	// between the periodic collections.
+	statsCollectionModeAdaptive = "adaptive"
+
+	// statsCollectionModeOnDemand collects the stats when requested.
+	statsCollectionModeOnDemand = "on_demand"
+
+	// nanoCoresPerMilliCore converts the CPU usage in millicores to nano cores.
+	nanoCoresPerMilliCore = 1000 * 1000
 )
 
 // StatsServer is responsible for maintaining a list of container and sandbox stats.
-// If collectionPeriod is > 0, it maintains this list by updating the stats on collectionPeriod frequency.
-// Otherwise, it only updates the stats as they're requested.
+// If collectionPeriod is > 0, it maintains this list by updating the stats on collectionPeriod frequency,
+// and the stats of busy containers on adaptivePeriod frequency if set.
+// Otherwise, it only updates the stats as they're requested, reusing them within the onDemandCacheDuration.
 type StatsServer struct {
 	parentServerIface
 
-	shutdown         chan struct{}
-	alreadyShutdown  bool
-	collectionPeriod time.Duration
-	sboxStats        map[string]*types.PodSandboxStats
-	ctrStats         map[string]*types.ContainerStats
-	sboxMetrics      map[string]*SandboxMetrics
-	ctx              context.Context
-	mutex            sync.Mutex
+	shutdown              chan struct{}
+	alreadyShutdown       bool
+	collectionPeriod      time.Duration
+	adaptivePeriod        time.Duration
+	adaptiveCPUThreshold  uint64
+	onDemandCacheDuration time.Duration
+	sboxStats             map[string]*types.PodSandboxStats
+	ctrStats              map[string]*types.ContainerStats
+	sboxMetrics           map[string]*SandboxMetrics
+	// collectedAt is the time the stats of a sandbox or container were collected.
+	collectedAt map[string]time.Time
+	// cgroupsRead is the number of cgroups read by the stats collections.
+	cgroupsRead int
+	ctx         context.Context
+	mutex       sync.Mutex
 }
 
 // parentServerIface is an interface for requesting information from the parent ContainerServer.
@@ -45,21 +71,26 @@ type parentServerIface interface {
 // New returns a new StatsServer, deriving the needed information from the provided parentServerIface.
 func New(ctx context.Context, cs parentServerIface) *StatsServer {
 	ss := &StatsServer{
-		shutdown:          make(chan struct{}, 1),
-		alreadyShutdown:   false,
-		collectionPeriod:  time.Duration(cs.Config().CollectionPeriod) * time.Second,
-		sboxStats:         make(map[string]*types.PodSandboxStats),
-		ctrStats:          make(map[string]*types.ContainerStats),
-		sboxMetrics:       make(map[string]*SandboxMetrics),
-		parentServerIface: cs,
-		ctx:               ctx,
+		shutdown:              make(chan struct{}, 1),
+		alreadyShutdown:       false,
+		collectionPeriod:      time.Duration(cs.Config().CollectionPeriod) * time.Second,
+		adaptivePeriod:        time.Duration(cs.Config().AdaptiveCollectionPeriod) * time.Second,
+		adaptiveCPUThreshold:  uint64(cs.Config().AdaptiveCollectionCPUThreshold) * nanoCoresPerMilliCore,
+		onDemandCacheDuration: cs.Config().OnDemandCacheDuration,
+		sboxStats:             make(map[string]*types.PodSandboxStats),
+		ctrStats:              make(map[string]*types.ContainerStats),
+		sboxMetrics:           make(map[string]*SandboxMetrics),
+		collectedAt:           make(map[string]time.Time),
+		parentServerIface:     cs,
+		ctx:                   ctx,
 	}
 	go ss.updateLoop()
 
 	return ss
 }
 
-// updateLoop updates the current list of stats every collectionPeriod seconds.
+// updateLoop updates the current list of stats every collectionPeriod seconds,
+// and the stats of busy containers every adaptivePeriod seconds in between.
 // If collectionPeriod is 0, it does nothing.
 func (ss *StatsServer) updateLoop() {
 	if ss.collectionPeriod == 0 {
@@ -67,14 +98,27 @@ func (ss *StatsServer) updateLoop() {
 		return
 	}
 
+	period := ss.collectionPeriod
+	if ss.adaptivePeriod > 0 {
+		period = ss.adaptivePeriod
+	}
+
+	lastUpdate := time.Now()
+
 	for {
 		select {
 		case <-ss.shutdown:
 			return
-		case <-time.After(ss.collectionPeriod):
+		case <-time.After(period):
 		}
 
-		ss.update()
+		if time.Since(lastUpdate) >= ss.collectionPeriod {
+			ss.update()
+
+			lastUpdate = time.Now()
+		} else {
+			ss.updateBusyContainers()
+		}
 	}
 }
 
@@ -85,9 +129,90 @@ func (ss *StatsServer) update() {
 	ss.mutex.Lock()
 	defer ss.mutex.Unlock()
 
-	for _, sb := range ss.ListSandboxes() {
-		ss.updateSandbox(sb)
-	}
+	ss.observeCollection(statsCollectionModePeriodic, func() {
+		for _, sb := range ss.ListSandboxes() {
+			ss.updateSandbox(sb)
+		}
+	})
+}
+
+// updateBusyContainers updates the stats of the containers whose CPU usage
+// is above the adaptive CPU threshold, as well as the stats of their sandbox.
+func (ss *StatsServer) updateBusyContainers() {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	ss.observeCollection(statsCollectionModeAdaptive, func() {
+		for _, sb := range ss.ListSandboxes() {
+			sboxStats, ok := ss.sboxStats[sb.ID()]
+			if !ok {
+				continue
+			}
+
+			// The containers of the sandbox are stored by name, while their
+			// stats only carry the container ID.
+			containers := make(map[string]*oci.Container)
+			for _, c := range sb.Containers().List() {
+				containers[c.ID()] = c
+			}
+
+			// The stats of the sandbox may already be returned to callers,
+			// so the updated container stats go into a copy.
+			var containerStats []*types.ContainerStats
+
+			for i, ctrStats := range sboxStats.GetLinux().GetContainers() {
+				if !ss.isBusy(ctrStats) {
+					continue
+				}
+
+				c, ok := containers[ctrStats.GetAttributes().GetId()]
+				if !ok {
+					continue
+				}
+
+				if updated := ss.updateContainerStats(c, sb); updated != nil {
+					if containerStats == nil {
+						containerStats = slices.Clone(sboxStats.GetLinux().GetContainers())
+					}
+
+					containerStats[i] = updated
+				}
+			}
+
+			if containerStats != nil {
+				ss.updateBusySandbox(sb, sboxStats, containerStats)
+			}
+		}
+	})
+}
+
+// isBusy returns whether the CPU usage of the container stats is above the
+// adaptive CPU threshold.
+func (ss *StatsServer) isBusy(ctrStats *types.ContainerStats) bool {
+	usage := ctrStats.GetCpu().GetUsageNanoCores()
+
+	return usage != nil && usage.GetValue() >= ss.adaptiveCPUThreshold
+}
+
+// observeCollection runs the stats collection and reports its duration and
+// the number of cgroups read.
+// Note: caller must hold the lock on the StatsServer.
+func (ss *StatsServer) observeCollection(mode string, collect func()) {
+	start := time.Now()
+	cgroupsRead := ss.cgroupsRead
+
+	collect()
+
+	metrics.Instance().MetricStatsCollectionObserve(mode, start, ss.cgroupsRead-cgroupsRead)
+}
+
+// cachedOnDemand returns whether the stats of the sandbox or container with
+// the ID were collected on-demand within the cache duration.
+// Note: caller must hold the lock on the StatsServer.
+func (ss *StatsServer) cachedOnDemand(id string) bool {
+	collectedAt, ok := ss.collectedAt[id]
+
+	return ok && ss.onDemandCacheDuration > 0 && time.Since(collectedAt) < ss.onDemandCacheDuration
 }
 
 // updateUsageNanoCores calculates the usage nano cores by averaging the CPU usage between the timestamps
@@ -174,7 +299,17 @@ func (ss *StatsServer) StatsForSandboxes(sboxes []*sandbox.Sandbox) []*types.Pod
 // that returns (and occasionally gathers) the stats for the given sandbox.
 func (ss *StatsServer) statsForSandbox(sb *sandbox.Sandbox) *types.PodSandboxStats {
 	if ss.collectionPeriod == 0 {
-		return ss.updateSandbox(sb)
+		if sboxStat, ok := ss.sboxStats[sb.ID()]; ok && ss.cachedOnDemand(sb.ID()) {
+			return sboxStat
+		}
+
+		var sboxStat *types.PodSandboxStats
+
+		ss.observeCollection(statsCollectionModeOnDemand, func() {
+			sboxStat = ss.updateSandbox(sb)
+		})
+
+		return sboxStat
 	}
 
 	sboxStat, ok := ss.sboxStats[sb.ID()]
@@ -192,6 +327,7 @@ func (ss *StatsServer) RemoveStatsForSandbox(sb *sandbox.Sandbox) {
 	defer ss.mutex.Unlock()
 
 	delete(ss.sboxStats, sb.ID())
+	delete(ss.collectedAt, sb.ID())
 }
 
 // StatsForContainer returns the stats for the given container.
@@ -229,7 +365,17 @@ func (ss *StatsServer) StatsForContainers(ctrs []*oci.Container) []*types.Contai
 // that returns (and occasionally gathers) the stats for the given container.
 func (ss *StatsServer) statsForContainer(c *oci.Container, sb *sandbox.Sandbox) *types.ContainerStats {
 	if ss.collectionPeriod == 0 {
-		return ss.updateContainerStats(c, sb)
+		if ctrStat, ok := ss.ctrStats[c.ID()]; ok && ss.cachedOnDemand(c.ID()) {
+			return ctrStat
+		}
+
+		var ctrStat *types.ContainerStats
+
+		ss.observeCollection(statsCollectionModeOnDemand, func() {
+			ctrStat = ss.updateContainerStats(c, sb)
+		})
+
+		return ctrStat
 	}
 
 	ctrStat, ok := ss.ctrStats[c.ID()]
@@ -247,6 +393,7 @@ func (ss *StatsServer) RemoveStatsForContainer(c *oci.Container) {
 	defer ss.mutex.Unlock()
 
 	delete(ss.ctrStats, c.ID())
+	delete(ss.collectedAt, c.ID())
 }
 
 // Shutdown tells the updateLoop to stop updating.
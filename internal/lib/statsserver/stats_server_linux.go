@@ -10,6 +10,7 @@ import (
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/opencontainers/cgroups"
 	"github.com/vishvananda/netlink"
+	"google.golang.org/protobuf/proto"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/cri-o/cri-o/internal/config/node"
@@ -20,6 +21,15 @@ import (
 	"github.com/cri-o/cri-o/pkg/config"
 )
 
+const (
+	// defaultInterfaceName is the name of the default interface of pods,
+	// matching the one assumed by the kubelet.
+	defaultInterfaceName = "eth0"
+
+	// loopbackInterfaceName is the name of the loopback interface.
+	loopbackInterfaceName = "lo"
+)
+
 // updateSandbox updates the StatsServer's entry for this sandbox, as well as each child container.
 // It first populates the stats from the CgroupParent, then calculates network usage, updates
 // each of its children container stats by calling into the runtime, and finally calculates the CPUNanoCores.
@@ -45,18 +55,8 @@ func (ss *StatsServer) updateSandbox(sb *sandbox.Sandbox) *types.PodSandboxStats
 		Linux: &types.LinuxPodSandboxStats{},
 	}
 
-	// Network metrics are collected at pod level only.
-	if slices.Contains(ss.Config().EnabledPodMetrics(), config.NetworkMetrics) {
-		podMetrics := ss.GenerateNetworkMetrics(sb)
-		sandboxMetrics.metric.Metrics = podMetrics
-	}
-
-	if cgstats, err := ss.Config().CgroupManager().SandboxCgroupStats(sb.CgroupParent(), sb.ID()); err != nil {
+	if err := ss.populateSandboxCgroupStats(sandboxStats, sb); err != nil {
 		log.Errorf(ss.ctx, "Error getting sandbox stats %s: %v", sb.ID(), err)
-	} else {
-		sandboxStats.Linux.Cpu = criCPUStats(&cgstats.CpuStats, cgstats.SystemNano)
-		sandboxStats.Linux.Memory = criMemStats(&cgstats.MemoryStats, cgstats.SystemNano)
-		sandboxStats.Linux.Process = criProcessStats(&cgstats.PidsStats, cgstats.SystemNano)
 	}
 
 	if err := ss.populateNetworkUsage(sandboxStats, sb); err != nil {
@@ -72,6 +72,8 @@ func (ss *StatsServer) updateSandbox(sb *sandbox.Sandbox) *types.PodSandboxStats
 			continue
 		}
 
+		ss.cgroupsRead++
+
 		ctrStats, err := ss.Runtime().ContainerStats(ss.ctx, c, sb.CgroupParent())
 		if err != nil {
 			log.Errorf(ss.ctx, "Error getting container stats %s: %v", c.ID(), err)
@@ -92,6 +94,8 @@ func (ss *StatsServer) updateSandbox(sb *sandbox.Sandbox) *types.PodSandboxStats
 		}
 
 		containerStats = append(containerStats, cStats)
+		ss.ctrStats[c.ID()] = cStats
+		ss.collectedAt[c.ID()] = time.Now()
 
 		// Convert cgroups stats to CRI metrics.
 		cMetrics := ss.containerMetricsFromContainerStats(sb, c, ctrStats, diskStats)
@@ -105,12 +109,81 @@ func (ss *StatsServer) updateSandbox(sb *sandbox.Sandbox) *types.PodSandboxStats
 		updateUsageNanoCores(old.GetLinux().GetCpu(), sandboxStats.GetLinux().GetCpu())
 	}
 
+	ss.updatePodAccounting(sb, sandboxStats, sandboxMetrics)
+	sandboxMetrics.metric.Metrics = ss.podMetrics(sb, sandboxMetrics)
+
 	ss.sboxStats[sb.ID()] = sandboxStats
+	ss.collectedAt[sb.ID()] = time.Now()
 	ss.sboxMetrics[sb.ID()] = sandboxMetrics
 
 	return sandboxStats
 }
 
+// updateBusySandbox replaces the stats of the sandbox by a copy holding the
+// containerStats refreshed by the adaptive collection, since the previous
+// stats may still be used by the callers they were returned to. The sandbox
+// cgroup stats and the pod accounting get refreshed as well to stay consistent
+// with the container stats, while the network usage and the container metrics
+// are only refreshed by the periodic collection.
+// Note: caller must hold the lock on the StatsServer.
+func (ss *StatsServer) updateBusySandbox(sb *sandbox.Sandbox, old *types.PodSandboxStats, containerStats []*types.ContainerStats) {
+	sandboxStats := &types.PodSandboxStats{
+		Attributes: proto.CloneOf(old.GetAttributes()),
+		Linux: &types.LinuxPodSandboxStats{
+			Cpu:        old.GetLinux().GetCpu(),
+			Memory:     old.GetLinux().GetMemory(),
+			Network:    old.GetLinux().GetNetwork(),
+			Process:    old.GetLinux().GetProcess(),
+			Containers: containerStats,
+			Io:         old.GetLinux().GetIo(),
+		},
+	}
+
+	if err := ss.populateSandboxCgroupStats(sandboxStats, sb); err != nil {
+		log.Errorf(ss.ctx, "Error getting sandbox stats %s: %v", sb.ID(), err)
+	} else {
+		updateUsageNanoCores(old.GetLinux().GetCpu(), sandboxStats.GetLinux().GetCpu())
+	}
+
+	if oldMetrics, ok := ss.sboxMetrics[sb.ID()]; ok {
+		sandboxMetrics := &SandboxMetrics{
+			metric: &types.PodSandboxMetrics{
+				PodSandboxId:     oldMetrics.metric.GetPodSandboxId(),
+				Metrics:          oldMetrics.metric.GetMetrics(),
+				ContainerMetrics: oldMetrics.metric.GetContainerMetrics(),
+			},
+			podAccounting: oldMetrics.podAccounting,
+		}
+
+		ss.updatePodAccounting(sb, sandboxStats, sandboxMetrics)
+
+		if sandboxMetrics.podAccounting != nil {
+			sandboxMetrics.metric.Metrics = ss.podMetrics(sb, sandboxMetrics)
+		}
+
+		ss.sboxMetrics[sb.ID()] = sandboxMetrics
+	}
+
+	ss.sboxStats[sb.ID()] = sandboxStats
+}
+
+// populateSandboxCgroupStats populates the sandbox stats from the pod cgroup.
+func (ss *StatsServer) populateSandboxCgroupStats(sandboxStats *types.PodSandboxStats, sb *sandbox.Sandbox) error {
+	ss.cgroupsRead++
+
+	cgstats, err := ss.Config().CgroupManager().SandboxCgroupStats(sb.CgroupParent(), sb.ID())
+	if err != nil {
+		return err
+	}
+
+	sandboxStats.Linux.Cpu = criCPUStats(&cgstats.CpuStats, cgstats.SystemNano)
+	sandboxStats.Linux.Memory = criMemStats(&cgstats.MemoryStats, cgstats.SystemNano)
+	sandboxStats.Linux.Process = criProcessStats(&cgstats.PidsStats, cgstats.SystemNano)
+	sandboxStats.Linux.Io = criIOStats(&cgstats.BlkioStats, cgstats.SystemNano)
+
+	return nil
+}
+
 // updateContainerStats calls into the runtime handler to update the container stats,
 // as well as populates the writable layer by calling into the container storage.
 // If this container already existed in the stats server, the CPU nano cores are calculated as well.
@@ -123,6 +196,8 @@ func (ss *StatsServer) updateContainerStats(c *oci.Container, sb *sandbox.Sandbo
 		return nil
 	}
 
+	ss.cgroupsRead++
+
 	ctrStats, err := ss.Runtime().ContainerStats(ss.ctx, c, sb.CgroupParent())
 	if err != nil {
 		log.Errorf(ss.ctx, "Error getting container stats %s: %v", c.ID(), err)
@@ -143,6 +218,7 @@ func (ss *StatsServer) updateContainerStats(c *oci.Container, sb *sandbox.Sandbo
 	}
 
 	ss.ctrStats[c.ID()] = cStats
+	ss.collectedAt[c.ID()] = time.Now()
 
 	return cStats
 }
@@ -157,9 +233,7 @@ func (ss *StatsServer) populateNetworkUsage(sbStats *types.PodSandboxStats, sb *
 			return err
 		}
 
-		sbStats.Linux.Network = &types.NetworkUsage{
-			Interfaces: make([]*types.NetworkInterfaceUsage, 0, len(links)-1),
-		}
+		ifaces := make([]*types.NetworkInterfaceUsage, 0, len(links))
 
 		for i := range links {
 			iface, err := linkToInterface(links[i])
@@ -168,18 +242,45 @@ func (ss *StatsServer) populateNetworkUsage(sbStats *types.PodSandboxStats, sb *
 
 				continue
 			}
-			// TODO FIXME or DefaultInterfaceName?
-			if i == 0 {
-				sbStats.Linux.Network.DefaultInterface = iface
-			} else {
-				sbStats.Linux.Network.Interfaces = append(sbStats.Linux.Network.Interfaces, iface)
-			}
+
+			ifaces = append(ifaces, iface)
 		}
 
+		sbStats.Linux.Network = criNetworkUsage(ifaces, time.Now().UnixNano())
+
 		return nil
 	})
 }
 
+// criNetworkUsage returns the network usage of the interfaces, using the
+// default interface name of the kubelet or otherwise the first interface
+// other than the loopback as default interface.
+func criNetworkUsage(ifaces []*types.NetworkInterfaceUsage, timestamp int64) *types.NetworkUsage {
+	usage := &types.NetworkUsage{
+		Timestamp:  timestamp,
+		Interfaces: make([]*types.NetworkInterfaceUsage, 0, len(ifaces)),
+	}
+
+	defaultIndex := slices.IndexFunc(ifaces, func(iface *types.NetworkInterfaceUsage) bool {
+		return iface.GetName() == defaultInterfaceName
+	})
+	if defaultIndex == -1 {
+		defaultIndex = slices.IndexFunc(ifaces, func(iface *types.NetworkInterfaceUsage) bool {
+			return iface.GetName() != loopbackInterfaceName
+		})
+	}
+
+	for i, iface := range ifaces {
+		if i == defaultIndex {
+			usage.DefaultInterface = iface
+		} else {
+			usage.Interfaces = append(usage.Interfaces, iface)
+		}
+	}
+
+	return usage
+}
+
 // metricsForPodSandbox is an internal, non-locking version of MetricsForPodSandbox
 // that returns (and occasionally gathers) the metrics for the given sandbox.
 // Note: caller must hold the lock on the StatsServer.
@@ -203,15 +304,19 @@ func (ss *StatsServer) updatePodSandboxMetrics(sb *sandbox.Sandbox) *SandboxMetr
 		return nil
 	}
 
+	// The pod accounting requires the stats of the pod and its containers,
+	// which only a full update of the sandbox collects.
+	if !hasInfraProcess(sb) && slices.Contains(ss.Config().EnabledPodMetrics(), config.PodAccountingMetrics) {
+		ss.updateSandbox(sb)
+
+		return ss.sboxMetrics[sb.ID()]
+	}
+
 	sm, exists := ss.sboxMetrics[sb.ID()]
 	if !exists {
 		sm = NewSandboxMetrics(sb)
 	}
-	// Network metrics are collected at the pod level.
-	if slices.Contains(ss.Config().EnabledPodMetrics(), config.NetworkMetrics) {
-		podMetrics := ss.GenerateNetworkMetrics(sb)
-		sm.metric.Metrics = podMetrics
-	}
+	sm.metric.Metrics = ss.podMetrics(sb, sm)
 
 	containersList := sb.Containers().List()
 	containerMetrics := make([]*types.ContainerMetrics, 0, len(containersList))
@@ -232,6 +337,24 @@ func (ss *StatsServer) updatePodSandboxMetrics(sb *sandbox.Sandbox) *SandboxMetr
 	return sm
 }
 
+// podMetrics generates the metrics collected at the pod level: the network
+// metrics and the accounting metrics of pods without infra process.
+func (ss *StatsServer) podMetrics(sb *sandbox.Sandbox, sm *SandboxMetrics) []*types.Metric {
+	var metrics []*types.Metric
+
+	enabled := ss.Config().EnabledPodMetrics()
+
+	if slices.Contains(enabled, config.NetworkMetrics) {
+		metrics = append(metrics, ss.GenerateNetworkMetrics(sb)...)
+	}
+
+	if slices.Contains(enabled, config.PodAccountingMetrics) && sm.podAccounting != nil {
+		metrics = append(metrics, generatePodAccountingMetrics(sb, sm.podAccounting)...)
+	}
+
+	return metrics
+}
+
 // GenerateSandboxContainerMetrics generates a list of metrics for the specified sandbox
 // containers by collecting metrics from the cgroup based on the included pod metrics,
 // except for network metrics, which are collected at the pod level.
@@ -305,8 +428,8 @@ func (ss *StatsServer) containerMetricsFromContainerStats(sb *sandbox.Sandbox, c
 
 			oomMetrics := GenerateContainerOOMMetrics(c, oomCount)
 			metrics = append(metrics, oomMetrics...)
-		case config.NetworkMetrics:
-			continue // Network metrics are collected at the pod level only.
+		case config.NetworkMetrics, config.PodAccountingMetrics:
+			continue // Network and pod accounting metrics are collected at the pod level only.
 		case config.ProcessMetrics:
 			if processMetrics := generateContainerProcessMetrics(c, &cgroupStats.PidsStats, &cgroupStats.ProcessStats); processMetrics != nil {
 				metrics = append(metrics, processMetrics...)
@@ -369,6 +492,7 @@ func containerCRIStats(cgstats *stats.CgroupStats, diskStats *stats.DiskStats, c
 	criStats.Cpu = criCPUStats(&cgstats.CpuStats, systemNano)
 	criStats.Memory = criMemStats(&cgstats.MemoryStats, systemNano)
 	criStats.Swap = criSwapStats(&cgstats.MemoryStats, systemNano)
+	criStats.Io = criIOStats(&cgstats.BlkioStats, systemNano)
 
 	// Add filesystem stats if available
 	if diskStats != nil {
@@ -382,6 +506,7 @@ func criCPUStats(cpuStats *cgroups.CpuStats, systemNano int64) *types.CpuUsage {
 	return &types.CpuUsage{
 		Timestamp:            systemNano,
 		UsageCoreNanoSeconds: &types.UInt64Value{Value: cpuStats.CpuUsage.TotalUsage},
+		Psi:                  criPSIStats(cpuStats.PSI),
 	}
 }
 
@@ -396,6 +521,42 @@ func criMemStats(memStats *cgroups.MemoryStats, systemNano int64) *types.MemoryU
 		MajorPageFaults: &types.UInt64Value{Value: majorPageFaults},
 		UsageBytes:      &types.UInt64Value{Value: memStats.Usage.Usage},
 		AvailableBytes:  &types.UInt64Value{Value: availableBytes},
+		Psi:             criPSIStats(memStats.PSI),
+	}
+}
+
+// criIOStats returns the IO usage, which is only available if the IO
+// pressure stall information is supported.
+func criIOStats(blkioStats *cgroups.BlkioStats, systemNano int64) *types.IoUsage {
+	if blkioStats.PSI == nil {
+		return nil
+	}
+
+	return &types.IoUsage{
+		Timestamp: systemNano,
+		Psi:       criPSIStats(blkioStats.PSI),
+	}
+}
+
+// criPSIStats converts the pressure stall information of the cgroup, where
+// the total is in microseconds, to the CRI one in nanoseconds.
+func criPSIStats(psi *cgroups.PSIStats) *types.PsiStats {
+	if psi == nil {
+		return nil
+	}
+
+	return &types.PsiStats{
+		Full: criPSIData(&psi.Full),
+		Some: criPSIData(&psi.Some),
+	}
+}
+
+func criPSIData(data *cgroups.PSIData) *types.PsiData {
+	return &types.PsiData{
+		Total:  data.Total * uint64(time.Microsecond),
+		Avg10:  data.Avg10,
+		Avg60:  data.Avg60,
+		Avg300: data.Avg300,
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/opencontainers/cgroups"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	cstorage "go.podman.io/storage"
 	drivers "go.podman.io/storage/drivers"
@@ -44,13 +45,14 @@ func (f *fakeStore) GraphDriver() (drivers.Driver, error) {
 }
 
 type fakeParentServer struct {
-	runtime *oci.Runtime
-	cfg     *config.Config
+	runtime   *oci.Runtime
+	cfg       *config.Config
+	sandboxes []*sandbox.Sandbox
 }
 
 func (f *fakeParentServer) Runtime() *oci.Runtime              { return f.runtime }
 func (f *fakeParentServer) Store() cstorage.Store              { return &fakeStore{} }
-func (f *fakeParentServer) ListSandboxes() []*sandbox.Sandbox  { return nil }
+func (f *fakeParentServer) ListSandboxes() []*sandbox.Sandbox  { return f.sandboxes }
 func (f *fakeParentServer) GetSandbox(string) *sandbox.Sandbox { return nil }
 func (f *fakeParentServer) Config() *config.Config             { return f.cfg }
 
@@ -120,6 +122,7 @@ func newTestStatsServer(t *testing.T, rt *oci.Runtime, cfg *config.Config) *Stat
 		sboxStats:         make(map[string]*types.PodSandboxStats),
 		ctrStats:          make(map[string]*types.ContainerStats),
 		sboxMetrics:       make(map[string]*SandboxMetrics),
+		collectedAt:       make(map[string]time.Time),
 		ctx:               context.Background(),
 	}
 }
@@ -192,3 +195,264 @@ func TestUpdateSandboxDiskStatsError(t *testing.T) {
 		t.Error("expected memory stats to be present")
 	}
 }
+
+func TestContainerCRIStatsPSI(t *testing.T) {
+	t.Parallel()
+
+	ctr := newRunningContainer(t, "ctr-1", "test-container")
+	psi := &cgroups.PSIStats{
+		Some: cgroups.PSIData{Avg10: 1.5, Avg60: 1, Avg300: 0.5, Total: 100},
+		Full: cgroups.PSIData{Avg10: 0.5, Total: 50},
+	}
+
+	cgstats := &stats.CgroupStats{SystemNano: time.Now().UnixNano()}
+	cgstats.CpuStats.PSI = psi
+	cgstats.MemoryStats.PSI = psi
+	cgstats.BlkioStats.PSI = psi
+
+	result := containerCRIStats(cgstats, nil, ctr, cgstats.SystemNano)
+
+	for name, got := range map[string]*types.PsiStats{
+		"cpu":    result.GetCpu().GetPsi(),
+		"memory": result.GetMemory().GetPsi(),
+		"io":     result.GetIo().GetPsi(),
+	} {
+		if got.GetSome().GetAvg10() != 1.5 || got.GetSome().GetTotal() != 100000 {
+			t.Errorf("unexpected %s some PSI: %v", name, got.GetSome())
+		}
+
+		if got.GetFull().GetAvg10() != 0.5 || got.GetFull().GetTotal() != 50000 {
+			t.Errorf("unexpected %s full PSI: %v", name, got.GetFull())
+		}
+	}
+}
+
+func TestContainerCRIStatsWithoutPSI(t *testing.T) {
+	t.Parallel()
+
+	ctr := newRunningContainer(t, "ctr-1", "test-container")
+	cgstats := &stats.CgroupStats{SystemNano: time.Now().UnixNano()}
+
+	result := containerCRIStats(cgstats, nil, ctr, cgstats.SystemNano)
+
+	if result.GetCpu().GetPsi() != nil || result.GetMemory().GetPsi() != nil {
+		t.Error("expected no PSI stats")
+	}
+
+	if result.GetIo() != nil {
+		t.Error("expected no IO stats")
+	}
+}
+
+func TestCRINetworkUsage(t *testing.T) {
+	t.Parallel()
+
+	lo := &types.NetworkInterfaceUsage{Name: "lo"}
+	eth0 := &types.NetworkInterfaceUsage{Name: "eth0"}
+	net1 := &types.NetworkInterfaceUsage{Name: "net1"}
+
+	for _, tc := range []struct {
+		name             string
+		ifaces           []*types.NetworkInterfaceUsage
+		expectedDefault  *types.NetworkInterfaceUsage
+		expectedOtherLen int
+	}{
+		{
+			name:             "default interface name",
+			ifaces:           []*types.NetworkInterfaceUsage{lo, net1, eth0},
+			expectedDefault:  eth0,
+			expectedOtherLen: 2,
+		},
+		{
+			name:             "first interface other than loopback",
+			ifaces:           []*types.NetworkInterfaceUsage{lo, net1},
+			expectedDefault:  net1,
+			expectedOtherLen: 1,
+		},
+		{
+			name:             "only loopback",
+			ifaces:           []*types.NetworkInterfaceUsage{lo},
+			expectedDefault:  nil,
+			expectedOtherLen: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			usage := criNetworkUsage(tc.ifaces, 1)
+
+			if usage.GetDefaultInterface() != tc.expectedDefault {
+				t.Errorf("expected default interface %v, got %v", tc.expectedDefault, usage.GetDefaultInterface())
+			}
+
+			if len(usage.GetInterfaces()) != tc.expectedOtherLen {
+				t.Errorf("expected %d other interfaces, got %d", tc.expectedOtherLen, len(usage.GetInterfaces()))
+			}
+
+			if usage.GetTimestamp() != 1 {
+				t.Errorf("expected timestamp 1, got %d", usage.GetTimestamp())
+			}
+		})
+	}
+}
+
+func TestStatsForContainerOnDemandCache(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name                string
+		cacheDuration       time.Duration
+		expectedCgroupsRead int
+	}{
+		{
+			name:                "without cache",
+			expectedCgroupsRead: 2,
+		},
+		{
+			name:                "with cache",
+			cacheDuration:       time.Minute,
+			expectedCgroupsRead: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := config.DefaultConfig()
+			if err != nil {
+				t.Fatalf("DefaultConfig: %v", err)
+			}
+
+			rt := oci.NewTestRuntime()
+			ctr := newRunningContainer(t, "ctr-1", "test-container")
+
+			rt.SetRuntimeImpl("ctr-1", &fakeRuntimeImpl{
+				cgroupStats: &stats.CgroupStats{SystemNano: time.Now().UnixNano()},
+			})
+
+			sb := newTestSandbox(t, "sb-1")
+			sb.AddContainer(context.Background(), ctr)
+
+			ss := newTestStatsServer(t, rt, cfg)
+			ss.onDemandCacheDuration = tc.cacheDuration
+
+			first := ss.statsForContainer(ctr, sb)
+			second := ss.statsForContainer(ctr, sb)
+
+			if first == nil || second == nil {
+				t.Fatal("expected container stats")
+			}
+
+			if ss.cgroupsRead != tc.expectedCgroupsRead {
+				t.Errorf("expected %d cgroups read, got %d", tc.expectedCgroupsRead, ss.cgroupsRead)
+			}
+		})
+	}
+}
+
+func TestIsBusy(t *testing.T) {
+	t.Parallel()
+
+	ss := &StatsServer{adaptiveCPUThreshold: 100 * nanoCoresPerMilliCore}
+
+	for _, tc := range []struct {
+		name     string
+		stats    *types.ContainerStats
+		expected bool
+	}{
+		{
+			name:     "without CPU usage",
+			stats:    &types.ContainerStats{},
+			expected: false,
+		},
+		{
+			name: "below the threshold",
+			stats: &types.ContainerStats{Cpu: &types.CpuUsage{
+				UsageNanoCores: &types.UInt64Value{Value: 50 * nanoCoresPerMilliCore},
+			}},
+			expected: false,
+		},
+		{
+			name: "above the threshold",
+			stats: &types.ContainerStats{Cpu: &types.CpuUsage{
+				UsageNanoCores: &types.UInt64Value{Value: 150 * nanoCoresPerMilliCore},
+			}},
+			expected: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if busy := ss.isBusy(tc.stats); busy != tc.expected {
+				t.Errorf("expected busy %v, got %v", tc.expected, busy)
+			}
+		})
+	}
+}
+
+func TestUpdateBusyContainersCopiesSandboxStats(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		t.Fatalf("DefaultConfig: %v", err)
+	}
+
+	rt := oci.NewTestRuntime()
+	busy := newRunningContainer(t, "ctr-busy", "busy-container")
+	idle := newRunningContainer(t, "ctr-idle", "idle-container")
+
+	rt.SetRuntimeImpl("ctr-busy", &fakeRuntimeImpl{
+		cgroupStats: &stats.CgroupStats{SystemNano: time.Now().UnixNano()},
+	})
+
+	sb := newTestSandbox(t, "sb-1")
+	sb.AddContainer(context.Background(), busy)
+	sb.AddContainer(context.Background(), idle)
+
+	ss := newTestStatsServer(t, rt, cfg)
+	ss.parentServerIface = &fakeParentServer{runtime: rt, cfg: cfg, sandboxes: []*sandbox.Sandbox{sb}}
+	ss.adaptiveCPUThreshold = 100 * nanoCoresPerMilliCore
+
+	busyStats := &types.ContainerStats{
+		Attributes: &types.ContainerAttributes{Id: busy.ID()},
+		Cpu: &types.CpuUsage{
+			UsageNanoCores: &types.UInt64Value{Value: 150 * nanoCoresPerMilliCore},
+		},
+	}
+	idleStats := &types.ContainerStats{
+		Attributes: &types.ContainerAttributes{Id: idle.ID()},
+		Cpu: &types.CpuUsage{
+			UsageNanoCores: &types.UInt64Value{Value: 50 * nanoCoresPerMilliCore},
+		},
+	}
+	previous := &types.PodSandboxStats{
+		Attributes: &types.PodSandboxAttributes{Id: sb.ID()},
+		Linux: &types.LinuxPodSandboxStats{
+			Containers: []*types.ContainerStats{busyStats, idleStats},
+		},
+	}
+	ss.sboxStats[sb.ID()] = previous
+
+	ss.updateBusyContainers()
+
+	if previous.GetLinux().GetContainers()[0] != busyStats {
+		t.Error("expected the previous sandbox stats to be left unchanged")
+	}
+
+	current := ss.sboxStats[sb.ID()]
+	if current == previous {
+		t.Fatal("expected the sandbox stats to be replaced")
+	}
+
+	if len(current.GetLinux().GetContainers()) != 2 {
+		t.Fatalf("expected 2 container stats, got %d", len(current.GetLinux().GetContainers()))
+	}
+
+	if current.GetLinux().GetContainers()[0] != ss.ctrStats[busy.ID()] {
+		t.Error("expected the busy container stats to be updated")
+	}
+
+	if current.GetLinux().GetContainers()[1] != idleStats {
+		t.Error("expected the idle container stats to be kept")
+	}
+}
@@ -22,6 +22,11 @@ func (ss *StatsServer) updateContainerStats(c *oci.Container, sb *sandbox.Sandbo
 	return &types.ContainerStats{}
 }
 
+// updateBusySandbox replaces the stats of the sandbox by a copy holding the
+// containerStats refreshed by the adaptive collection.
+func (ss *StatsServer) updateBusySandbox(sb *sandbox.Sandbox, old *types.PodSandboxStats, containerStats []*types.ContainerStats) {
+}
+
 // metricsForPodSandbox is an internal, non-locking version of MetricsForPodSandbox
 // that returns (and occasionally gathers) the metrics for the given sandbox.
 // Note: the caller must hold the lock on the StatsServer
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -246,6 +247,21 @@ func mockRuntimeInLibConfig() {
 	}
 }
 
+// mockRuntimeRecordingArgsInLibConfig configures a runtime which succeeds
+// and records the arguments of every call as a line in the returned file.
+func mockRuntimeRecordingArgsInLibConfig() string {
+	dir := t.MustTempDir("runtime")
+	argsFile := filepath.Join(dir, "args")
+	runtimePath := filepath.Join(dir, "runtime")
+	Expect(os.WriteFile(runtimePath, []byte("#!/bin/sh\necho \"$*\" >> "+argsFile+"\n"), 0o755)).To(Succeed())
+
+	config.Runtimes[config.DefaultRuntime] = &libconfig.RuntimeHandler{
+		RuntimePath: runtimePath,
+	}
+
+	return argsFile
+}
+
 func mockRuntimeInLibConfigCheckpoint() {
 	trueCMD, err := exec.LookPath("true")
 	Expect(err).NotTo(HaveOccurred())
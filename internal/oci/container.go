@@ -860,6 +860,12 @@ func (c *Container) SetRuntimePathForPlatform(runtimePath string) {
 	c.runtimePath = runtimePath
 }
 
+// RuntimePath returns the runtime path set for the container, or an empty
+// string if it uses the one of its runtime handler.
+func (c *Container) RuntimePath() string {
+	return c.runtimePath
+}
+
 // RuntimePathForPlatform returns the runtime path for a given platform.
 func (c *Container) RuntimePathForPlatform(r *runtimeOCI) string {
 	if c.runtimePath == "" {
@@ -0,0 +1,69 @@
+package oci
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	cio "github.com/containerd/containerd/pkg/cri/io"
+
+	"github.com/cri-o/cri-o/internal/log"
+)
+
+// jsonLoggerBufSize is the maximum size of a log entry. Longer lines are split
+// into partial entries, like done by the CRI logger.
+const jsonLoggerBufSize = 16 * 1024
+
+// jsonLogEntry is a line of the container output in the JSON log format.
+type jsonLogEntry struct {
+	Log    string    `json:"log"`
+	Stream string    `json:"stream"`
+	Time   time.Time `json:"time"`
+}
+
+// newJSONLogger returns a write closer which writes the container output
+// line by line as JSON objects into the log file. It also returns a channel
+// which gets closed once the logger is stopped. Partial lines, which are
+// either unterminated or exceed the buffer size, are written without a
+// trailing newline in the log.
+func newJSONLogger(ctx context.Context, path string, w io.Writer, stream cio.StreamType) (io.WriteCloser, <-chan struct{}) {
+	log.Debugf(ctx, "Start writing stream %q to JSON log file %q", stream, path)
+
+	pr, pw := io.Pipe()
+	stop := make(chan struct{})
+
+	go func() {
+		defer close(stop)
+		defer pr.Close()
+
+		encoder := json.NewEncoder(w)
+		reader := bufio.NewReaderSize(pr, jsonLoggerBufSize)
+
+		for {
+			line, err := reader.ReadSlice('\n')
+			if errors.Is(err, bufio.ErrBufferFull) {
+				err = nil
+			}
+
+			if len(line) > 0 {
+				entry := jsonLogEntry{Log: string(line), Stream: string(stream), Time: time.Now()}
+				if encodeErr := encoder.Encode(&entry); encodeErr != nil {
+					log.Errorf(ctx, "Failed to write %q log to log file %q: %v", stream, path, encodeErr)
+				}
+			}
+
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					log.Errorf(ctx, "Failed to redirect stream %q to log file %q: %v", stream, path, err)
+				}
+
+				return
+			}
+		}
+	}()
+
+	return pw, stop
+}
@@ -0,0 +1,78 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	cio "github.com/containerd/containerd/pkg/cri/io"
+)
+
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger, stop := newJSONLogger(context.Background(), "test.log", &buf, cio.Stdout)
+
+	if _, err := logger.Write([]byte("first line\nsecond ")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := logger.Write([]byte("line\npartial")); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Close()
+	<-stop
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	expected := []string{"first line\n", "second line\n", "partial"}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d log entries, got %d: %q", len(expected), len(lines), buf.String())
+	}
+
+	for i, line := range lines {
+		var entry jsonLogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+
+		if entry.Log != expected[i] || entry.Stream != string(cio.Stdout) || entry.Time.IsZero() {
+			t.Fatalf("Unexpected log entry %d: %+v", i, entry)
+		}
+	}
+}
+
+func TestJSONLoggerLongLine(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger, stop := newJSONLogger(context.Background(), "test.log", &buf, cio.Stdout)
+
+	line := strings.Repeat("a", jsonLoggerBufSize+10) + "\n"
+	if _, err := logger.Write([]byte(line)); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Close()
+	<-stop
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	expected := []string{strings.Repeat("a", jsonLoggerBufSize), strings.Repeat("a", 10) + "\n"}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d log entries, got %d", len(expected), len(lines))
+	}
+
+	for i, line := range lines {
+		var entry jsonLogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+
+		if entry.Log != expected[i] {
+			t.Fatalf("Unexpected log entry %d of length %d", i, len(entry.Log))
+		}
+	}
+}
@@ -48,6 +48,14 @@ type Runtime struct {
 	config              *config.Config
 	runtimeImplMap      map[string]RuntimeImpl
 	runtimeImplMapMutex sync.RWMutex
+	streamServer        StreamServer
+}
+
+// StreamServer provides the exec and attach URLs of the CRI-O streaming
+// server.
+type StreamServer interface {
+	GetExec(*types.ExecRequest) (*types.ExecResponse, error)
+	GetAttach(*types.AttachRequest) (*types.AttachResponse, error)
 }
 
 // RuntimeImpl is an interface used by the caller to interact with the
@@ -76,7 +84,8 @@ type RuntimeImpl interface {
 	PortForwardContainer(context.Context, *Container, string,
 		int32, io.ReadWriteCloser) error
 	ReopenContainerLog(context.Context, *Container) error
-	CheckpointContainer(context.Context, *Container, *rspec.Spec, bool) error
+	CheckpointContainer(context.Context, *Container, *rspec.Spec, bool, string) error
+	PreDumpContainer(context.Context, *Container, *rspec.Spec, string, string) error
 	RestoreContainer(context.Context, *Container, string, string) error
 	IsContainerAlive(*Container) bool
 	// ProbeMonitor is used to check the liveness of the container monitor process.
@@ -98,6 +107,13 @@ func New(c *config.Config) (*Runtime, error) {
 	}, nil
 }
 
+// SetStreamServer sets the streaming server used by runtimes which do not
+// serve exec and attach themselves. It has to be called before serving any
+// requests.
+func (r *Runtime) SetStreamServer(server StreamServer) {
+	r.streamServer = server
+}
+
 // Runtimes returns the map of OCI runtimes.
 func (r *Runtime) Runtimes() config.Runtimes {
 	return r.config.Runtimes
@@ -168,6 +184,46 @@ func (r *Runtime) PlatformRuntimePath(handler, platform string) (string, error)
 	return "", nil
 }
 
+// WasmRuntimeHandler returns the name of the wasm runtime handler running
+// WebAssembly containers in pod sandboxes of the runtimeHandler. It returns an
+// empty string if the runtimeHandler runs them itself, if it is not an OCI
+// runtime handler or if no wasm runtime handler is configured.
+func (r *Runtime) WasmRuntimeHandler(handler string) (string, error) {
+	rh, err := r.getRuntimeHandler(handler)
+	if err != nil {
+		return "", err
+	}
+
+	if rh.RuntimeType != config.DefaultRuntimeType && rh.RuntimeType != "" {
+		return "", nil
+	}
+
+	return r.config.WasmRuntimeHandler(), nil
+}
+
+// RuntimeCanaryPath returns the canary runtime path of the runtimeHandler if
+// it got selected for a new pod sandbox, and an empty string otherwise.
+func (r *Runtime) RuntimeCanaryPath(handler string) (string, error) {
+	rh, err := r.getRuntimeHandler(handler)
+	if err != nil {
+		return "", err
+	}
+
+	return rh.RuntimeCanaryPath(), nil
+}
+
+// RuntimeFallbackHandler returns the name of the runtime handler used to retry
+// creating a pod sandbox after the runtimeErr of the runtimeHandler, or an
+// empty string if there is none.
+func (r *Runtime) RuntimeFallbackHandler(handler string, runtimeErr error) string {
+	rh, err := r.getRuntimeHandler(handler)
+	if err != nil {
+		return ""
+	}
+
+	return rh.RuntimeFallbackHandler(runtimeErr)
+}
+
 // AllowedAnnotations returns the allowed annotations for this runtime.
 func (r *Runtime) AllowedAnnotations(handler string) ([]string, error) {
 	rh, err := r.getRuntimeHandler(handler)
@@ -246,6 +302,23 @@ func (r *Runtime) RuntimeSupportsRROMounts(runtimeHandler string) bool {
 	return rh.RuntimeSupportsRROMounts()
 }
 
+// ValidateRuntimeFeatures validates the spec against the features advertised
+// by the runtime at runtimePath of the runtimeHandler running the container,
+// such as its canary runtime path. An empty runtimePath refers to the runtime
+// path of the runtimeHandler.
+func (r *Runtime) ValidateRuntimeFeatures(runtimeHandler, runtimePath string, spec *rspec.Spec) error {
+	rh, err := r.getRuntimeHandler(runtimeHandler)
+	if err != nil {
+		return err
+	}
+
+	if err := rh.ValidateRuntimePathFeatures(runtimePath, spec); err != nil {
+		return fmt.Errorf("runtime handler %q: %w", runtimeHandler, err)
+	}
+
+	return nil
+}
+
 // RuntimeDefaultAnnotations returns the default annotations for this runtime handler.
 func (r *Runtime) RuntimeDefaultAnnotations(runtimeHandler string) (map[string]string, error) {
 	rh, err := r.getRuntimeHandler(runtimeHandler)
@@ -272,15 +345,15 @@ func (r *Runtime) newRuntimeImpl(c *Container) (RuntimeImpl, error) {
 		return nil, err
 	}
 
-	if rh.RuntimeType == config.RuntimeTypeVM {
-		return newRuntimeVM(rh, r.config.ContainerExitsDir), nil
+	if rh.RuntimeType == config.RuntimeTypeVM || rh.RuntimeType == config.RuntimeTypeShimV2 {
+		return newRuntimeVM(r, rh), nil
 	}
 
 	if rh.RuntimeType == config.RuntimeTypePod {
 		return newRuntimePod(r, rh, c)
 	}
 
-	// If the runtime type is different from "vm", then let's fallback
+	// If the runtime type is different from "vm" and "shimv2", then let's fallback
 	// onto the OCI implementation by default.
 	return newRuntimeOCI(r, rh), nil
 }
@@ -542,14 +615,28 @@ func (e *ExecSyncError) Error() string {
 	return fmt.Sprintf("command error: %+v, stdout: %s, stderr: %s, exit code %d", e.Err, e.Stdout.Bytes(), e.Stderr.Bytes(), e.ExitCode)
 }
 
-// CheckpointContainer checkpoints a container.
-func (r *Runtime) CheckpointContainer(ctx context.Context, c *Container, specgen *rspec.Spec, leaveRunning bool) error {
+// CheckpointContainer checkpoints a container. A non empty parentPath refers
+// to the directory of the last pre-dump relative to the checkpoint directory.
+func (r *Runtime) CheckpointContainer(ctx context.Context, c *Container, specgen *rspec.Spec, leaveRunning bool, parentPath string) error {
+	impl, err := r.RuntimeImpl(c)
+	if err != nil {
+		return err
+	}
+
+	return impl.CheckpointContainer(ctx, c, specgen, leaveRunning, parentPath)
+}
+
+// PreDumpContainer dumps the memory of a running container into imagePath
+// without stopping it, so that a following checkpoint only has to dump the
+// memory pages changed since then. A non empty parentPath refers to the
+// directory of the previous pre-dump relative to imagePath.
+func (r *Runtime) PreDumpContainer(ctx context.Context, c *Container, specgen *rspec.Spec, imagePath, parentPath string) error {
 	impl, err := r.RuntimeImpl(c)
 	if err != nil {
 		return err
 	}
 
-	return impl.CheckpointContainer(ctx, c, specgen, leaveRunning)
+	return impl.PreDumpContainer(ctx, c, specgen, imagePath, parentPath)
 }
 
 // RestoreContainer restores a container.
@@ -2,18 +2,52 @@ package oci_test
 
 import (
 	"context"
+	"io"
 	"os"
+	"time"
 
 	criu "github.com/checkpoint-restore/go-criu/v8/utils"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/cri-o/cri-o/internal/oci"
 	v2 "github.com/cri-o/cri-o/pkg/annotations/v2"
 	libconfig "github.com/cri-o/cri-o/pkg/config"
 )
 
+// fakeStreamServer records the requests and returns fixed URLs.
+type fakeStreamServer struct {
+	execRequest   *types.ExecRequest
+	attachRequest *types.AttachRequest
+}
+
+func (f *fakeStreamServer) GetExec(req *types.ExecRequest) (*types.ExecResponse, error) {
+	f.execRequest = req
+
+	return &types.ExecResponse{Url: "http://127.0.0.1:10010/exec/token"}, nil
+}
+
+func (f *fakeStreamServer) GetAttach(req *types.AttachRequest) (*types.AttachResponse, error) {
+	f.attachRequest = req
+
+	return &types.AttachResponse{Url: "http://127.0.0.1:10010/attach/token"}, nil
+}
+
+// fakeStream is a port forward stream recording whether it got closed.
+type fakeStream struct {
+	io.ReadWriter
+
+	closed bool
+}
+
+func (f *fakeStream) Close() error {
+	f.closed = true
+
+	return nil
+}
+
 // The actual test suite.
 var _ = t.Describe("Oci", func() {
 	t.Describe("New", func() {
@@ -44,8 +78,20 @@ var _ = t.Describe("Oci", func() {
 			usernsRuntime      = "userns"
 			performanceRuntime = "high-performance"
 			vmRuntime          = "kata"
+			shimV2Runtime      = "shimv2"
 		)
 
+		newContainer := func(id, runtimeHandler string) *oci.Container {
+			c, err := oci.NewContainer(id, "", "", "",
+				make(map[string]string), make(map[string]string),
+				make(map[string]string), "", nil, nil, "",
+				&types.ContainerMetadata{}, sandboxID, true,
+				false, false, runtimeHandler, "", time.Now(), "")
+			Expect(err).ToNot(HaveOccurred())
+
+			return c
+		}
+
 		runtimes := libconfig.Runtimes{
 			defaultRuntime: &libconfig.RuntimeHandler{
 				RuntimePath: "/bin/sh",
@@ -77,6 +123,12 @@ var _ = t.Describe("Oci", func() {
 				PrivilegedWithoutHostDevices: true,
 				RuntimeConfigPath:            "/opt/kata-containers/config.toml",
 			},
+			shimV2Runtime: &libconfig.RuntimeHandler{
+				RuntimePath:      "/usr/bin/containerd-shim-runc-v2",
+				RuntimeType:      "shimv2",
+				RuntimeRoot:      "/run/shimv2",
+				StreamWebsockets: true,
+			},
 		}
 
 		BeforeEach(func() {
@@ -131,6 +183,77 @@ var _ = t.Describe("Oci", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(runtimeType).To(Equal(libconfig.RuntimeTypeVM))
 		})
+		It("ServeExecContainer should return the URL of the streaming server for shimv2 runtimes", func() {
+			// Given
+			streamServer := &fakeStreamServer{}
+			sut.SetStreamServer(streamServer)
+			c := newContainer("shimv2-exec", shimV2Runtime)
+
+			// When
+			url, err := sut.ServeExecContainer(context.Background(), c, []string{"ls"}, true, false, true, false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(url).To(Equal("http://127.0.0.1:10010/exec/token"))
+			Expect(streamServer.execRequest.GetContainerId()).To(Equal("shimv2-exec"))
+			Expect(streamServer.execRequest.GetCmd()).To(Equal([]string{"ls"}))
+			Expect(streamServer.execRequest.GetTty()).To(BeTrue())
+			Expect(streamServer.execRequest.GetStdin()).To(BeFalse())
+			Expect(streamServer.execRequest.GetStdout()).To(BeTrue())
+		})
+		It("ServeExecContainer should fail for shimv2 runtimes without streaming server", func() {
+			// Given
+			c := newContainer("shimv2-exec-fail", shimV2Runtime)
+
+			// When
+			url, err := sut.ServeExecContainer(context.Background(), c, []string{"ls"}, false, false, true, true)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(url).To(BeEmpty())
+		})
+		It("ServeAttachContainer should return the URL of the streaming server for shimv2 runtimes", func() {
+			// Given
+			streamServer := &fakeStreamServer{}
+			sut.SetStreamServer(streamServer)
+			c := newContainer("shimv2-attach", shimV2Runtime)
+
+			// When
+			url, err := sut.ServeAttachContainer(context.Background(), c, true, true, false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(url).To(Equal("http://127.0.0.1:10010/attach/token"))
+			Expect(streamServer.attachRequest.GetContainerId()).To(Equal("shimv2-attach"))
+			Expect(streamServer.attachRequest.GetTty()).To(BeTrue())
+			Expect(streamServer.attachRequest.GetStdin()).To(BeTrue())
+			Expect(streamServer.attachRequest.GetStderr()).To(BeFalse())
+		})
+		It("PortForwardContainer should be a no-op for VM runtimes", func() {
+			// Given
+			stream := &fakeStream{}
+			c := newContainer("vm-port-forward", vmRuntime)
+
+			// When
+			err := sut.PortForwardContainer(context.Background(), c, "/proc/self/ns/net", 8080, stream)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stream.closed).To(BeFalse())
+		})
+		It("PortForwardContainer should forward in the network namespace for shimv2 runtimes", func() {
+			// Given
+			stream := &fakeStream{}
+			c := newContainer("shimv2-port-forward", shimV2Runtime)
+
+			// When
+			err := sut.PortForwardContainer(context.Background(), c, "/proc/self/ns/net", 1, stream)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to connect to localhost:1"))
+			Expect(stream.closed).To(BeTrue())
+		})
 		It("Seccomp should return the runtime seccomp config", func() {
 			// Given
 			// When
@@ -220,7 +343,7 @@ var _ = t.Describe("Oci", func() {
 				},
 			}
 			// When
-			err := sut.CheckpointContainer(context.Background(), myContainer, specgen, false)
+			err := sut.CheckpointContainer(context.Background(), myContainer, specgen, false, "")
 
 			// Then
 			Expect(err).ToNot(HaveOccurred())
@@ -248,7 +371,7 @@ var _ = t.Describe("Oci", func() {
 				},
 			}
 			// When
-			err := sut.CheckpointContainer(context.Background(), myContainer, specgen, true)
+			err := sut.CheckpointContainer(context.Background(), myContainer, specgen, true, "")
 
 			// Then
 			Expect(err).To(HaveOccurred())
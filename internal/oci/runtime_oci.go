@@ -1674,7 +1674,7 @@ func (r *runtimeOCI) defaultRuntimeArgs() []string {
 }
 
 // CheckpointContainer checkpoints a container.
-func (r *runtimeOCI) CheckpointContainer(ctx context.Context, c *Container, specgen *rspec.Spec, leaveRunning bool) error {
+func (r *runtimeOCI) CheckpointContainer(ctx context.Context, c *Container, specgen *rspec.Spec, leaveRunning bool, parentPath string) error {
 	c.opLock.Lock()
 	defer c.opLock.Unlock()
 
@@ -1718,6 +1718,10 @@ func (r *runtimeOCI) CheckpointContainer(ctx context.Context, c *Container, spec
 		args = append(args, "--leave-running")
 	}
 
+	if parentPath != "" {
+		args = append(args, "--parent-path", parentPath)
+	}
+
 	args = append(args, c.ID())
 
 	_, err := r.runtimeCmd(args...)
@@ -1736,6 +1740,49 @@ func (r *runtimeOCI) CheckpointContainer(ctx context.Context, c *Container, spec
 	return nil
 }
 
+// PreDumpContainer dumps the memory of a running container without stopping
+// it, which requires memory tracking support of the kernel.
+func (r *runtimeOCI) PreDumpContainer(ctx context.Context, c *Container, specgen *rspec.Spec, imagePath, parentPath string) error {
+	c.opLock.Lock()
+	defer c.opLock.Unlock()
+
+	runtimePath := c.RuntimePathForPlatform(r)
+	if err := r.checkpointRestoreSupported(runtimePath); err != nil {
+		return err
+	}
+
+	if err := crutils.CRCreateFileWithLabel(
+		c.Dir(),
+		metadata.DumpLogFile,
+		specgen.Linux.MountLabel,
+	); err != nil {
+		return err
+	}
+
+	log.Debugf(ctx, "Writing pre-dump to %s", imagePath)
+
+	args := []string{
+		"checkpoint",
+		"--pre-dump",
+		"--image-path",
+		imagePath,
+		"--work-path",
+		c.Dir(),
+	}
+
+	if parentPath != "" {
+		args = append(args, "--parent-path", parentPath)
+	}
+
+	args = append(args, c.ID())
+
+	if _, err := r.runtimeCmd(args...); err != nil {
+		return fmt.Errorf("running %q %q failed: %w", runtimePath, args, err)
+	}
+
+	return nil
+}
+
 // RestoreContainer restores a container.
 func (r *runtimeOCI) RestoreContainer(ctx context.Context, c *Container, cgroupParent, mountLabel string) error {
 	if err := r.checkpointRestoreSupported(c.RuntimePathForPlatform(r)); err != nil {
@@ -18,6 +18,12 @@ import (
 
 // PortForwardContainer forwards the specified port into the provided container.
 func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
+	return portForwardContainer(ctx, c, netNsPath, port, stream)
+}
+
+// portForwardContainer forwards the specified port by connecting to it from
+// within the network namespace of the container.
+func portForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 
@@ -155,6 +155,11 @@ func (r *runtimePod) CreateContainer(ctx context.Context, c *Container, cgroupPa
 		maxSize = uint64(r.oci.config.LogSizeMax)
 	}
 
+	logDriverType := conmonClient.LogDriverTypeContainerRuntimeInterface
+	if r.oci.handler.LogFormat == config.LogFormatJSON {
+		logDriverType = conmonClient.LogDriverTypeJSONLogger
+	}
+
 	createConfig := &conmonClient.CreateContainerConfig{
 		ID:           c.ID(),
 		BundlePath:   c.bundlePath,
@@ -164,7 +169,7 @@ func (r *runtimePod) CreateContainer(ctx context.Context, c *Container, cgroupPa
 		OOMExitPaths: []string{filepath.Join(c.bundlePath, "oom")}, // Keep in sync with location in oci.UpdateContainerStatus()
 		LogDrivers: []conmonClient.ContainerLogDriver{
 			{
-				Type:    conmonClient.LogDriverTypeContainerRuntimeInterface,
+				Type:    logDriverType,
 				Path:    c.logPath,
 				MaxSize: maxSize,
 			},
@@ -213,8 +218,18 @@ func (r *runtimePod) CheckpointContainer(
 	c *Container,
 	specgen *rspec.Spec,
 	leaveRunning bool,
+	parentPath string,
+) error {
+	return r.oci.CheckpointContainer(ctx, c, specgen, leaveRunning, parentPath)
+}
+
+func (r *runtimePod) PreDumpContainer(
+	ctx context.Context,
+	c *Container,
+	specgen *rspec.Spec,
+	imagePath, parentPath string,
 ) error {
-	return r.oci.CheckpointContainer(ctx, c, specgen, leaveRunning)
+	return r.oci.PreDumpContainer(ctx, c, specgen, imagePath, parentPath)
 }
 
 func (r *runtimePod) RestoreContainer(
@@ -52,6 +52,7 @@ import (
 type runtimeVM struct {
 	sync.Mutex
 
+	runtime    *Runtime
 	path       string
 	fifoDir    string
 	configPath string
@@ -78,8 +79,15 @@ const (
 	execTimeout = -2
 )
 
+// ShimOOMFilePath returns the path of the file which "crio publish" creates
+// in the exitsDir once the task of the container of a shimv2 runtime got OOM
+// killed.
+func ShimOOMFilePath(exitsDir, containerID string) string {
+	return filepath.Join(exitsDir, containerID+".oom")
+}
+
 // newRuntimeVM creates a new runtimeVM instance.
-func newRuntimeVM(handler *config.RuntimeHandler, exitsPath string) RuntimeImpl {
+func newRuntimeVM(r *Runtime, handler *config.RuntimeHandler) RuntimeImpl {
 	logrus.Debug("oci.newRuntimeVM() start")
 	defer logrus.Debug("oci.newRuntimeVM() end")
 
@@ -96,9 +104,10 @@ func newRuntimeVM(handler *config.RuntimeHandler, exitsPath string) RuntimeImpl
 	typeurl.Register(&rspec.WindowsResources{}, prefix, "opencontainers/runtime-spec", major, "WindowsResources")
 
 	return &runtimeVM{
+		runtime:    r,
 		path:       handler.RuntimePath,
 		configPath: handler.RuntimeConfigPath,
-		exitsPath:  exitsPath,
+		exitsPath:  r.config.ContainerExitsDir,
 		pullImage:  handler.RuntimePullImage,
 		fifoDir:    filepath.Join(handler.RuntimeRoot, "crio", "fifo"),
 		ctx:        context.Background(),
@@ -213,6 +222,12 @@ func (r *runtimeVM) CreateContainer(ctx context.Context, c *Container, cgroupPar
 		Options:  opts,
 	}
 
+	if restore {
+		// The shim restores the task from the checkpoint images instead of
+		// creating a new init process.
+		request.Checkpoint = c.CheckpointPath()
+	}
+
 	if r.handler.RuntimePullImage {
 		err := addVolumeMountsToCreateRequest(ctx, request, c)
 		if err != nil {
@@ -292,11 +307,20 @@ func (r *runtimeVM) startRuntimeDaemon(ctx context.Context, c *Container) error
 
 	r.ctx = namespaces.WithNamespace(r.ctx, namespaces.Default)
 
+	// Shims publishing their events through the publish binary, which is
+	// "crio publish", write the exit and OOM files of their tasks into the
+	// address. This notifies about exits also after a restart of CRI-O.
+	var publishAddress string
+	if r.handler.RuntimeType == config.RuntimeTypeShimV2 {
+		publishAddress = r.exitsPath
+	}
+
 	// Prepare the command to exec
 	cmd, err := client.Command(
 		r.ctx,
 		&client.CommandConfig{
 			Runtime: r.handler.RuntimePath,
+			Address: publishAddress,
 			Path:    c.BundlePath(),
 			Args:    args,
 		},
@@ -787,6 +811,10 @@ func (r *runtimeVM) DeleteContainer(ctx context.Context, c *Container) error {
 		metrics.Instance().MetricContainersOOMCountTotalDelete(c.Name())
 	}
 
+	if err := os.Remove(ShimOOMFilePath(r.exitsPath, c.ID())); err != nil && !os.IsNotExist(err) {
+		log.Warnf(ctx, "Unable to remove OOM file: %v", err)
+	}
+
 	return r.deleteContainer(c, false)
 }
 
@@ -910,6 +938,10 @@ func (r *runtimeVM) updateContainerStatus(ctx context.Context, c *Container) err
 
 	if exitCode != 0 {
 		oomFilePath := filepath.Join(c.bundlePath, "oom")
+		if r.handler.RuntimeType == config.RuntimeTypeShimV2 {
+			oomFilePath = ShimOOMFilePath(r.exitsPath, c.ID())
+		}
+
 		if _, err = os.Stat(oomFilePath); err == nil {
 			c.state.OOMKilled = true
 
@@ -1016,8 +1048,14 @@ func (r *runtimeVM) createContainerLoggers(ctx context.Context, logPath string)
 	var stdoutCh, stderrCh <-chan struct{}
 
 	wc := cioutil.NewSerialWriteCloser(f)
-	stdout, stdoutCh = cio.NewCRILogger(logPath, wc, cio.Stdout, -1)
-	stderr, stderrCh = cio.NewCRILogger(logPath, wc, cio.Stderr, -1)
+
+	if r.handler.LogFormat == config.LogFormatJSON {
+		stdout, stdoutCh = newJSONLogger(ctx, logPath, wc, cio.Stdout)
+		stderr, stderrCh = newJSONLogger(ctx, logPath, wc, cio.Stderr)
+	} else {
+		stdout, stdoutCh = cio.NewCRILogger(logPath, wc, cio.Stdout, -1)
+		stderr, stderrCh = cio.NewCRILogger(logPath, wc, cio.Stderr, -1)
+	}
 
 	go func() {
 		if stdoutCh != nil {
@@ -1109,14 +1147,6 @@ func (r *runtimeVM) AttachContainer(ctx context.Context, c *Container, inputStre
 	return nil
 }
 
-// PortForwardContainer forwards the specified port provides statistics of a container.
-func (r *runtimeVM) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
-	log.Debugf(ctx, "RuntimeVM.PortForwardContainer() start")
-	defer log.Debugf(ctx, "RuntimeVM.PortForwardContainer() end")
-
-	return nil
-}
-
 // ReopenContainerLog reopens the log file of a container.
 func (r *runtimeVM) ReopenContainerLog(ctx context.Context, c *Container) error {
 	log.Debugf(ctx, "RuntimeVM.ReopenContainerLog() start")
@@ -1226,20 +1256,109 @@ func (r *runtimeVM) closeIO(ctrID, execID string) error {
 	return nil
 }
 
-// CheckpointContainer not implemented for runtimeVM.
-func (r *runtimeVM) CheckpointContainer(ctx context.Context, c *Container, specgen *rspec.Spec, leaveRunning bool) error {
+// CheckpointContainer checkpoints a container using the task API of the shim.
+func (r *runtimeVM) CheckpointContainer(ctx context.Context, c *Container, specgen *rspec.Spec, leaveRunning bool, parentPath string) error {
 	log.Debugf(ctx, "RuntimeVM.CheckpointContainer() start")
 	defer log.Debugf(ctx, "RuntimeVM.CheckpointContainer() end")
 
-	return errors.New("checkpointing not implemented for runtimeVM")
+	// Lock the container
+	c.opLock.Lock()
+	defer c.opLock.Unlock()
+
+	// The task API has no notion of parent checkpoints, pre-dumps are
+	// therefore never requested for this runtime.
+	if parentPath != "" {
+		return errors.New("incremental checkpoints not supported for runtimeVM")
+	}
+
+	imagePath := c.CheckpointPath()
+	if err := os.MkdirAll(imagePath, 0o700); err != nil {
+		return fmt.Errorf("create checkpoint directory %s: %w", imagePath, err)
+	}
+
+	log.Debugf(ctx, "Writing checkpoint to %s", imagePath)
+
+	if _, err := r.task.Checkpoint(r.ctx, &task.CheckpointTaskRequest{
+		ID:   c.ID(),
+		Path: imagePath,
+	}); err != nil {
+		err = errdefs.FromGRPC(err)
+		if errdefs.IsNotImplemented(err) {
+			return fmt.Errorf("runtime handler %s does not support checkpointing: %w", r.handler.RuntimePath, err)
+		}
+
+		return fmt.Errorf("checkpoint container %s: %w", c.ID(), err)
+	}
+
+	c.SetCheckpointedAt(time.Now())
+
+	if leaveRunning {
+		return nil
+	}
+
+	// The shim leaves the task running after the checkpoint, so it has to be
+	// stopped the same way the OCI runtime does when not leaving it running.
+	stopCh := make(chan error)
+
+	go func() {
+		if _, err := r.wait(c.ID(), ""); err != nil && !errors.Is(err, errdefs.ErrNotFound) {
+			stopCh <- errdefs.FromGRPC(err)
+		}
+
+		close(stopCh)
+	}()
+
+	if err := r.kill(c.ID(), "", syscall.SIGKILL); err != nil {
+		return fmt.Errorf("stop checkpointed container %s: %w", c.ID(), err)
+	}
+
+	if err := r.waitCtrTerminate(syscall.SIGKILL, stopCh, killContainerTimeout); err != nil {
+		return fmt.Errorf("stop checkpointed container %s: %w", c.ID(), err)
+	}
+
+	c.state.Status = ContainerStateStopped
+	c.state.ExitCode = new(int32(0))
+	c.state.Finished = c.CheckpointedAt()
+
+	return nil
 }
 
-// RestoreContainer not implemented for runtimeVM.
+// PreDumpContainer not implemented for runtimeVM.
+func (r *runtimeVM) PreDumpContainer(ctx context.Context, c *Container, specgen *rspec.Spec, imagePath, parentPath string) error {
+	log.Debugf(ctx, "RuntimeVM.PreDumpContainer() start")
+	defer log.Debugf(ctx, "RuntimeVM.PreDumpContainer() end")
+
+	return errors.New("pre-dump not supported for runtimeVM")
+}
+
+// RestoreContainer restores a container by creating its task from the
+// checkpoint images.
 func (r *runtimeVM) RestoreContainer(ctx context.Context, c *Container, cgroupParent, mountLabel string) error {
 	log.Debugf(ctx, "RuntimeVM.RestoreContainer() start")
 	defer log.Debugf(ctx, "RuntimeVM.RestoreContainer() end")
 
-	return errors.New("restoring not implemented for runtimeVM")
+	// Let's try to stat() CRIU's inventory file. If it does not exist, it makes
+	// no sense to try a restore. This is a minimal check if a checkpoint exist.
+	if _, err := os.Stat(filepath.Join(c.CheckpointPath(), "inventory.img")); os.IsNotExist(err) {
+		return fmt.Errorf("a complete checkpoint for this container cannot be found, cannot restore: %w", err)
+	}
+
+	c.state.InitPid = 0
+	c.state.InitStartTime = ""
+
+	if err := r.CreateContainer(ctx, c, cgroupParent, true); err != nil {
+		return err
+	}
+
+	if err := r.StartContainer(ctx, c); err != nil {
+		return err
+	}
+
+	// Once the container is restored, update the metadata
+	c.state.Status = ContainerStateRunning
+	c.state.ExitCode = nil
+
+	return nil
 }
 
 func EncodeKataVirtualVolumeToBase64(ctx context.Context, volume *katavolume.KataVirtualVolume) (string, error) {
@@ -1263,10 +1382,52 @@ func (r *runtimeVM) ProbeMonitor(ctx context.Context, c *Container) error {
 	return nil
 }
 
-func (r *runtimeVM) ServeExecContainer(context.Context, *Container, []string, bool, bool, bool, bool) (string, error) {
-	return "", nil
+// ServeExecContainer returns the URL of the CRI-O streaming server for the
+// exec, because shims do not serve it themselves. The streaming server runs
+// the command through the Exec task API of the shim.
+func (r *runtimeVM) ServeExecContainer(ctx context.Context, c *Container, cmd []string, tty, stdin, stdout, stderr bool) (string, error) {
+	log.Debugf(ctx, "RuntimeVM.ServeExecContainer() start")
+	defer log.Debugf(ctx, "RuntimeVM.ServeExecContainer() end")
+
+	if r.runtime.streamServer == nil {
+		return "", errors.New("no streaming server available")
+	}
+
+	resp, err := r.runtime.streamServer.GetExec(&types.ExecRequest{
+		ContainerId: c.ID(),
+		Cmd:         cmd,
+		Tty:         tty,
+		Stdin:       stdin,
+		Stdout:      stdout,
+		Stderr:      stderr,
+	})
+	if err != nil {
+		return "", fmt.Errorf("get exec URL: %w", err)
+	}
+
+	return resp.GetUrl(), nil
 }
 
-func (r *runtimeVM) ServeAttachContainer(context.Context, *Container, bool, bool, bool) (string, error) {
-	return "", nil
+// ServeAttachContainer returns the URL of the CRI-O streaming server for the
+// attach, because shims do not serve it themselves.
+func (r *runtimeVM) ServeAttachContainer(ctx context.Context, c *Container, stdin, stdout, stderr bool) (string, error) {
+	log.Debugf(ctx, "RuntimeVM.ServeAttachContainer() start")
+	defer log.Debugf(ctx, "RuntimeVM.ServeAttachContainer() end")
+
+	if r.runtime.streamServer == nil {
+		return "", errors.New("no streaming server available")
+	}
+
+	resp, err := r.runtime.streamServer.GetAttach(&types.AttachRequest{
+		ContainerId: c.ID(),
+		Stdin:       stdin,
+		Stdout:      stdout,
+		Stderr:      stderr,
+		Tty:         c.terminal,
+	})
+	if err != nil {
+		return "", fmt.Errorf("get attach URL: %w", err)
+	}
+
+	return resp.GetUrl(), nil
 }
@@ -5,6 +5,7 @@ package oci
 import (
 	"context"
 	"errors"
+	"io"
 	"time"
 
 	cgroupsV1 "github.com/containerd/cgroups/stats/v1"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/cri-o/cri-o/internal/lib/stats"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/pkg/config"
 	"github.com/cri-o/cri-o/utils/errdefs"
 )
 
@@ -187,3 +189,17 @@ func metricsV2ToCgroupStats(ctx context.Context, m *cgroupsV2.Metrics) *stats.Cg
 		SystemNano: time.Now().UnixNano(),
 	}
 }
+
+// PortForwardContainer forwards the specified port into the provided container.
+// The containers of VM runtimes are not reachable from the network namespace
+// of the pod, which is why only shimv2 runtimes support port forwarding.
+func (r *runtimeVM) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
+	log.Debugf(ctx, "RuntimeVM.PortForwardContainer() start")
+	defer log.Debugf(ctx, "RuntimeVM.PortForwardContainer() end")
+
+	if r.handler.RuntimeType != config.RuntimeTypeShimV2 {
+		return nil
+	}
+
+	return portForwardContainer(ctx, c, netNsPath, port, stream)
+}
@@ -1,9 +1,27 @@
 package oci_test
 
 import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
+
+	"github.com/containerd/containerd/api/runtime/task/v2"
+	"github.com/containerd/ttrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/cri-o/cri-o/internal/oci"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
 )
 
 func TestParseShimAddress(t *testing.T) {
@@ -84,3 +102,317 @@ func TestParseShimAddress(t *testing.T) {
 		})
 	}
 }
+
+// fakeTaskService is a containerd task service recording the requests of
+// the runtime. Calls of unexpected methods panic.
+type fakeTaskService struct {
+	task.TaskService
+
+	mutex              sync.Mutex
+	createRequests     []*task.CreateTaskRequest
+	checkpointRequests []*task.CheckpointTaskRequest
+	killSignals        []uint32
+	checkpointErr      error
+	exited             chan struct{}
+	done               chan struct{}
+}
+
+func (f *fakeTaskService) Create(_ context.Context, req *task.CreateTaskRequest) (*task.CreateTaskResponse, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.createRequests = append(f.createRequests, req)
+
+	return &task.CreateTaskResponse{Pid: 42}, nil
+}
+
+func (f *fakeTaskService) Start(context.Context, *task.StartRequest) (*task.StartResponse, error) {
+	return &task.StartResponse{Pid: 42}, nil
+}
+
+func (f *fakeTaskService) Checkpoint(_ context.Context, req *task.CheckpointTaskRequest) (*emptypb.Empty, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.checkpointRequests = append(f.checkpointRequests, req)
+
+	if f.checkpointErr != nil {
+		return nil, f.checkpointErr
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeTaskService) Kill(_ context.Context, req *task.KillRequest) (*emptypb.Empty, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.killSignals = append(f.killSignals, req.GetSignal())
+	if req.GetSignal() == uint32(syscall.SIGKILL) {
+		close(f.exited)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeTaskService) Wait(ctx context.Context, _ *task.WaitRequest) (*task.WaitResponse, error) {
+	select {
+	case <-f.exited:
+		return &task.WaitResponse{ExitStatus: 137}, nil
+	case <-f.done:
+		return nil, errors.New("task service stopped")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (f *fakeTaskService) Delete(context.Context, *task.DeleteRequest) (*task.DeleteResponse, error) {
+	return &task.DeleteResponse{}, nil
+}
+
+func (f *fakeTaskService) Shutdown(context.Context, *task.ShutdownRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+// newShimV2Runtime serves the fake task service on a unix socket and returns
+// a runtime with a shimv2 runtime handler, whose shim binary only prints the
+// address of the socket.
+func newShimV2Runtime(t *testing.T) (*oci.Runtime, *fakeTaskService) {
+	t.Helper()
+
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "shim.sock")
+
+	fake := &fakeTaskService{
+		exited: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	server, err := ttrpc.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task.RegisterTaskService(server, fake)
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go server.Serve(context.Background(), listener) //nolint:errcheck // stopped on cleanup
+
+	t.Cleanup(func() {
+		close(fake.done)
+		server.Close()
+	})
+
+	shim := filepath.Join(dir, "containerd-shim-fake-v2")
+	if err := os.WriteFile(shim, []byte("#!/bin/sh\necho unix://"+socket+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := libconfig.DefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.ContainerAttachSocketDir = t.TempDir()
+	cfg.ContainerExitsDir = t.TempDir()
+	cfg.DefaultRuntime = "shimv2"
+	cfg.Runtimes = libconfig.Runtimes{
+		"shimv2": &libconfig.RuntimeHandler{
+			RuntimePath:            shim,
+			RuntimeType:            libconfig.RuntimeTypeShimV2,
+			RuntimeRoot:            t.TempDir(),
+			ContainerCreateTimeout: 10,
+		},
+	}
+
+	runtime, err := oci.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return runtime, fake
+}
+
+// newShimV2Container returns a container of the shimv2 runtime handler.
+func newShimV2Container(t *testing.T, id string) *oci.Container {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	c, err := oci.NewContainer(id, "name", dir, filepath.Join(dir, "ctr.log"),
+		map[string]string{}, map[string]string{}, map[string]string{},
+		"", nil, nil, "", &types.ContainerMetadata{}, "sandbox",
+		false, false, false, "shimv2", dir, time.Now(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestRuntimeVMCheckpointContainer(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		leaveRunning bool
+		expectKill   bool
+		expectStatus string
+	}{
+		{
+			name:         "stop after checkpoint",
+			expectKill:   true,
+			expectStatus: oci.ContainerStateStopped,
+		},
+		{
+			name:         "leave running",
+			leaveRunning: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			runtime, fake := newShimV2Runtime(t)
+			c := newShimV2Container(t, "checkpoint")
+
+			if err := runtime.CreateContainer(context.Background(), c, "", false); err != nil {
+				t.Fatalf("CreateContainer() error: %v", err)
+			}
+
+			if err := runtime.CheckpointContainer(context.Background(), c, nil, tc.leaveRunning, ""); err != nil {
+				t.Fatalf("CheckpointContainer() error: %v", err)
+			}
+
+			fake.mutex.Lock()
+			defer fake.mutex.Unlock()
+
+			if len(fake.createRequests) != 1 || fake.createRequests[0].GetCheckpoint() != "" {
+				t.Errorf("expected a single create request without checkpoint, got %v", fake.createRequests)
+			}
+
+			if len(fake.checkpointRequests) != 1 {
+				t.Fatalf("expected a single checkpoint request, got %d", len(fake.checkpointRequests))
+			}
+
+			req := fake.checkpointRequests[0]
+			if req.GetID() != c.ID() || req.GetPath() != c.CheckpointPath() || req.GetOptions() != nil {
+				t.Errorf("unexpected checkpoint request: %v", req)
+			}
+
+			if _, err := os.Stat(c.CheckpointPath()); err != nil {
+				t.Errorf("checkpoint directory not created: %v", err)
+			}
+
+			if killed := slices.Contains(fake.killSignals, uint32(syscall.SIGKILL)); killed != tc.expectKill {
+				t.Errorf("expected kill %v, got signals %v", tc.expectKill, fake.killSignals)
+			}
+
+			if status := string(c.State().Status); status != tc.expectStatus {
+				t.Errorf("expected status %q, got %q", tc.expectStatus, status)
+			}
+
+			if c.CheckpointedAt().IsZero() {
+				t.Error("checkpoint time not set")
+			}
+		})
+	}
+}
+
+func TestRuntimeVMCheckpointContainerFailure(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		parentPath    string
+		checkpointErr error
+		expectErr     string
+		expectRequest bool
+	}{
+		{
+			name:       "incremental checkpoint",
+			parentPath: "../pre-dump",
+			expectErr:  "incremental checkpoints not supported",
+		},
+		{
+			name:          "not implemented by the shim",
+			checkpointErr: status.Error(codes.Unimplemented, "checkpoint not implemented"),
+			expectErr:     "does not support checkpointing",
+			expectRequest: true,
+		},
+		{
+			name:          "checkpoint failure",
+			checkpointErr: errors.New("criu failed"),
+			expectErr:     "checkpoint container checkpoint",
+			expectRequest: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			runtime, fake := newShimV2Runtime(t)
+			fake.checkpointErr = tc.checkpointErr
+			c := newShimV2Container(t, "checkpoint")
+
+			if err := runtime.CreateContainer(context.Background(), c, "", false); err != nil {
+				t.Fatalf("CreateContainer() error: %v", err)
+			}
+
+			err := runtime.CheckpointContainer(context.Background(), c, nil, false, tc.parentPath)
+			if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.expectErr, err)
+			}
+
+			fake.mutex.Lock()
+			defer fake.mutex.Unlock()
+
+			if requested := len(fake.checkpointRequests) > 0; requested != tc.expectRequest {
+				t.Errorf("expected checkpoint request %v, got %v", tc.expectRequest, fake.checkpointRequests)
+			}
+
+			if len(fake.killSignals) != 0 {
+				t.Errorf("expected no kill after failure, got signals %v", fake.killSignals)
+			}
+
+			if !c.CheckpointedAt().IsZero() {
+				t.Error("checkpoint time set after failure")
+			}
+		})
+	}
+}
+
+func TestRuntimeVMRestoreContainer(t *testing.T) {
+	runtime, fake := newShimV2Runtime(t)
+	c := newShimV2Container(t, "restore")
+
+	if err := runtime.RestoreContainer(context.Background(), c, "", ""); err == nil {
+		t.Fatal("expected RestoreContainer() to fail without checkpoint")
+	}
+
+	if err := os.MkdirAll(c.CheckpointPath(), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(c.CheckpointPath(), "inventory.img"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runtime.RestoreContainer(context.Background(), c, "", ""); err != nil {
+		t.Fatalf("RestoreContainer() error: %v", err)
+	}
+
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	if len(fake.createRequests) != 1 {
+		t.Fatalf("expected a single create request, got %d", len(fake.createRequests))
+	}
+
+	req := fake.createRequests[0]
+	if req.GetID() != c.ID() || req.GetCheckpoint() != c.CheckpointPath() || req.GetBundle() != c.BundlePath() {
+		t.Errorf("unexpected create request: %v", req)
+	}
+
+	if status := string(c.State().Status); status != oci.ContainerStateRunning {
+		t.Errorf("expected status %q, got %q", oci.ContainerStateRunning, status)
+	}
+
+	if c.State().InitPid != 42 {
+		t.Errorf("expected init PID 42, got %d", c.State().InitPid)
+	}
+}
@@ -5,6 +5,7 @@ package oci
 import (
 	"context"
 	"errors"
+	"io"
 
 	"github.com/cri-o/cri-o/internal/lib/stats"
 )
@@ -18,3 +19,8 @@ func (r *runtimeVM) CgroupStats(ctx context.Context, c *Container, _ string) (*s
 func (r *runtimeVM) DiskStats(ctx context.Context, c *Container, _ string) (*stats.DiskStats, error) {
 	return nil, errors.New("disk stats not supported on this platform")
 }
+
+// PortForwardContainer is not supported on non-Linux platforms for VM runtimes.
+func (r *runtimeVM) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
+	return nil
+}
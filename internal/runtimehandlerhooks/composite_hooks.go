@@ -2,6 +2,7 @@ package runtimehandlerhooks
 
 import (
 	"context"
+	"errors"
 
 	"github.com/opencontainers/runtime-tools/generate"
 
@@ -51,12 +52,16 @@ func (c *CompositeHooks) PreStop(ctx context.Context, cont *oci.Container, s *sa
 	return nil
 }
 
+// PostStop runs all hooks even if one fails, so that every hook can restore
+// the resources it changed for the container.
 func (c *CompositeHooks) PostStop(ctx context.Context, cont *oci.Container, s *sandbox.Sandbox) error {
+	var errs []error
+
 	for _, h := range c.hooks {
 		if err := h.PostStop(ctx, cont, s); err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
@@ -0,0 +1,128 @@
+package runtimehandlerhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/generate"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/utils/cmdrunner"
+)
+
+// ExecHooks run an external binary at the stages of the container
+// lifecycle, passing the container spec and the sandbox as JSON on stdin.
+type ExecHooks struct {
+	name   string
+	config *libconfig.ExecHookConfig
+}
+
+// execHookInput is the JSON passed to the binary of an exec hook.
+type execHookInput struct {
+	Stage       string          `json:"stage"`
+	ContainerID string          `json:"container_id"`
+	Spec        *rspec.Spec     `json:"spec"`
+	Sandbox     execHookSandbox `json:"sandbox"`
+}
+
+// execHookSandbox is the sandbox passed to the binary of an exec hook.
+type execHookSandbox struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Namespace      string            `json:"namespace"`
+	KubeName       string            `json:"kube_name"`
+	Labels         map[string]string `json:"labels"`
+	Annotations    map[string]string `json:"annotations"`
+	CgroupParent   string            `json:"cgroup_parent"`
+	RuntimeHandler string            `json:"runtime_handler"`
+}
+
+// NewExecHooks creates the hooks for the configured exec hook.
+func NewExecHooks(name string, config *libconfig.ExecHookConfig) *ExecHooks {
+	return &ExecHooks{name: name, config: config}
+}
+
+func (e *ExecHooks) PreCreate(ctx context.Context, specgen *generate.Generator, s *sandbox.Sandbox, c *oci.Container) error {
+	return e.run(ctx, libconfig.HookStagePreCreate, specgen.Config, c, s)
+}
+
+func (e *ExecHooks) PreStart(ctx context.Context, c *oci.Container, s *sandbox.Sandbox) error {
+	spec := c.Spec()
+
+	return e.run(ctx, libconfig.HookStagePreStart, &spec, c, s)
+}
+
+func (e *ExecHooks) PreStop(ctx context.Context, c *oci.Container, s *sandbox.Sandbox) error {
+	spec := c.Spec()
+
+	return e.run(ctx, libconfig.HookStagePreStop, &spec, c, s)
+}
+
+func (e *ExecHooks) PostStop(ctx context.Context, c *oci.Container, s *sandbox.Sandbox) error {
+	spec := c.Spec()
+
+	return e.run(ctx, libconfig.HookStagePostStop, &spec, c, s)
+}
+
+// run runs the binary of the hook if it is configured for the stage. Failures
+// are only logged if the failure policy is "ignore", or at the stop stages,
+// where a broken hook would otherwise keep the container from being stopped.
+func (e *ExecHooks) run(ctx context.Context, stage string, spec *rspec.Spec, c *oci.Container, s *sandbox.Sandbox) error {
+	if !e.config.RunsAt(stage) {
+		return nil
+	}
+
+	input, err := json.Marshal(&execHookInput{
+		Stage:       stage,
+		ContainerID: c.ID(),
+		Spec:        spec,
+		Sandbox: execHookSandbox{
+			ID:             s.ID(),
+			Name:           s.Name(),
+			Namespace:      s.Namespace(),
+			KubeName:       s.KubeName(),
+			Labels:         s.Labels(),
+			Annotations:    s.Annotations(),
+			CgroupParent:   s.CgroupParent(),
+			RuntimeHandler: s.RuntimeHandler(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal input of exec hook %q: %w", e.name, err)
+	}
+
+	if e.config.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, e.config.Timeout)
+		defer cancel()
+	}
+
+	cmd := cmdrunner.CommandContext(ctx, e.config.Path, e.config.Args...)
+	// An empty environment keeps the binary from inheriting the one of CRI-O.
+	cmd.Env = append([]string{}, e.config.Env...)
+	cmd.Stdin = bytes.NewReader(input)
+
+	log.Debugf(ctx, "Running exec hook %q at %s stage of container %s", e.name, stage, c.ID())
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		err = fmt.Errorf("exec hook %q failed at %s stage: %w: %s", e.name, stage, err, strings.TrimSpace(string(output)))
+		if e.config.FailurePolicy == libconfig.HookFailurePolicyIgnore ||
+			stage == libconfig.HookStagePreStop || stage == libconfig.HookStagePostStop {
+			log.Warnf(ctx, "Ignoring failure of container %s: %v", c.ID(), err)
+
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,305 @@
+package runtimehandlerhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/generate"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/pkg/config"
+)
+
+// failingPostStopHooks fail at the post-stop stage.
+type failingPostStopHooks struct {
+	calls int
+}
+
+func (*failingPostStopHooks) PreCreate(context.Context, *generate.Generator, *sandbox.Sandbox, *oci.Container) error {
+	return nil
+}
+
+func (*failingPostStopHooks) PreStart(context.Context, *oci.Container, *sandbox.Sandbox) error {
+	return nil
+}
+
+func (*failingPostStopHooks) PreStop(context.Context, *oci.Container, *sandbox.Sandbox) error {
+	return nil
+}
+
+func (f *failingPostStopHooks) PostStop(context.Context, *oci.Container, *sandbox.Sandbox) error {
+	f.calls++
+
+	return errors.New("post-stop failed")
+}
+
+// The actual test suite.
+var _ = Describe("ExecHooks", func() {
+	var (
+		dir       string
+		output    string
+		sb        *sandbox.Sandbox
+		container *oci.Container
+		ctx       = context.Background()
+	)
+
+	writeHook := func(script string) string {
+		path := filepath.Join(dir, "hook")
+		Expect(os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755)).To(Succeed())
+
+		return path
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		output = filepath.Join(dir, "output")
+
+		var err error
+
+		container, err = oci.NewContainer("containerID", "", "", "",
+			make(map[string]string), make(map[string]string),
+			make(map[string]string), "pauseImage", nil, nil, "",
+			&types.ContainerMetadata{}, "sandboxID", false, false,
+			false, "", "", time.Now(), "")
+		Expect(err).ToNot(HaveOccurred())
+		container.SetSpec(&specs.Spec{Hostname: "container"})
+
+		sbox := sandbox.NewBuilder()
+		sbox.SetCreatedAt(time.Now())
+		sbox.SetID("sandboxID")
+		sbox.SetName("sandboxName")
+		sbox.SetLogDir("test")
+		sbox.SetShmPath("test")
+		sbox.SetNamespace("default")
+		sbox.SetKubeName("pod")
+		sbox.SetMountLabel("test")
+		sbox.SetProcessLabel("test")
+		sbox.SetCgroupParent("kubepods.slice")
+		sbox.SetRuntimeHandler("runc")
+		sbox.SetResolvPath("")
+		sbox.SetHostname("")
+		sbox.SetPortMappings([]*hostport.PortMapping{})
+		sbox.SetHostNetwork(false)
+		sbox.SetUsernsMode("")
+		sbox.SetPodLinuxOverhead(nil)
+		sbox.SetPodLinuxResources(nil)
+		sbox.SetPrivileged(false)
+		Expect(sbox.SetCRISandbox(
+			sbox.ID(),
+			map[string]string{"app": "test"},
+			map[string]string{},
+			&types.PodSandboxMetadata{},
+		)).To(Succeed())
+
+		sb, err = sbox.GetSandbox()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should pass the container spec and sandbox to the binary", func() {
+		// Given
+		hooks := NewExecHooks("test", &config.ExecHookConfig{
+			Path: writeHook("cat > " + output),
+		})
+
+		// When
+		err := hooks.PreStart(ctx, container, sb)
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+
+		content, err := os.ReadFile(output)
+		Expect(err).ToNot(HaveOccurred())
+
+		input := &execHookInput{}
+		Expect(json.Unmarshal(content, input)).To(Succeed())
+		Expect(input.Stage).To(Equal(config.HookStagePreStart))
+		Expect(input.ContainerID).To(Equal("containerID"))
+		Expect(input.Spec.Hostname).To(Equal("container"))
+		Expect(input.Sandbox.ID).To(Equal("sandboxID"))
+		Expect(input.Sandbox.KubeName).To(Equal("pod"))
+		Expect(input.Sandbox.Labels).To(HaveKeyWithValue("app", "test"))
+		Expect(input.Sandbox.RuntimeHandler).To(Equal("runc"))
+	})
+
+	It("should only run at the configured stages", func() {
+		// Given
+		hooks := NewExecHooks("test", &config.ExecHookConfig{
+			Path:   writeHook("echo \"$1\" >> " + output),
+			Args:   []string{"called"},
+			Stages: []string{config.HookStagePostStop},
+		})
+
+		// When
+		Expect(hooks.PreStart(ctx, container, sb)).To(Succeed())
+		Expect(hooks.PreStop(ctx, container, sb)).To(Succeed())
+		Expect(hooks.PostStop(ctx, container, sb)).To(Succeed())
+
+		// Then
+		content, err := os.ReadFile(output)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("called\n"))
+	})
+
+	It("should fail if the binary fails", func() {
+		// Given
+		hooks := NewExecHooks("test", &config.ExecHookConfig{
+			Path:          writeHook("echo broken; exit 1"),
+			FailurePolicy: config.HookFailurePolicyFail,
+		})
+
+		// When
+		err := hooks.PreStart(ctx, container, sb)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("broken"))
+	})
+
+	It("should fail if the binary times out", func() {
+		// Given
+		hooks := NewExecHooks("test", &config.ExecHookConfig{
+			Path:    writeHook("exec sleep 10"),
+			Timeout: 100 * time.Millisecond,
+		})
+
+		// When
+		err := hooks.PreStart(ctx, container, sb)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should ignore failures with the ignore policy", func() {
+		// Given
+		hooks := NewExecHooks("test", &config.ExecHookConfig{
+			Path:          writeHook("exit 1"),
+			FailurePolicy: config.HookFailurePolicyIgnore,
+		})
+
+		// When
+		err := hooks.PreStop(ctx, container, sb)
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should ignore failures at the stop stages", func() {
+		// Given
+		hooks := NewExecHooks("test", &config.ExecHookConfig{
+			Path:          writeHook("exit 1"),
+			FailurePolicy: config.HookFailurePolicyFail,
+		})
+
+		// When
+		preStopErr := hooks.PreStop(ctx, container, sb)
+		postStopErr := hooks.PostStop(ctx, container, sb)
+
+		// Then
+		Expect(preStopErr).ToNot(HaveOccurred())
+		Expect(postStopErr).ToNot(HaveOccurred())
+	})
+
+	It("should not pass the environment of CRI-O to the binary", func() {
+		// Given
+		GinkgoT().Setenv("EXEC_HOOK_TEST_INHERITED", "inherited")
+		hooks := NewExecHooks("test", &config.ExecHookConfig{
+			Path: writeHook("echo \"$EXEC_HOOK_TEST_INHERITED$CONFIGURED\" > " + output),
+			Env:  []string{"CONFIGURED=configured"},
+		})
+
+		// When
+		err := hooks.PreStart(ctx, container, sb)
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+
+		content, err := os.ReadFile(output)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("configured\n"))
+	})
+
+	It("should run all post-stop hooks of a chain", func() {
+		// Given
+		failing := &failingPostStopHooks{}
+		hooks := &CompositeHooks{hooks: []RuntimeHandlerHooks{
+			failing,
+			NewExecHooks("test", &config.ExecHookConfig{
+				Path: writeHook("echo called > " + output),
+			}),
+			failing,
+		}}
+
+		// When
+		err := hooks.PostStop(ctx, container, sb)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(failing.calls).To(Equal(2))
+
+		content, err := os.ReadFile(output)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("called\n"))
+	})
+
+	Describe("HooksRetriever", func() {
+		It("should return the handler hooks in order", func() {
+			// Given
+			cfg := &config.Config{
+				RuntimeConfig: config.RuntimeConfig{
+					Runtimes: config.Runtimes{
+						"runc": &config.RuntimeHandler{
+							HandlerHooks: []string{"first", "second"},
+						},
+					},
+					ExecHooks: config.ExecHooks{
+						"first":  &config.ExecHookConfig{Path: writeHook("exit 0")},
+						"second": &config.ExecHookConfig{Path: writeHook("exit 0")},
+					},
+				},
+			}
+
+			// When
+			hooks := NewHooksRetriever(ctx, cfg).Get(ctx, "runc", nil)
+
+			// Then
+			composite, ok := hooks.(*CompositeHooks)
+			Expect(ok).To(BeTrue())
+			Expect(composite.hooks).To(HaveLen(2))
+			Expect(composite.hooks[0].(*ExecHooks).name).To(Equal("first"))
+			Expect(composite.hooks[1].(*ExecHooks).name).To(Equal("second"))
+		})
+
+		It("should use registered hooks", func() {
+			// Given
+			cfg := &config.Config{
+				RuntimeConfig: config.RuntimeConfig{
+					Runtimes: config.Runtimes{
+						"runc": &config.RuntimeHandler{
+							HandlerHooks: []string{"custom"},
+						},
+					},
+				},
+			}
+			hooksRetriever := NewHooksRetriever(ctx, cfg)
+			custom := NewExecHooks("custom", &config.ExecHookConfig{})
+
+			// When
+			hooksRetriever.Register("custom", func(string) RuntimeHandlerHooks {
+				return custom
+			})
+
+			// Then
+			Expect(hooksRetriever.Get(ctx, "runc", nil)).To(Equal(custom))
+		})
+	})
+})
@@ -104,11 +104,5 @@ func calculateGOMAXPROCS(shares, fallbackMaxProcs int64) int64 {
 // Injection is skipped if GOMAXPROCS is already set in the OCI spec's process env
 // (which already includes default_env values merged by setupContainerEnvironmentAndWorkdir).
 func injectGOMAXPROCS(specgen *generate.Generator, maxProcs int64) {
-	for _, env := range specgen.Config.Process.Env {
-		if strings.HasPrefix(env, "GOMAXPROCS=") {
-			return
-		}
-	}
-
-	specgen.AddProcessEnv("GOMAXPROCS", strconv.FormatInt(maxProcs, 10))
+	injectProcessEnv(specgen, "GOMAXPROCS", strconv.FormatInt(maxProcs, 10))
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/opencontainers/runtime-tools/generate"
 
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
 	libconfig "github.com/cri-o/cri-o/pkg/config"
 )
@@ -29,8 +30,67 @@ type HighPerformanceHook interface {
 	RuntimeHandlerHooks
 }
 
+// HookFactory returns the hooks of a runtime handler hook for the runtime
+// handler, or nil if the hook does not apply to it.
+type HookFactory func(runtimeName string) RuntimeHandlerHooks
+
 // HooksRetriever allows retrieving the runtime hooks for a given sandbox.
 type HooksRetriever struct {
 	config               *libconfig.Config
 	highPerformanceHooks RuntimeHandlerHooks
+	factories            map[string]HookFactory
+}
+
+// Register adds a named runtime handler hook which can be referenced by the
+// handler_hooks of the runtime handlers. An existing hook of the same name is
+// replaced.
+func (hr *HooksRetriever) Register(name string, factory HookFactory) {
+	if hr.factories == nil {
+		hr.factories = map[string]HookFactory{}
+	}
+
+	hr.factories[name] = factory
+}
+
+// registerExecHooks registers the configured exec hooks.
+func (hr *HooksRetriever) registerExecHooks() {
+	for name, config := range hr.config.ExecHooks {
+		hooks := NewExecHooks(name, config)
+		hr.Register(name, func(string) RuntimeHandlerHooks {
+			return hooks
+		})
+	}
+}
+
+// configuredHooks returns the hooks listed in the handler_hooks of the runtime
+// handler, in order.
+func (hr *HooksRetriever) configuredHooks(ctx context.Context, runtimeName string) RuntimeHandlerHooks {
+	hooks := []RuntimeHandlerHooks{}
+
+	for _, name := range hr.config.Runtimes[runtimeName].HandlerHooks {
+		factory, ok := hr.factories[name]
+		if !ok {
+			log.Warnf(ctx, "Skipping handler hook %q of runtime %s: not available", name, runtimeName)
+
+			continue
+		}
+
+		if h := factory(runtimeName); h != nil {
+			hooks = append(hooks, h)
+		}
+	}
+
+	return combineHooks(hooks)
+}
+
+// combineHooks returns nil, the single hook or a CompositeHooks chain.
+func combineHooks(hooks []RuntimeHandlerHooks) RuntimeHandlerHooks {
+	switch len(hooks) {
+	case 0:
+		return nil
+	case 1:
+		return hooks[0]
+	default:
+		return &CompositeHooks{hooks: hooks}
+	}
 }
@@ -33,16 +33,49 @@ func NewHooksRetriever(ctx context.Context, config *libconfig.Config) *HooksRetr
 		}
 	}
 
+	rhh.Register(libconfig.HandlerHookHighPerformance, rhh.getHighPerformanceHooks)
+	rhh.Register(libconfig.HandlerHookCPULoadBalancing, func(string) RuntimeHandlerHooks {
+		return &DefaultCPULoadBalanceHooks{
+			CgroupManager: config.CgroupManager(),
+		}
+	})
+	rhh.Register(libconfig.HandlerHookGOMAXPROCS, func(string) RuntimeHandlerHooks {
+		if config.MinInjectedGOMAXPROCS <= 0 {
+			return nil
+		}
+
+		return &GomaxprocsHooks{
+			fallback: config.MinInjectedGOMAXPROCS,
+		}
+	})
+	rhh.Register(libconfig.HandlerHookRuntimeTuning, func(string) RuntimeHandlerHooks {
+		if len(config.RuntimeTuning) == 0 {
+			return nil
+		}
+
+		return &RuntimeTuningHooks{
+			runtimes: config.RuntimeTuning,
+		}
+	})
+	rhh.registerExecHooks()
+
 	return rhh
 }
 
-// Get checks runtime name or the sandbox's annotations for allowed high performance annotations and
-// the config for GOMAXPROCS injection. It returns a single hook, a CompositeHooks chain, or nil.
+// Get returns the hooks listed in the handler_hooks of the runtime handler if
+// set. Otherwise it checks runtime name or the sandbox's annotations for allowed
+// high performance annotations and the config for GOMAXPROCS injection and
+// runtime tuning.
+// It returns a single hook, a CompositeHooks chain, or nil.
 func (hr *HooksRetriever) Get(ctx context.Context, runtimeName string, sandboxAnnotations map[string]string) RuntimeHandlerHooks {
+	runtimeConfig, ok := hr.config.Runtimes[runtimeName]
+	if ok && len(runtimeConfig.HandlerHooks) > 0 {
+		return hr.configuredHooks(ctx, runtimeName)
+	}
+
 	var hooks []RuntimeHandlerHooks
 
 	if strings.Contains(runtimeName, HighPerformance) || highPerformanceAnnotationsSpecified(sandboxAnnotations) {
-		runtimeConfig, ok := hr.config.Runtimes[runtimeName]
 		if !ok {
 			// This shouldn't happen because runtime is already validated
 			log.Errorf(ctx, "Config of runtime %s is not found", runtimeName)
@@ -50,21 +83,7 @@ func (hr *HooksRetriever) Get(ctx context.Context, runtimeName string, sandboxAn
 			return nil
 		}
 
-		if hr.highPerformanceHooks == nil {
-			hr.highPerformanceHooks = &HighPerformanceHooks{
-				CgroupManager:             hr.config.CgroupManager(),
-				irqBalanceConfigFile:      hr.config.IrqBalanceConfigFile,
-				cpusetLock:                sync.Mutex{},
-				updateIRQSMPAffinityLock:  sync.Mutex{},
-				irqSMPAffinityDisabledSet: map[string]struct{}{},
-				sharedCPUs:                hr.config.SharedCPUSet,
-				irqSMPAffinityFile:        IrqSmpAffinityProcFile,
-				execCPUAffinity:           runtimeConfig.ExecCPUAffinity,
-				sysCPUDir:                 sysCPUDir,
-			}
-		}
-
-		hooks = append(hooks, hr.highPerformanceHooks)
+		hooks = append(hooks, hr.getHighPerformanceHooks(runtimeName))
 	} else if cpuLoadBalancingAllowed(hr.config) {
 		hooks = append(hooks, &DefaultCPULoadBalanceHooks{
 			CgroupManager: hr.config.CgroupManager(),
@@ -77,14 +96,33 @@ func (hr *HooksRetriever) Get(ctx context.Context, runtimeName string, sandboxAn
 		})
 	}
 
-	switch len(hooks) {
-	case 0:
-		return nil
-	case 1:
-		return hooks[0]
-	default:
-		return &CompositeHooks{hooks: hooks}
+	if len(hr.config.RuntimeTuning) > 0 {
+		hooks = append(hooks, &RuntimeTuningHooks{
+			runtimes: hr.config.RuntimeTuning,
+		})
+	}
+
+	return combineHooks(hooks)
+}
+
+// getHighPerformanceHooks returns the high-performance hooks, which are shared
+// by all runtime handlers and created on first use.
+func (hr *HooksRetriever) getHighPerformanceHooks(runtimeName string) RuntimeHandlerHooks {
+	if hr.highPerformanceHooks == nil {
+		hr.highPerformanceHooks = &HighPerformanceHooks{
+			CgroupManager:             hr.config.CgroupManager(),
+			irqBalanceConfigFile:      hr.config.IrqBalanceConfigFile,
+			cpusetLock:                sync.Mutex{},
+			updateIRQSMPAffinityLock:  sync.Mutex{},
+			irqSMPAffinityDisabledSet: map[string]struct{}{},
+			sharedCPUs:                hr.config.SharedCPUSet,
+			irqSMPAffinityFile:        IrqSmpAffinityProcFile,
+			execCPUAffinity:           hr.config.Runtimes[runtimeName].ExecCPUAffinity,
+			sysCPUDir:                 sysCPUDir,
+		}
 	}
+
+	return hr.highPerformanceHooks
 }
 
 func highPerformanceAnnotationsSpecified(annotations map[string]string) bool {
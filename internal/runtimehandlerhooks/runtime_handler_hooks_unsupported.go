@@ -23,11 +23,18 @@ func NewHooksRetriever(ctx context.Context, config *libconfig.Config) *HooksRetr
 		highPerformanceHooks: nil,
 	}
 
+	rhh.registerExecHooks()
+
 	return rhh
 }
 
-// Get always returns DefaultCPULoadBalanceHooks for non-linux architectures.
+// Get returns the hooks listed in the handler_hooks of the runtime handler if
+// set, otherwise DefaultCPULoadBalanceHooks for non-linux architectures.
 func (hr *HooksRetriever) Get(ctx context.Context, runtimeName string, sandboxAnnotations map[string]string) RuntimeHandlerHooks {
+	if runtimeConfig, ok := hr.config.Runtimes[runtimeName]; ok && len(runtimeConfig.HandlerHooks) > 0 {
+		return hr.configuredHooks(ctx, runtimeName)
+	}
+
 	return &DefaultCPULoadBalanceHooks{}
 }
 
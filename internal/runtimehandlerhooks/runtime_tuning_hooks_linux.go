@@ -0,0 +1,169 @@
+package runtimehandlerhooks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/runtime-tools/generate"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/utils/cpuset"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	crioann "github.com/cri-o/cri-o/pkg/annotations/v2"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
+)
+
+const (
+	// runtimeTuningHeapPercentage is the percentage of the memory limit
+	// used as the maximum heap size of the language runtimes.
+	runtimeTuningHeapPercentage = 75
+
+	// Bounds of UV_THREADPOOL_SIZE, the lower one being the libuv default.
+	minUVThreadpoolSize = 4
+	maxUVThreadpoolSize = 1024
+)
+
+// RuntimeTuningHooks inject environment variables tuning language runtimes
+// (JVM, Node.js and .NET) to the CPU and memory limits of the container,
+// which these runtimes otherwise do not or only partly detect.
+type RuntimeTuningHooks struct {
+	runtimes []string
+}
+
+func (r *RuntimeTuningHooks) PreCreate(ctx context.Context, specgen *generate.Generator, s *sandbox.Sandbox, c *oci.Container) error {
+	log.Infof(ctx, "Run runtime tuning runtime handler pre-create hook for the container %q", c.ID())
+
+	skipped := skippedRuntimeTunings(s.Annotations(), c.CRIContainer().GetMetadata().GetName())
+	cpus := containerCPUCount(specgen)
+
+	var memoryLimit int64
+
+	if lspec := specgen.Config.Linux; lspec != nil && lspec.Resources != nil &&
+		lspec.Resources.Memory != nil && lspec.Resources.Memory.Limit != nil {
+		memoryLimit = *lspec.Resources.Memory.Limit
+	}
+
+	heapMiB := memoryLimit / 100 * runtimeTuningHeapPercentage / (1 << 20)
+
+	for _, runtime := range r.runtimes {
+		if slices.Contains(skipped, runtime) {
+			log.Debugf(ctx, "Skipping %s runtime tuning: %s annotation is set", runtime, crioann.SkipRuntimeTuning)
+
+			continue
+		}
+
+		switch runtime {
+		case libconfig.RuntimeTuningJVM:
+			options := []string{}
+			if cpus > 0 {
+				options = append(options, "-XX:ActiveProcessorCount="+strconv.Itoa(cpus))
+			}
+
+			if memoryLimit > 0 {
+				options = append(options, fmt.Sprintf("-XX:MaxRAMPercentage=%d.0", runtimeTuningHeapPercentage))
+			}
+
+			if len(options) > 0 {
+				injectProcessEnv(specgen, "JAVA_TOOL_OPTIONS", strings.Join(options, " "))
+			}
+
+		case libconfig.RuntimeTuningNode:
+			if cpus > 0 {
+				injectProcessEnv(specgen, "UV_THREADPOOL_SIZE", strconv.Itoa(min(max(cpus, minUVThreadpoolSize), maxUVThreadpoolSize)))
+			}
+
+			if heapMiB > 0 {
+				injectProcessEnv(specgen, "NODE_OPTIONS", "--max-old-space-size="+strconv.FormatInt(heapMiB, 10))
+			}
+
+		case libconfig.RuntimeTuningDotnet:
+			if cpus > 0 {
+				injectProcessEnv(specgen, "DOTNET_PROCESSOR_COUNT", strconv.Itoa(cpus))
+			}
+		}
+	}
+
+	return nil
+}
+
+// No-op.
+func (*RuntimeTuningHooks) PreStart(context.Context, *oci.Container, *sandbox.Sandbox) error {
+	return nil
+}
+
+// No-op.
+func (*RuntimeTuningHooks) PreStop(context.Context, *oci.Container, *sandbox.Sandbox) error {
+	return nil
+}
+
+// No-op.
+func (*RuntimeTuningHooks) PostStop(context.Context, *oci.Container, *sandbox.Sandbox) error {
+	return nil
+}
+
+// skippedRuntimeTunings returns the language runtimes skipped by the
+// skip-runtime-tuning annotation of the container or pod.
+func skippedRuntimeTunings(annotations fields.Set, containerName string) []string {
+	value, ok := getAnnotationValueForContainer(annotations, crioann.SkipRuntimeTuning, containerName)
+	if !ok {
+		return nil
+	}
+
+	if value == annotationTrue {
+		return libconfig.RuntimeTunings
+	}
+
+	skipped := []string{}
+	for runtime := range strings.SplitSeq(value, ",") {
+		skipped = append(skipped, strings.TrimSpace(runtime))
+	}
+
+	return skipped
+}
+
+// containerCPUCount returns the number of CPUs available to the container:
+// the size of its cpuset if pinned, otherwise its CPU limit or request
+// rounded up. It returns 0 if the container has neither.
+func containerCPUCount(specgen *generate.Generator) int {
+	lspec := specgen.Config.Linux
+	if lspec == nil || lspec.Resources == nil || lspec.Resources.CPU == nil {
+		return 0
+	}
+
+	cpu := lspec.Resources.CPU
+
+	if cpu.Cpus != "" {
+		if cpus, err := cpuset.Parse(cpu.Cpus); err == nil && cpus.Size() > 0 {
+			return cpus.Size()
+		}
+	}
+
+	if cpu.Quota != nil && *cpu.Quota > 0 && cpu.Period != nil && *cpu.Period > 0 {
+		return int((uint64(*cpu.Quota) + *cpu.Period - 1) / *cpu.Period)
+	}
+
+	// Best-effort containers get the minimum of 2 shares.
+	if cpu.Shares != nil && *cpu.Shares > 2 {
+		return int((*cpu.Shares + 1023) / 1024)
+	}
+
+	return 0
+}
+
+// injectProcessEnv sets the environment variable unless it is already set in
+// the OCI spec's process env, which includes the image, pod spec and
+// default_env values.
+func injectProcessEnv(specgen *generate.Generator, key, value string) {
+	for _, env := range specgen.Config.Process.Env {
+		if strings.HasPrefix(env, key+"=") {
+			return
+		}
+	}
+
+	specgen.AddProcessEnv(key, value)
+}
@@ -0,0 +1,122 @@
+package runtimehandlerhooks
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/runtime-tools/generate"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/oci"
+	crioann "github.com/cri-o/cri-o/pkg/annotations/v2"
+	"github.com/cri-o/cri-o/pkg/config"
+)
+
+var _ = Describe("Runtime tuning", func() {
+	DescribeTable("containerCPUCount",
+		func(cpus string, quota int64, shares uint64, expected int) {
+			g, err := generate.New("linux")
+			Expect(err).NotTo(HaveOccurred())
+
+			g.SetLinuxResourcesCPUCpus(cpus)
+			g.SetLinuxResourcesCPUPeriod(100000)
+			g.SetLinuxResourcesCPUQuota(quota)
+			g.SetLinuxResourcesCPUShares(shares)
+
+			Expect(containerCPUCount(&g)).To(Equal(expected))
+		},
+		Entry("uses the cpuset", "0-3", int64(100000), uint64(1024), 4),
+		Entry("rounds up the CPU limit", "", int64(150000), uint64(1536), 2),
+		Entry("rounds up the CPU request", "", int64(0), uint64(512), 1),
+		Entry("ignores best-effort shares", "", int64(0), uint64(2), 0),
+	)
+
+	DescribeTable("skippedRuntimeTunings",
+		func(annotations map[string]string, expected []string) {
+			Expect(skippedRuntimeTunings(annotations, "ctr")).To(Equal(expected))
+		},
+		Entry("skips nothing without annotation", map[string]string{}, nil),
+		Entry("skips all runtimes",
+			map[string]string{crioann.SkipRuntimeTuning: "true"}, config.RuntimeTunings),
+		Entry("skips the listed runtimes",
+			map[string]string{crioann.SkipRuntimeTuning: "jvm, node"}, []string{"jvm", "node"}),
+		Entry("prefers the container annotation",
+			map[string]string{
+				crioann.SkipRuntimeTuning:          "true",
+				crioann.SkipRuntimeTuning + "/ctr": "dotnet",
+			}, []string{"dotnet"}),
+	)
+
+	DescribeTable("PreCreate",
+		func(annotations map[string]string, specEnvs, expected []string) {
+			sbox := sandbox.NewBuilder()
+			sbox.SetCreatedAt(time.Now())
+			sbox.SetID("sandboxID")
+			sbox.SetName("sandboxName")
+			sbox.SetLogDir("test")
+			sbox.SetShmPath("test")
+			sbox.SetNamespace("")
+			sbox.SetKubeName("")
+			sbox.SetMountLabel("test")
+			sbox.SetProcessLabel("test")
+			sbox.SetCgroupParent("kubepods-burstable.slice")
+			sbox.SetRuntimeHandler("")
+			sbox.SetResolvPath("")
+			sbox.SetHostname("")
+			sbox.SetPortMappings([]*hostport.PortMapping{})
+			sbox.SetHostNetwork(false)
+			sbox.SetUsernsMode("")
+			sbox.SetPodLinuxOverhead(nil)
+			sbox.SetPodLinuxResources(nil)
+			sbox.SetPrivileged(false)
+			Expect(sbox.SetCRISandbox(sbox.ID(), map[string]string{}, annotations, &types.PodSandboxMetadata{})).To(Succeed())
+			sb, err := sbox.GetSandbox()
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := oci.NewContainer("containerID", "", "", "",
+				make(map[string]string), make(map[string]string),
+				make(map[string]string), "pauseImage", nil, nil, "",
+				&types.ContainerMetadata{Name: "ctr"}, "sandboxID", false, false,
+				false, "", "", time.Now(), "")
+			Expect(err).NotTo(HaveOccurred())
+
+			g, err := generate.New("linux")
+			Expect(err).NotTo(HaveOccurred())
+			g.Config.Process.Env = specEnvs
+			g.SetLinuxResourcesCPUPeriod(100000)
+			g.SetLinuxResourcesCPUQuota(200000)
+			g.SetLinuxResourcesMemoryLimit(1 << 30)
+
+			hooks := &RuntimeTuningHooks{runtimes: config.RuntimeTunings}
+			Expect(hooks.PreCreate(context.Background(), &g, sb, c)).To(Succeed())
+
+			Expect(g.Config.Process.Env).To(Equal(expected))
+		},
+		Entry("injects the variables of all runtimes",
+			map[string]string{}, []string{},
+			[]string{
+				"JAVA_TOOL_OPTIONS=-XX:ActiveProcessorCount=2 -XX:MaxRAMPercentage=75.0",
+				"UV_THREADPOOL_SIZE=4",
+				"NODE_OPTIONS=--max-old-space-size=767",
+				"DOTNET_PROCESSOR_COUNT=2",
+			}),
+		Entry("keeps variables set in the spec",
+			map[string]string{}, []string{"JAVA_TOOL_OPTIONS=-Xmx1g", "DOTNET_PROCESSOR_COUNT=8"},
+			[]string{
+				"JAVA_TOOL_OPTIONS=-Xmx1g",
+				"DOTNET_PROCESSOR_COUNT=8",
+				"UV_THREADPOOL_SIZE=4",
+				"NODE_OPTIONS=--max-old-space-size=767",
+			}),
+		Entry("skips the runtimes of the annotation",
+			map[string]string{crioann.SkipRuntimeTuning: "jvm,node"}, []string{},
+			[]string{"DOTNET_PROCESSOR_COUNT=2"}),
+		Entry("skips all runtimes",
+			map[string]string{crioann.SkipRuntimeTuning + "/ctr": "true"}, []string{},
+			[]string{}),
+	)
+})
@@ -67,6 +67,7 @@ type ImageResult struct {
 	Labels              map[string]string
 	OCIConfig           *specs.Image
 	Annotations         map[string]string
+	LayerMediaTypes     []string
 	Pinned              bool // pinned image to prevent it from garbage collection
 	MountPoint          string
 }
@@ -395,6 +396,7 @@ func (svc *imageService) buildImageResult(image *storage.Image, cacheItem imageC
 		Labels:              cacheItem.info.Labels,
 		OCIConfig:           cacheItem.config,
 		Annotations:         cacheItem.annotations,
+		LayerMediaTypes:     layerMediaTypes(cacheItem.info),
 		Pinned:              imagePinned,
 		MountPoint:          mountPoint,
 	}, nil
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"slices"
+
+	"go.podman.io/image/v5/types"
+)
+
+// wasmOperatingSystems are the operating systems of WebAssembly images.
+var wasmOperatingSystems = []string{"wasi", "wasip1", "wasip2"}
+
+// wasmArchitectures are the architectures of WebAssembly images.
+var wasmArchitectures = []string{"wasm", "wasm32"}
+
+// wasmLayerMediaTypes are the media types of the layers of WebAssembly
+// artifacts, which contain the module itself instead of a file system.
+var wasmLayerMediaTypes = []string{
+	"application/wasm",
+	"application/vnd.wasm.content.layer.v1+wasm",
+	"application/vnd.bytecodealliance.wasm.component.layer.v0+wasm",
+}
+
+// WasmVariantAnnotation is the annotation of WebAssembly images which tells
+// wasm-capable runtimes how to execute them.
+const WasmVariantAnnotation = "module.wasm.image/variant"
+
+// IsWasm returns whether the image contains a WebAssembly module, which is
+// detected from its platform, its layer media types or its annotations.
+func (r *ImageResult) IsWasm() bool {
+	if r.OCIConfig != nil &&
+		slices.Contains(wasmOperatingSystems, r.OCIConfig.OS) &&
+		slices.Contains(wasmArchitectures, r.OCIConfig.Architecture) {
+		return true
+	}
+
+	for _, mediaType := range r.LayerMediaTypes {
+		if slices.Contains(wasmLayerMediaTypes, mediaType) {
+			return true
+		}
+	}
+
+	_, ok := r.Annotations[WasmVariantAnnotation]
+
+	return ok
+}
+
+// layerMediaTypes returns the media types of the image layers, if known.
+func layerMediaTypes(info *types.ImageInspectInfo) []string {
+	if info == nil {
+		return nil
+	}
+
+	mediaTypes := make([]string, 0, len(info.LayersData))
+
+	for _, layer := range info.LayersData {
+		if layer.MIMEType != "" {
+			mediaTypes = append(mediaTypes, layer.MIMEType)
+		}
+	}
+
+	return mediaTypes
+}
@@ -0,0 +1,44 @@
+package storage_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/cri-o/cri-o/internal/storage"
+)
+
+// The actual test suite.
+var _ = t.Describe("Wasm", func() {
+	DescribeTable("should detect WebAssembly images", func(result *storage.ImageResult, expected bool) {
+		// Given
+		// When
+		isWasm := result.IsWasm()
+
+		// Then
+		Expect(isWasm).To(Equal(expected))
+	},
+		Entry("linux image", &storage.ImageResult{
+			OCIConfig:       &specs.Image{Platform: specs.Platform{OS: "linux", Architecture: "amd64"}},
+			LayerMediaTypes: []string{specs.MediaTypeImageLayerGzip},
+		}, false),
+		Entry("image without config", &storage.ImageResult{}, false),
+		Entry("wasi platform", &storage.ImageResult{
+			OCIConfig: &specs.Image{Platform: specs.Platform{OS: "wasi", Architecture: "wasm32"}},
+		}, true),
+		Entry("wasip1 platform", &storage.ImageResult{
+			OCIConfig: &specs.Image{Platform: specs.Platform{OS: "wasip1", Architecture: "wasm"}},
+		}, true),
+		Entry("wasi operating system on another architecture", &storage.ImageResult{
+			OCIConfig: &specs.Image{Platform: specs.Platform{OS: "wasi", Architecture: "amd64"}},
+		}, false),
+		Entry("wasm layer media type", &storage.ImageResult{
+			OCIConfig:       &specs.Image{Platform: specs.Platform{OS: "linux", Architecture: "amd64"}},
+			LayerMediaTypes: []string{"application/vnd.wasm.content.layer.v1+wasm"},
+		}, true),
+		Entry("wasm variant annotation", &storage.ImageResult{
+			OCIConfig:   &specs.Image{Platform: specs.Platform{OS: "linux", Architecture: "amd64"}},
+			Annotations: map[string]string{storage.WasmVariantAnnotation: "compat"},
+		}, true),
+	)
+})
@@ -0,0 +1,451 @@
+// Package userns implements an allocator for the host ID ranges of pod user
+// namespaces with a persistent allocation table.
+package userns
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/google/renameio"
+	"github.com/sirupsen/logrus"
+	"go.podman.io/storage/pkg/idtools"
+
+	"github.com/cri-o/cri-o/pkg/types"
+)
+
+// ErrPoolExhausted is returned if a pool has no free range of the requested
+// size left.
+var ErrPoolExhausted = errors.New("user namespace ID pool exhausted")
+
+// Pool is a range of host IDs user namespace ranges are allocated from. The
+// same host IDs are used for UIDs and GIDs.
+type Pool struct {
+	// Name is the unique name of the pool.
+	Name string
+
+	// HostID is the first host ID of the pool.
+	HostID uint32
+
+	// Size is the number of host IDs in the pool.
+	Size uint32
+
+	// RuntimeHandlers restricts the pool to pods using one of the runtime
+	// handlers. The pool applies to all runtime handlers if empty.
+	RuntimeHandlers []string
+
+	// Namespaces restricts the pool to pods in one of the Kubernetes
+	// namespaces. The pool applies to all namespaces if empty.
+	Namespaces []string
+}
+
+// end returns the first host ID after the pool.
+func (p *Pool) end() uint64 {
+	return uint64(p.HostID) + uint64(p.Size)
+}
+
+// matches returns true if the pool applies to the runtime handler and
+// namespace.
+func (p *Pool) matches(runtimeHandler, namespace string) bool {
+	return (len(p.RuntimeHandlers) == 0 || slices.Contains(p.RuntimeHandlers, runtimeHandler)) &&
+		(len(p.Namespaces) == 0 || slices.Contains(p.Namespaces, namespace))
+}
+
+// specificity returns the number of selectors restricting the pool.
+func (p *Pool) specificity() int {
+	res := 0
+	if len(p.RuntimeHandlers) > 0 {
+		res++
+	}
+
+	if len(p.Namespaces) > 0 {
+		res++
+	}
+
+	return res
+}
+
+// Sandbox is a pod sandbox with the ID mappings of its user namespace.
+type Sandbox struct {
+	ID        string
+	Namespace string
+	Name      string
+	Mappings  *idtools.IDMappings
+}
+
+// state is the persisted allocation table.
+type state struct {
+	Allocations []*types.UsernsAllocation `json:"allocations"`
+}
+
+// Allocator allocates host ID ranges for pod user namespaces from the
+// configured pools and keeps track of the ranges used by all pods.
+type Allocator struct {
+	mutex       sync.Mutex
+	path        string
+	pools       []Pool
+	allocations map[string]*types.UsernsAllocation
+}
+
+// New creates a new allocator persisting its table at path. The pools must
+// not overlap.
+func New(path string, pools []Pool) (*Allocator, error) {
+	sorted := slices.Clone(pools)
+	for i := range sorted {
+		if sorted[i].Size == 0 {
+			return nil, fmt.Errorf("user namespace ID pool %q has no IDs", sorted[i].Name)
+		}
+
+		for j := range i {
+			if overlaps(uint64(sorted[i].HostID), sorted[i].end(), uint64(sorted[j].HostID), sorted[j].end()) {
+				return nil, fmt.Errorf("user namespace ID pools %q and %q overlap", sorted[i].Name, sorted[j].Name)
+			}
+		}
+	}
+
+	// More specific pools take precedence.
+	slices.SortFunc(sorted, func(a, b Pool) int {
+		return cmp.Or(cmp.Compare(b.specificity(), a.specificity()), cmp.Compare(a.Name, b.Name))
+	})
+
+	a := &Allocator{
+		path:        path,
+		pools:       sorted,
+		allocations: make(map[string]*types.UsernsAllocation),
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read user namespace allocations: %w", err)
+	}
+
+	var s state
+	if err := json.Unmarshal(content, &s); err != nil {
+		// The table gets rebuilt from the existing pods on restore.
+		logrus.Warnf("Ignoring corrupted user namespace allocations %s: %v", path, err)
+
+		return a, nil
+	}
+
+	for _, allocation := range s.Allocations {
+		a.allocations[allocation.SandboxID] = allocation
+	}
+
+	return a, nil
+}
+
+// PoolFor returns the name of the pool to be used for a pod with the runtime
+// handler in the namespace. It returns an empty string if no pool applies.
+func (a *Allocator) PoolFor(runtimeHandler, namespace string) string {
+	for i := range a.pools {
+		if a.pools[i].matches(runtimeHandler, namespace) {
+			return a.pools[i].Name
+		}
+	}
+
+	return ""
+}
+
+// CheckReserved returns an error if a pool overlaps with the reserved host
+// IDs, like the subordinate IDs containers/storage picks the user namespaces
+// of pods in "auto" mode without pool from. Such pods would otherwise get
+// ranges which are still free in a pool.
+func (a *Allocator) CheckReserved(owner string, reserved []idtools.IDMap) error {
+	for i := range a.pools {
+		pool := &a.pools[i]
+		for _, r := range reserved {
+			if overlaps(uint64(pool.HostID), pool.end(), uint64(r.HostID), uint64(r.HostID)+uint64(r.Size)) {
+				return fmt.Errorf("user namespace ID pool %q overlaps with the IDs %d-%d reserved for %s",
+					pool.Name, r.HostID, r.HostID+r.Size-1, owner)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Allocate allocates a range of size host IDs from the pool for the pod
+// sandbox. The range does not overlap with the UIDs and GIDs used by any other
+// known pod sandbox.
+func (a *Allocator) Allocate(sandboxID, namespace, name, poolName string, size uint32) (types.UsernsIDRange, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	idx := slices.IndexFunc(a.pools, func(p Pool) bool { return p.Name == poolName })
+	if idx < 0 {
+		return types.UsernsIDRange{}, fmt.Errorf("unknown user namespace ID pool %q", poolName)
+	}
+
+	pool := &a.pools[idx]
+
+	var used []types.UsernsIDRange
+
+	for id, allocation := range a.allocations {
+		if id == sandboxID {
+			continue
+		}
+
+		used = append(used, allocation.Uids...)
+		used = append(used, allocation.Gids...)
+	}
+
+	slices.SortFunc(used, func(x, y types.UsernsIDRange) int {
+		return cmp.Compare(x.HostID, y.HostID)
+	})
+
+	// First fit: advance the candidate start behind every used range
+	// intersecting it.
+	start := uint64(pool.HostID)
+
+	for _, r := range used {
+		if start+uint64(size) <= uint64(r.HostID) {
+			break
+		}
+
+		start = max(start, uint64(r.HostID)+uint64(r.Size))
+	}
+
+	if start+uint64(size) > pool.end() {
+		return types.UsernsIDRange{}, fmt.Errorf("%w: no range of %d IDs left in pool %q", ErrPoolExhausted, size, poolName)
+	}
+
+	res := types.UsernsIDRange{HostID: uint32(start), Size: size}
+
+	a.allocations[sandboxID] = &types.UsernsAllocation{
+		SandboxID: sandboxID,
+		Namespace: namespace,
+		Name:      name,
+		Pool:      poolName,
+		Uids:      []types.UsernsIDRange{res},
+		Gids:      []types.UsernsIDRange{res},
+	}
+
+	if err := a.save(); err != nil {
+		delete(a.allocations, sandboxID)
+
+		return types.UsernsIDRange{}, err
+	}
+
+	return res, nil
+}
+
+// Record records the ID mappings of a pod sandbox not allocated from a pool,
+// so that they are avoided by later allocations.
+func (a *Allocator) Record(sb *Sandbox) error {
+	if sb.Mappings == nil || sb.Mappings.Empty() {
+		return nil
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.allocations[sb.ID] = externalAllocation(sb)
+
+	return a.save()
+}
+
+// Release removes the allocation of the pod sandbox. Releasing an unknown pod
+// sandbox is a no-op.
+func (a *Allocator) Release(sandboxID string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if _, ok := a.allocations[sandboxID]; !ok {
+		return nil
+	}
+
+	delete(a.allocations, sandboxID)
+
+	return a.save()
+}
+
+// Restore reconciles the allocation table with the existing pod sandboxes.
+// Allocations of removed pod sandboxes are released and the ranges of pod
+// sandboxes unknown to the table are recorded. It returns the overlaps of
+// pool allocations with other pod sandboxes.
+func (a *Allocator) Restore(sandboxes []*Sandbox) ([]types.UsernsOverlap, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	allocations := make(map[string]*types.UsernsAllocation, len(sandboxes))
+
+	for _, sb := range sandboxes {
+		if sb.Mappings == nil || sb.Mappings.Empty() {
+			continue
+		}
+
+		actual := externalAllocation(sb)
+
+		// Keep pool allocations still covered by the mappings of the pod.
+		if known, ok := a.allocations[sb.ID]; ok && known.Pool != "" &&
+			covers(actual.Uids, known.Uids) && covers(actual.Gids, known.Gids) {
+			allocations[sb.ID] = known
+
+			continue
+		}
+
+		allocations[sb.ID] = actual
+	}
+
+	a.allocations = allocations
+
+	return a.overlaps(), a.save()
+}
+
+// Info returns the configured pools, the current allocations and their
+// overlaps.
+func (a *Allocator) Info() types.UsernsInfo {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	info := types.UsernsInfo{
+		Pools:       make([]types.UsernsPoolInfo, 0, len(a.pools)),
+		Allocations: make([]types.UsernsAllocation, 0, len(a.allocations)),
+		Overlaps:    a.overlaps(),
+	}
+
+	for i := range a.pools {
+		pool := &a.pools[i]
+		poolInfo := types.UsernsPoolInfo{
+			Name:            pool.Name,
+			HostID:          pool.HostID,
+			Size:            pool.Size,
+			RuntimeHandlers: pool.RuntimeHandlers,
+			Namespaces:      pool.Namespaces,
+		}
+
+		for _, allocation := range a.allocations {
+			if allocation.Pool == pool.Name {
+				for _, r := range allocation.Uids {
+					poolInfo.Allocated += uint64(r.Size)
+				}
+			}
+		}
+
+		info.Pools = append(info.Pools, poolInfo)
+	}
+
+	for _, allocation := range a.sortedAllocations() {
+		info.Allocations = append(info.Allocations, *allocation)
+	}
+
+	return info
+}
+
+// overlaps returns all pairs of pod sandboxes sharing host IDs, where at
+// least one of them uses a pool allocation. Overlaps between ranges not
+// allocated by CRI-O are intentional, for example with the default ID
+// mappings.
+func (a *Allocator) overlaps() []types.UsernsOverlap {
+	res := []types.UsernsOverlap{}
+	sorted := a.sortedAllocations()
+
+	for i, x := range sorted {
+		for _, y := range sorted[i+1:] {
+			if x.Pool == "" && y.Pool == "" {
+				continue
+			}
+
+			uids, gids := rangesOverlap(x.Uids, y.Uids), rangesOverlap(x.Gids, y.Gids)
+			if uids || gids {
+				res = append(res, types.UsernsOverlap{
+					SandboxIDs: []string{x.SandboxID, y.SandboxID},
+					Uids:       uids,
+					Gids:       gids,
+				})
+			}
+		}
+	}
+
+	return res
+}
+
+func (a *Allocator) sortedAllocations() []*types.UsernsAllocation {
+	res := make([]*types.UsernsAllocation, 0, len(a.allocations))
+	for _, allocation := range a.allocations {
+		res = append(res, allocation)
+	}
+
+	slices.SortFunc(res, func(x, y *types.UsernsAllocation) int {
+		return cmp.Compare(x.SandboxID, y.SandboxID)
+	})
+
+	return res
+}
+
+// save persists the allocation table. The caller has to hold the mutex.
+func (a *Allocator) save() error {
+	content, err := json.Marshal(&state{Allocations: a.sortedAllocations()})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(a.path), 0o700); err != nil {
+		return fmt.Errorf("create directory for user namespace allocations: %w", err)
+	}
+
+	if err := renameio.WriteFile(a.path, content, 0o600); err != nil {
+		return fmt.Errorf("write user namespace allocations: %w", err)
+	}
+
+	return nil
+}
+
+func externalAllocation(sb *Sandbox) *types.UsernsAllocation {
+	return &types.UsernsAllocation{
+		SandboxID: sb.ID,
+		Namespace: sb.Namespace,
+		Name:      sb.Name,
+		Uids:      toRanges(sb.Mappings.UIDs()),
+		Gids:      toRanges(sb.Mappings.GIDs()),
+	}
+}
+
+func toRanges(mappings []idtools.IDMap) []types.UsernsIDRange {
+	res := make([]types.UsernsIDRange, 0, len(mappings))
+	for _, m := range mappings {
+		res = append(res, types.UsernsIDRange{HostID: uint32(m.HostID), Size: uint32(m.Size)})
+	}
+
+	return res
+}
+
+// covers returns true if every range of want is contained in a range of have.
+func covers(have, want []types.UsernsIDRange) bool {
+	for _, w := range want {
+		if !slices.ContainsFunc(have, func(h types.UsernsIDRange) bool {
+			return h.HostID <= w.HostID && uint64(w.HostID)+uint64(w.Size) <= uint64(h.HostID)+uint64(h.Size)
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func rangesOverlap(x, y []types.UsernsIDRange) bool {
+	for _, r := range x {
+		for _, o := range y {
+			if overlaps(uint64(r.HostID), uint64(r.HostID)+uint64(r.Size), uint64(o.HostID), uint64(o.HostID)+uint64(o.Size)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// overlaps returns true if the half-open intervals [aStart, aEnd) and
+// [bStart, bEnd) intersect.
+func overlaps(aStart, aEnd, bStart, bEnd uint64) bool {
+	return aStart < bEnd && bStart < aEnd
+}
@@ -0,0 +1,284 @@
+package userns_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.podman.io/storage/pkg/idtools"
+
+	"github.com/cri-o/cri-o/internal/userns"
+	"github.com/cri-o/cri-o/pkg/types"
+)
+
+func sandbox(id string, hostID, size int) *userns.Sandbox {
+	mappings := []idtools.IDMap{{ContainerID: 0, HostID: hostID, Size: size}}
+
+	return &userns.Sandbox{
+		ID:        id,
+		Namespace: "namespace",
+		Name:      id,
+		Mappings:  idtools.NewIDMappingsFromMaps(mappings, mappings),
+	}
+}
+
+// The actual test suite.
+var _ = t.Describe("Allocator", func() {
+	var (
+		path  string
+		pools []userns.Pool
+		sut   *userns.Allocator
+	)
+
+	newAllocator := func() {
+		var err error
+
+		sut, err = userns.New(path, pools)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		path = filepath.Join(t.MustTempDir("userns"), "allocations.json")
+		pools = []userns.Pool{{Name: "default", HostID: 100000, Size: 1000}}
+	})
+
+	t.Describe("New", func() {
+		It("should fail with overlapping pools", func() {
+			// Given
+			pools = append(pools, userns.Pool{Name: "other", HostID: 100500, Size: 1000})
+
+			// When
+			res, err := userns.New(path, pools)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+
+		It("should fail with empty pool", func() {
+			// Given
+			pools = []userns.Pool{{Name: "empty", HostID: 100000}}
+
+			// When
+			res, err := userns.New(path, pools)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		})
+
+		It("should fail with pools overlapping reserved IDs", func() {
+			// Given
+			newAllocator()
+
+			// When
+			err := sut.CheckReserved("auto", []idtools.IDMap{{HostID: 100900, Size: 65536}})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(sut.CheckReserved("auto", []idtools.IDMap{{HostID: 101000, Size: 65536}})).To(Succeed())
+		})
+
+		It("should ignore corrupted allocations", func() {
+			// Given
+			Expect(os.WriteFile(path, []byte("{"), 0o600)).To(Succeed())
+
+			// When
+			newAllocator()
+
+			// Then
+			Expect(sut.Info().Allocations).To(BeEmpty())
+		})
+	})
+
+	t.Describe("PoolFor", func() {
+		BeforeEach(func() {
+			pools = append(pools,
+				userns.Pool{Name: "handler", HostID: 200000, Size: 1000, RuntimeHandlers: []string{"kata"}},
+				userns.Pool{Name: "both", HostID: 300000, Size: 1000, RuntimeHandlers: []string{"kata"}, Namespaces: []string{"untrusted"}},
+			)
+			newAllocator()
+		})
+
+		It("should prefer the most specific pool", func() {
+			// Given
+			// When
+			res := sut.PoolFor("kata", "untrusted")
+
+			// Then
+			Expect(res).To(Equal("both"))
+		})
+
+		It("should match the runtime handler", func() {
+			// Given
+			// When
+			res := sut.PoolFor("kata", "default")
+
+			// Then
+			Expect(res).To(Equal("handler"))
+		})
+
+		It("should fall back to unrestricted pools", func() {
+			// Given
+			// When
+			res := sut.PoolFor("runc", "untrusted")
+
+			// Then
+			Expect(res).To(Equal("default"))
+		})
+
+		It("should return nothing without matching pool", func() {
+			// Given
+			pools = pools[1:]
+			newAllocator()
+
+			// When
+			res := sut.PoolFor("runc", "default")
+
+			// Then
+			Expect(res).To(BeEmpty())
+		})
+	})
+
+	t.Describe("Allocate", func() {
+		BeforeEach(func() {
+			newAllocator()
+		})
+
+		It("should allocate consecutive ranges", func() {
+			// Given
+			// When
+			first, err := sut.Allocate("first", "namespace", "first", "default", 100)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := sut.Allocate("second", "namespace", "second", "default", 100)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Then
+			Expect(first).To(Equal(types.UsernsIDRange{HostID: 100000, Size: 100}))
+			Expect(second).To(Equal(types.UsernsIDRange{HostID: 100100, Size: 100}))
+		})
+
+		It("should reuse released ranges", func() {
+			// Given
+			_, err := sut.Allocate("first", "namespace", "first", "default", 100)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = sut.Allocate("second", "namespace", "second", "default", 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sut.Release("first")).To(Succeed())
+
+			// When
+			res, err := sut.Allocate("third", "namespace", "third", "default", 100)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(types.UsernsIDRange{HostID: 100000, Size: 100}))
+		})
+
+		It("should avoid recorded ranges", func() {
+			// Given
+			Expect(sut.Record(sandbox("external", 100000, 500))).To(Succeed())
+
+			// When
+			res, err := sut.Allocate("first", "namespace", "first", "default", 100)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(types.UsernsIDRange{HostID: 100500, Size: 100}))
+		})
+
+		It("should fail if the pool is exhausted", func() {
+			// Given
+			_, err := sut.Allocate("first", "namespace", "first", "default", 600)
+			Expect(err).NotTo(HaveOccurred())
+
+			// When
+			_, err = sut.Allocate("second", "namespace", "second", "default", 600)
+
+			// Then
+			Expect(err).To(MatchError(userns.ErrPoolExhausted))
+		})
+
+		It("should fail with unknown pool", func() {
+			// Given
+			// When
+			_, err := sut.Allocate("first", "namespace", "first", "unknown", 100)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should persist the allocations", func() {
+			// Given
+			_, err := sut.Allocate("first", "namespace", "first", "default", 100)
+			Expect(err).NotTo(HaveOccurred())
+
+			// When
+			newAllocator()
+			res, err := sut.Allocate("second", "namespace", "second", "default", 100)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(types.UsernsIDRange{HostID: 100100, Size: 100}))
+			Expect(sut.Info().Pools).To(ConsistOf(HaveField("Allocated", BeEquivalentTo(200))))
+		})
+	})
+
+	t.Describe("Restore", func() {
+		BeforeEach(func() {
+			newAllocator()
+		})
+
+		It("should release the allocations of removed sandboxes", func() {
+			// Given
+			_, err := sut.Allocate("first", "namespace", "first", "default", 100)
+			Expect(err).NotTo(HaveOccurred())
+
+			// When
+			overlaps, err := sut.Restore(nil)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(overlaps).To(BeEmpty())
+			Expect(sut.Info().Allocations).To(BeEmpty())
+		})
+
+		It("should keep the allocations of existing sandboxes", func() {
+			// Given
+			_, err := sut.Allocate("first", "namespace", "first", "default", 100)
+			Expect(err).NotTo(HaveOccurred())
+
+			// When
+			_, err = sut.Restore([]*userns.Sandbox{sandbox("first", 100000, 100)})
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sut.Info().Allocations).To(ConsistOf(HaveField("Pool", "default")))
+		})
+
+		It("should report overlaps with pool allocations", func() {
+			// Given
+			_, err := sut.Allocate("first", "namespace", "first", "default", 100)
+			Expect(err).NotTo(HaveOccurred())
+
+			// When
+			overlaps, err := sut.Restore([]*userns.Sandbox{
+				sandbox("first", 100000, 100),
+				sandbox("second", 100050, 100),
+				sandbox("third", 100050, 100),
+			})
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(overlaps).To(Equal([]types.UsernsOverlap{{
+				SandboxIDs: []string{"first", "second"},
+				Uids:       true,
+				Gids:       true,
+			}, {
+				SandboxIDs: []string{"first", "third"},
+				Uids:       true,
+				Gids:       true,
+			}}))
+		})
+	})
+})
@@ -0,0 +1,30 @@
+package userns_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/cri-o/cri-o/test/framework"
+)
+
+// TestUserns runs the created specs.
+func TestUserns(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunFrameworkSpecs(t, "Userns")
+}
+
+var t *TestFramework
+
+var _ = BeforeSuite(func() {
+	t = NewTestFramework(NilFunc, NilFunc)
+	t.Setup()
+
+	logrus.SetLevel(logrus.PanicLevel)
+})
+
+var _ = AfterSuite(func() {
+	t.Teardown()
+})
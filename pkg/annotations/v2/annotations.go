@@ -9,6 +9,11 @@ const (
 
 	// V2 annotations (recommended format: *.crio.io).
 
+	// AutoCheckpoint is a comma separated list of the policies ("drain",
+	// "memory-pressure") on which the containers of a pod are checkpointed
+	// automatically.
+	AutoCheckpoint = "auto-checkpoint.crio.io"
+
 	// Cgroup2MountHierarchyRW specifies mounting v2 cgroups as an rw filesystem.
 	Cgroup2MountHierarchyRW = "cgroup2-mount-hierarchy-rw.crio.io"
 
@@ -31,6 +36,10 @@ const (
 	// PodLinuxResources indicates the sum of container resources for this pod.
 	PodLinuxResources = "pod-linux-resources.crio.io"
 
+	// RestorePod is the path of a pod checkpoint archive to restore the
+	// containers of a new pod sandbox from.
+	RestorePod = "restore-pod.crio.io"
+
 	// SeccompNotifierAction indicates a container is allowed to use the seccomp notifier feature.
 	SeccompNotifierAction = "seccomp-notifier-action.crio.io"
 
@@ -46,6 +55,13 @@ const (
 	// even when min_injected_gomaxprocs is enabled globally.
 	SkipGoMaxProcs = "skip-gomaxprocs.crio.io"
 
+	// SkipRuntimeTuning is used to skip the runtime_tuning environment variable
+	// injection for a specific pod. The value is either "true" to skip all
+	// language runtimes, or a comma separated list of language runtimes to skip.
+	// A container name can optionally be appended to target a specific container.
+	// Example: skip-runtime-tuning.crio.io/containerA.
+	SkipRuntimeTuning = "skip-runtime-tuning.crio.io"
+
 	// ShmSize is the annotation used to set custom shm size.
 	ShmSize = "shm-size.crio.io"
 
@@ -98,6 +114,22 @@ const (
 	// Example: irq-load-balancing.crio.io/containerA.
 	IRQLoadBalancing = "irq-load-balancing.crio.io"
 
+	// IOLimits sets per-device cgroup v2 io.max and io.weight limits for the container.
+	// The value is a JSON map from device to limits, in the same format as the workload iolimits,
+	// for example: {"graphroot": {"rbps": 10485760, "wiops": 1000}}.
+	// A device is either "graphroot", an absolute block device path or a "major:minor" pair.
+	// A container name can optionally be appended to target a specific container. The container specific annotation
+	// takes precedence if both are present.
+	// Example: io-limits.crio.io/containerA.
+	IOLimits = "io-limits.crio.io"
+
+	// NUMAMemoryPlacement restricts the memory nodes of the container to the NUMA nodes of its CPUs ("cpus")
+	// or of its CPUs and CDI devices ("cpus-and-devices").
+	// A container name can optionally be appended to target a specific container. The container specific annotation
+	// takes precedence if both are present.
+	// Example: numa-memory-placement.crio.io/containerA.
+	NUMAMemoryPlacement = "numa-memory-placement.crio.io"
+
 	// OCISeccompBPFHook is the annotation used by the OCI seccomp BPF hook for tracing container syscalls.
 	OCISeccompBPFHook = "io.containers.trace-syscall"
 
@@ -276,6 +308,7 @@ func findV1KeyForContainerSpecific(newKey string) string {
 
 // AllAnnotations lists all V2 annotations.
 var AllAnnotations = []string{
+	AutoCheckpoint,
 	Cgroup2MountHierarchyRW,
 	CPUCStates,
 	CPUFreqGovernor,
@@ -284,12 +317,15 @@ var AllAnnotations = []string{
 	CPUShared,
 	Devices,
 	DisableFIPS,
+	IOLimits,
 	IRQLoadBalancing,
 	LinkLogs,
+	NUMAMemoryPlacement,
 	OCISeccompBPFHook,
 	PlatformRuntimePath,
 	PodLinuxOverhead,
 	PodLinuxResources,
+	RestorePod,
 	SeccompNotifierAction,
 	SeccompProfile,
 	ShmSize,
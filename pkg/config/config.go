@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"net"
 	"os"
 	"os/exec"
@@ -28,6 +29,7 @@ import (
 	"go.podman.io/image/v5/pkg/sysregistriesv2"
 	"go.podman.io/image/v5/types"
 	"go.podman.io/storage"
+	"go.podman.io/storage/pkg/archive"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/utils/cpuset"
 	"tags.cncf.io/container-device-interface/pkg/cdi"
@@ -68,27 +70,55 @@ const (
 	OCIBufSize                    = 8192
 	RuntimeTypeVM                 = "vm"
 	RuntimeTypePod                = "pod"
+	RuntimeTypeShimV2             = "shimv2"
+	RuntimeTypeWasm               = "wasm"
 	defaultCtrStopTimeout         = 30 // seconds
 	defaultNamespacesDir          = "/var/run"
 	RuntimeTypeVMBinaryPattern    = "^containerd-shim-[a-zA-Z0-9\\-\\+]+$"
 	tasksetBinary                 = "taskset"
 	MonitorExecCgroupDefault      = ""
 	MonitorExecCgroupContainer    = "container"
+	// defaultCNISetupTimeout is the default timeout for CNI ADD operations.
+	defaultCNISetupTimeout = 5 * time.Minute
+	// defaultCNITeardownTimeout is the default timeout for a single CNI DEL attempt,
+	// half of a StopPod request timeout limit.
+	defaultCNITeardownTimeout = time.Minute
+	// defaultCNIGCTimeout is the default timeout for CNI GC operations.
+	defaultCNIGCTimeout = 30 * time.Second
+	// maxCNITeardownRetries is the upper bound of retries for failed CNI DEL operations.
+	maxCNITeardownRetries = 5
+
+	// maxCheckpointPreDumpIterations is the upper bound of CRIU pre-dump
+	// iterations before checkpointing a container.
+	maxCheckpointPreDumpIterations = 10
+
+	// defaultAutoCheckpointMemoryPressure is the default memory pressure
+	// threshold in percent for automatic checkpoints.
+	defaultAutoCheckpointMemoryPressure = 50
+
+	// defaultDNSCacheSize is the default number of answers kept by the DNS cache.
+	defaultDNSCacheSize = 10000
+	// defaultDNSCacheMaxTTL is the default upper bound for caching DNS answers.
+	defaultDNSCacheMaxTTL = 5 * time.Minute
+	// defaultAdaptiveCollectionCPUThreshold is the default CPU usage in
+	// millicores above which containers are considered busy.
+	defaultAdaptiveCollectionCPUThreshold = 100
 )
 
 // When updating metrics, remember to update the document as well.
 const (
-	AllMetrics      = "all"
-	CPUMetrics      = "cpu"
-	DiskMetrics     = "disk"
-	DiskIOMetrics   = "diskIO"
-	HugetlbMetrics  = "hugetlb"
-	MemoryMetrics   = "memory"
-	NetworkMetrics  = "network"
-	OOMMetrics      = "oom"
-	ProcessMetrics  = "process"
-	SpecMetrics     = "spec"
-	PressureMetrics = "pressure"
+	AllMetrics           = "all"
+	CPUMetrics           = "cpu"
+	DiskMetrics          = "disk"
+	DiskIOMetrics        = "diskIO"
+	HugetlbMetrics       = "hugetlb"
+	MemoryMetrics        = "memory"
+	NetworkMetrics       = "network"
+	OOMMetrics           = "oom"
+	ProcessMetrics       = "process"
+	SpecMetrics          = "spec"
+	PressureMetrics      = "pressure"
+	PodAccountingMetrics = "podAccounting"
 )
 
 // AvailableMetrics is a list of all available metrics that can be included in stats.
@@ -104,6 +134,7 @@ var AvailableMetrics = []string{
 	ProcessMetrics,
 	SpecMetrics,
 	PressureMetrics,
+	PodAccountingMetrics,
 }
 
 // Config represents the entire set of configuration values that can be set for
@@ -307,6 +338,10 @@ type RuntimeHandler struct {
 	// This is populated dynamically and not read from config.
 	features runtimeHandlerFeatures
 
+	// Output of the "features" subcommand of the CanaryRuntimePath.
+	// This is populated dynamically and not read from config.
+	canaryFeatures runtimeHandlerFeatures
+
 	// Inheritance request
 	// Fill in the Runtime information (paths and type) from the default runtime
 	InheritDefaultRuntime bool `toml:"inherit_default_runtime,omitempty"`
@@ -320,6 +355,7 @@ type RuntimeHandler struct {
 	//
 	// conmon-rs (runtime_type = "pod") supports this configuration for exec
 	// and attach. Forwarding ports will be supported in future releases.
+	// For shimv2 runtimes, the URLs of the CRI-O streaming server are used.
 	StreamWebsockets bool `toml:"stream_websockets,omitempty"`
 
 	// ExecCPUAffinity specifies which CPU is used when exec-ing the container.
@@ -341,6 +377,41 @@ type RuntimeHandler struct {
 	// If not set, defaults to 240 seconds.
 	ContainerCreateTimeout int64 `toml:"container_create_timeout,omitempty"`
 
+	// CanaryRuntimePath is the path of an alternative runtime executable used
+	// for CanaryPercentage of the new pod sandboxes, for example to roll out a
+	// new runtime version on a node.
+	CanaryRuntimePath string `toml:"canary_runtime_path,omitempty"`
+
+	// CanaryPercentage is the percentage (0-100) of new pod sandboxes using
+	// the CanaryRuntimePath.
+	CanaryPercentage int `toml:"canary_percentage,omitempty"`
+
+	// FallbackRuntimeHandler is the name of the runtime handler used to
+	// retry creating a pod sandbox if the runtime failed to create it.
+	FallbackRuntimeHandler string `toml:"fallback_runtime_handler,omitempty"`
+
+	// FallbackErrors are regular expressions matching the runtime errors
+	// which trigger the FallbackRuntimeHandler. Every runtime error does if
+	// none are specified.
+	FallbackErrors []string `toml:"fallback_errors,omitempty"`
+
+	// fallbackErrors are the compiled FallbackErrors.
+	// This is populated when validating the runtime handler.
+	fallbackErrors []*regexp.Regexp
+
+	// LogFormat is the format of the container logs, either "cri" or "json".
+	// Defaults to "cri", which is the only format supported by conmon.
+	LogFormat string `toml:"log_format,omitempty"`
+
+	// ContainerLogRotation configures the rotation of the container logs by
+	// CRI-O, taking precedence over the defaults of the namespaces.
+	ContainerLogRotation
+
+	// HandlerHooks is the ordered list of runtime handler hooks, either
+	// built-in or exec hooks, used for the containers of the handler instead
+	// of the hooks chosen by the pod annotations and configuration.
+	HandlerHooks []string `toml:"handler_hooks,omitempty"`
+
 	// seccompConfig is the seccomp configuration for the handler.
 	seccompConfig *seccomp.Config
 }
@@ -382,7 +453,7 @@ func (l ContainerCheckpointRestoreLevel) Validate() error {
 		return nil
 	default:
 		return fmt.Errorf(
-			"invalid container_level_enabled %q: must be one of %q, %q or %q",
+			"invalid checkpoint/restore level %q: must be one of %q, %q or %q",
 			l,
 			ContainerCheckpointRestoreLevelNone,
 			ContainerCheckpointRestoreLevelCheckpointOnly,
@@ -391,6 +462,48 @@ func (l ContainerCheckpointRestoreLevel) Validate() error {
 	}
 }
 
+// CheckpointCompression defines the compression of checkpoint archives and
+// checkpoint images.
+type CheckpointCompression string
+
+const (
+	// CheckpointCompressionNone writes uncompressed checkpoint archives.
+	CheckpointCompressionNone CheckpointCompression = "none"
+	// CheckpointCompressionGzip compresses checkpoint archives using gzip.
+	CheckpointCompressionGzip CheckpointCompression = "gzip"
+	// CheckpointCompressionZstd compresses checkpoint archives using zstd.
+	CheckpointCompressionZstd CheckpointCompression = "zstd"
+)
+
+// Validate returns an error if the checkpoint compression is not one of the
+// recognized values.
+func (c CheckpointCompression) Validate() error {
+	switch c {
+	case CheckpointCompressionNone, CheckpointCompressionGzip, CheckpointCompressionZstd:
+		return nil
+	default:
+		return fmt.Errorf(
+			"invalid checkpoint compression %q: must be one of %q, %q or %q",
+			c,
+			CheckpointCompressionNone,
+			CheckpointCompressionGzip,
+			CheckpointCompressionZstd,
+		)
+	}
+}
+
+// Archive returns the archive compression of the checkpoint compression.
+func (c CheckpointCompression) Archive() archive.Compression {
+	switch c {
+	case CheckpointCompressionGzip:
+		return archive.Gzip
+	case CheckpointCompressionZstd:
+		return archive.Zstd
+	default:
+		return archive.Uncompressed
+	}
+}
+
 // CheckpointRestoreConfig represents the "crio.checkpoint_restore" TOML config
 // table.
 type CheckpointRestoreConfig struct {
@@ -400,6 +513,45 @@ type CheckpointRestoreConfig struct {
 	// "checkpoint_only": only checkpointing containers is enabled.
 	// "checkpoint_restore": both checkpointing and restoring containers is enabled.
 	ContainerLevelEnabled ContainerCheckpointRestoreLevel `toml:"container_level_enabled"`
+
+	// PodLevelEnabled configures the level of pod checkpoint and restore
+	// support, which checkpoints all containers of a pod sandbox into one
+	// archive and restores them into a new pod sandbox. It accepts the same
+	// values as ContainerLevelEnabled.
+	PodLevelEnabled ContainerCheckpointRestoreLevel `toml:"pod_level_enabled"`
+
+	// Compression is the compression used for checkpoint archives and the
+	// layer of checkpoint images. Restoring detects the compression
+	// automatically.
+	Compression CheckpointCompression `toml:"compression"`
+
+	// PreDumpIterations is the number of CRIU pre-dump iterations copying the
+	// memory of a running container before it gets paused for the final
+	// dump, which then only copies the memory pages changed since the last
+	// pre-dump.
+	PreDumpIterations int `toml:"pre_dump_iterations"`
+
+	// EncryptionKeysPath is the path of the directory containing the public
+	// keys and certificates of the recipients for which checkpoint archives
+	// and pushed checkpoint images get encrypted. Checkpoints are not
+	// encrypted if it is empty.
+	EncryptionKeysPath string `toml:"encryption_keys_path"`
+
+	// AutoCheckpointDirectory is the directory into which containers of pods
+	// with the auto-checkpoint.crio.io annotation are checkpointed
+	// automatically. Automatic checkpoints are disabled if it is empty.
+	AutoCheckpointDirectory string `toml:"auto_checkpoint_directory"`
+
+	// AutoCheckpointDrainFile is the file indicating that the node gets
+	// drained. If it exists when CRI-O receives SIGTERM, the containers of
+	// pods opting in with the "drain" policy are checkpointed.
+	AutoCheckpointDrainFile string `toml:"auto_checkpoint_drain_file"`
+
+	// AutoCheckpointMemoryPressure is the memory pressure threshold in
+	// percent. Containers of pods opting in with the "memory-pressure" policy
+	// are checkpointed once the share of time in which some of their tasks
+	// stalled on memory within the last 10 seconds crosses it.
+	AutoCheckpointMemoryPressure int `toml:"auto_checkpoint_memory_pressure"`
 }
 
 // Validate checks whether the checkpoint/restore configuration is valid. When
@@ -407,14 +559,43 @@ type CheckpointRestoreConfig struct {
 // available in $PATH, disabling checkpoint/restore support if it is not.
 func (c *CheckpointRestoreConfig) Validate(onExecution bool) error {
 	if err := c.ContainerLevelEnabled.Validate(); err != nil {
-		return err
+		return fmt.Errorf("container_level_enabled: %w", err)
+	}
+
+	if err := c.PodLevelEnabled.Validate(); err != nil {
+		return fmt.Errorf("pod_level_enabled: %w", err)
+	}
+
+	if err := c.Compression.Validate(); err != nil {
+		return fmt.Errorf("compression: %w", err)
+	}
+
+	if c.PreDumpIterations < 0 || c.PreDumpIterations > maxCheckpointPreDumpIterations {
+		return fmt.Errorf("invalid pre_dump_iterations: must be between 0 and %d, got %d", maxCheckpointPreDumpIterations, c.PreDumpIterations)
+	}
+
+	if c.AutoCheckpointMemoryPressure < 1 || c.AutoCheckpointMemoryPressure > 100 {
+		return fmt.Errorf("invalid auto_checkpoint_memory_pressure: must be between 1 and 100, got %d", c.AutoCheckpointMemoryPressure)
 	}
 
 	if !onExecution {
 		return nil
 	}
 
-	if c.ContainerLevelEnabled == ContainerCheckpointRestoreLevelNone {
+	if c.AutoCheckpointDirectory != "" {
+		if err := os.MkdirAll(c.AutoCheckpointDirectory, 0o700); err != nil {
+			return fmt.Errorf("invalid auto_checkpoint_directory: %w", err)
+		}
+	}
+
+	if c.EncryptionKeysPath != "" {
+		if _, err := os.Stat(c.EncryptionKeysPath); err != nil {
+			return fmt.Errorf("invalid encryption_keys_path: %w", err)
+		}
+	}
+
+	if c.ContainerLevelEnabled == ContainerCheckpointRestoreLevelNone &&
+		c.PodLevelEnabled == ContainerCheckpointRestoreLevelNone {
 		logrus.Infof("Checkpoint/restore support disabled via configuration")
 
 		return nil
@@ -422,10 +603,11 @@ func (c *CheckpointRestoreConfig) Validate(onExecution bool) error {
 
 	if err := validateCriuInPath(); err != nil {
 		c.ContainerLevelEnabled = ContainerCheckpointRestoreLevelNone
+		c.PodLevelEnabled = ContainerCheckpointRestoreLevelNone
 
 		logrus.Infof("Checkpoint/restore support disabled: CRIU binary not found in $PATH")
 	} else {
-		logrus.Infof("Checkpoint/restore support enabled (level: %q)", c.ContainerLevelEnabled)
+		logrus.Infof("Checkpoint/restore support enabled (level: %q, pod level: %q)", c.ContainerLevelEnabled, c.PodLevelEnabled)
 	}
 
 	return nil
@@ -485,6 +667,12 @@ type RuntimeConfig struct {
 	// spec. Set to 0 to disable. Defaults to 0 (disabled).
 	MinInjectedGOMAXPROCS int64 `toml:"min_injected_gomaxprocs"`
 
+	// RuntimeTuning are the language runtimes ("jvm", "node" and "dotnet")
+	// for which environment variables derived from the CPU and memory limits
+	// of the container are injected. Variables already set via the image or
+	// pod spec are kept. Empty by default (disabled).
+	RuntimeTuning []string `toml:"runtime_tuning"`
+
 	// Sysctls to add to all containers.
 	DefaultSysctls []string `toml:"default_sysctls"`
 
@@ -591,6 +779,18 @@ type RuntimeConfig struct {
 	// to us via CRI, for a pod that isn't to be run as UID 0.
 	MinimumMappableGID int64 `toml:"minimum_mappable_gid"`
 
+	// UsernsPools defines ranges of host IDs CRI-O allocates the user
+	// namespaces of pods using the "auto" user namespace mode from.
+	UsernsPools UsernsPools `toml:"userns_pools"`
+
+	// UsernsAllocationsFile is the location CRI-O persists the user namespace
+	// ID ranges allocated for the pods.
+	UsernsAllocationsFile string `toml:"userns_allocations_file"`
+
+	// ExecHooks are the runtime handler hooks running external binaries,
+	// which can be referenced by the handler_hooks of the runtime handlers.
+	ExecHooks ExecHooks `toml:"exec_hooks"`
+
 	// LogLevel determines the verbosity of the logs based on the level it is set to.
 	// Options are fatal, panic, error (default), warn, info, debug, and trace.
 	LogLevel string `toml:"log_level"`
@@ -634,6 +834,10 @@ type RuntimeConfig struct {
 	// Negative values indicate that the log file won't be truncated.
 	LogSizeMax int64 `toml:"log_size_max"`
 
+	// NamespaceLogRotation are the defaults for the rotation of container
+	// logs by CRI-O per Kubernetes namespace.
+	NamespaceLogRotation map[string]*ContainerLogRotation `toml:"namespace_log_rotation,omitempty"`
+
 	// CtrStopTimeout specifies the time to wait before to generate an
 	// error because the container state is still tagged as "running".
 	CtrStopTimeout int64 `toml:"ctr_stop_timeout"`
@@ -648,6 +852,15 @@ type RuntimeConfig struct {
 	// want access to shared cpus.
 	SharedCPUSet string `toml:"shared_cpuset"`
 
+	// CPUAllocatorPool is the CPU set CRI-O allocates exclusive, NUMA aligned
+	// CPUs for containers of Guaranteed pods from, if the kubelet did not
+	// assign a cpuset. The allocator is disabled if empty.
+	CPUAllocatorPool string `toml:"cpu_allocator_pool"`
+
+	// CPUAllocationsFile is the location CRI-O persists the exclusive CPUs
+	// allocated for the containers.
+	CPUAllocationsFile string `toml:"cpu_allocations_file"`
+
 	// AbsentMountSourcesToReject is a list of paths that, when absent from the host,
 	// will cause a container creation to fail (as opposed to the current behavior of creating a directory).
 	AbsentMountSourcesToReject []string `toml:"absent_mount_sources_to_reject"`
@@ -806,6 +1019,36 @@ type NetworkConfig struct {
 	// upgrades (e.g. OVN-K daemonset rollout).
 	CNIStatusGracePeriod time.Duration `toml:"cni_status_grace_period"`
 
+	// CNISetupTimeout is the maximum duration a CNI ADD (and the subsequent
+	// status CHECK) may take. It is added on top of the deadline of the
+	// RunPodSandbox request.
+	CNISetupTimeout time.Duration `toml:"cni_setup_timeout"`
+
+	// CNITeardownTimeout is the maximum duration of a single CNI DEL attempt.
+	CNITeardownTimeout time.Duration `toml:"cni_teardown_timeout"`
+
+	// CNITeardownRetries is the number of times a failed CNI DEL will be
+	// retried before the pod network is considered to be stopped.
+	CNITeardownRetries int `toml:"cni_teardown_retries"`
+
+	// CNIGCTimeout is the maximum duration of a CNI GC call.
+	CNIGCTimeout time.Duration `toml:"cni_gc_timeout"`
+
+	// DNSCacheAddress is the node local IP address the caching DNS resolver
+	// for pods listens on. The resolver is disabled if empty.
+	DNSCacheAddress string `toml:"dns_cache_address"`
+
+	// DNSCacheUpstreams are the nameservers used by the DNS cache for pods
+	// without own nameservers. Queries of unknown clients are dropped.
+	DNSCacheUpstreams []string `toml:"dns_cache_upstreams"`
+
+	// DNSCacheSize is the maximum number of answers kept by the DNS cache.
+	DNSCacheSize int `toml:"dns_cache_size"`
+
+	// DNSCacheMaxTTL is the maximum duration an answer is kept by the DNS
+	// cache, regardless of its TTL.
+	DNSCacheMaxTTL time.Duration `toml:"dns_cache_max_ttl"`
+
 	// cniManager manages the internal ocicni plugin
 	cniManager *cnimgr.CNIManager
 }
@@ -909,6 +1152,20 @@ type StatsConfig struct {
 	// and pod sandbox metrics. If set to 0, the metrics/stats are collected on-demand instead.
 	CollectionPeriod int `toml:"collection_period"`
 
+	// OnDemandCacheDuration is the duration for which stats collected
+	// on-demand are reused by subsequent requests, if CollectionPeriod is 0.
+	OnDemandCacheDuration time.Duration `toml:"on_demand_cache_duration"`
+
+	// AdaptiveCollectionPeriod is the number of seconds between collecting
+	// the stats of busy containers, which has to be less than
+	// CollectionPeriod. If set to 0, all stats are collected every
+	// CollectionPeriod.
+	AdaptiveCollectionPeriod int `toml:"adaptive_collection_period"`
+
+	// AdaptiveCollectionCPUThreshold is the CPU usage in millicores above
+	// which containers are considered busy for the adaptive collection.
+	AdaptiveCollectionCPUThreshold int `toml:"adaptive_collection_cpu_threshold"`
+
 	// IncludedPodMetrics specifies the list of metrics to include when collecting pod metrics.
 	// If "all" is specified, all metrics are included. In that case, "all" should be the only element.
 	//
@@ -1180,7 +1437,11 @@ func DefaultConfig() (*Config, error) {
 		},
 		RuntimeConfig: *DefaultRuntimeConfig(cgroupManager),
 		CheckpointRestoreConfig: CheckpointRestoreConfig{
-			ContainerLevelEnabled: ContainerCheckpointRestoreLevelCheckpointRestore,
+			ContainerLevelEnabled:        ContainerCheckpointRestoreLevelCheckpointRestore,
+			PodLevelEnabled:              ContainerCheckpointRestoreLevelNone,
+			Compression:                  CheckpointCompressionNone,
+			AutoCheckpointDrainFile:      CrioDrainFile,
+			AutoCheckpointMemoryPressure: defaultAutoCheckpointMemoryPressure,
 		},
 		ImageConfig: ImageConfig{
 			DefaultTransport:        "docker://",
@@ -1194,14 +1455,22 @@ func DefaultConfig() (*Config, error) {
 			NamespacedAuthDir:       cpConfig.AuthDir,
 		},
 		NetworkConfig: NetworkConfig{
-			NetworkDir: cniConfigDir,
-			PluginDirs: []string{cniBinDir},
+			NetworkDir:         cniConfigDir,
+			PluginDirs:         []string{cniBinDir},
+			CNISetupTimeout:    defaultCNISetupTimeout,
+			CNITeardownTimeout: defaultCNITeardownTimeout,
+			CNIGCTimeout:       defaultCNIGCTimeout,
+			DNSCacheSize:       defaultDNSCacheSize,
+			DNSCacheMaxTTL:     defaultDNSCacheMaxTTL,
 		},
 		MetricsConfig: MetricsConfig{
 			MetricsHost:       "127.0.0.1",
 			MetricsPort:       9090,
 			MetricsCollectors: collectors.All(),
 		},
+		StatsConfig: StatsConfig{
+			AdaptiveCollectionCPUThreshold: defaultAdaptiveCollectionCPUThreshold,
+		},
 		TracingConfig: TracingConfig{
 			TracingEndpoint:               "127.0.0.1:4317",
 			TracingSamplingRatePerMillion: 0,
@@ -1232,6 +1501,8 @@ func DefaultRuntimeConfig(cgroupManager cgmgr.CgroupManager) *RuntimeConfig {
 		ContainerAttachSocketDir:    ContainerAttachSocketDir,
 		MinimumMappableUID:          -1,
 		MinimumMappableGID:          -1,
+		UsernsAllocationsFile:       CrioUsernsAllocationsFile,
+		CPUAllocationsFile:          CrioCPUAllocationsFile,
 		LogSizeMax:                  DefaultLogSizeMax,
 		CtrStopTimeout:              defaultCtrStopTimeout,
 		DefaultCapabilities:         capabilities.Default(),
@@ -1460,6 +1731,10 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 		return fmt.Errorf("min_injected_gomaxprocs must be >= 0, got %d", c.MinInjectedGOMAXPROCS)
 	}
 
+	if err := validateRuntimeTuning(c.RuntimeTuning); err != nil {
+		return err
+	}
+
 	for _, p := range c.AdditionalArtifactStores {
 		if !filepath.IsAbs(p) {
 			return fmt.Errorf("additional_artifact_stores entry must be absolute: %q", p)
@@ -1489,6 +1764,10 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 		return fmt.Errorf("log size max should be negative or >= %d", OCIBufSize)
 	}
 
+	if err := c.ValidateNamespaceLogRotation(); err != nil {
+		return fmt.Errorf("invalid namespace_log_rotation: %w", err)
+	}
+
 	// We need to ensure the container termination will be properly waited
 	// for by defining a minimal timeout value. This will prevent timeout
 	// value defined in the configuration file to be too low.
@@ -1519,10 +1798,44 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 		cmdrunner.PrependCommandsWith(executable, "--cpu-list", set.String())
 	}
 
+	if c.CPUAllocatorPool != "" {
+		pool, err := cpuset.Parse(c.CPUAllocatorPool)
+		if err != nil {
+			return fmt.Errorf("invalid cpu_allocator_pool: %w", err)
+		}
+
+		if pool.IsEmpty() {
+			return errors.New("cpu_allocator_pool must not be empty")
+		}
+
+		for _, other := range []struct{ name, cpus string }{
+			{"infra_ctr_cpuset", c.InfraCtrCPUSet},
+			{"shared_cpuset", c.SharedCPUSet},
+		} {
+			if set, err := cpuset.Parse(other.cpus); err == nil && !set.Intersection(pool).IsEmpty() {
+				return fmt.Errorf("cpu_allocator_pool overlaps with %s", other.name)
+			}
+		}
+	}
+
 	if err := c.Workloads.Validate(); err != nil {
 		return fmt.Errorf("workloads validation: %w", err)
 	}
 
+	if err := c.UsernsPools.Validate(c); err != nil {
+		return fmt.Errorf("userns pools validation: %w", err)
+	}
+
+	if err := c.ExecHooks.Validate(); err != nil {
+		return fmt.Errorf("exec hooks validation: %w", err)
+	}
+
+	for name, handler := range c.Runtimes {
+		if err := handler.ValidateHandlerHooks(name, c.ExecHooks); err != nil {
+			return err
+		}
+	}
+
 	// check for validation on execution
 	if onExecution {
 		// First, configure cgroup manager so the values of the Runtime.MonitorCgroup can be validated
@@ -1723,6 +2036,28 @@ func (c *RuntimeConfig) ValidateRuntimes() error {
 		delete(c.Runtimes, invalidHandlerName)
 	}
 
+	for name, handler := range c.Runtimes {
+		if handler.FallbackRuntimeHandler == "" {
+			continue
+		}
+
+		if handler.FallbackRuntimeHandler == name {
+			return fmt.Errorf("runtime handler %q cannot fall back to itself", name)
+		}
+
+		if _, ok := c.Runtimes[handler.FallbackRuntimeHandler]; !ok {
+			return fmt.Errorf("fallback runtime handler %q of runtime handler %q does not exist", handler.FallbackRuntimeHandler, name)
+		}
+
+		if c.DropInfraCtr {
+			logrus.Warnf(
+				"Fallback runtime handler %q of runtime handler %q only applies to pods running an infra container, "+
+					"which is dropped for most pods because drop_infra_ctr is enabled",
+				handler.FallbackRuntimeHandler, name,
+			)
+		}
+	}
+
 	c.initializeRuntimeFeatures()
 
 	return nil
@@ -1730,60 +2065,78 @@ func (c *RuntimeConfig) ValidateRuntimes() error {
 
 func (c *RuntimeConfig) initializeRuntimeFeatures() {
 	for name, handler := range c.Runtimes {
-		versionOutput, err := cmdrunner.CombinedOutput(handler.RuntimePath, "--version")
-		if err != nil {
-			logrus.Errorf("Unable to determine version of runtime handler %q: %v", name, err)
-
-			continue
+		// Reset the features first, so that runtime handlers failing to
+		// report them do not keep the ones of a previously probed binary.
+		handler.features = probeRuntimeFeatures(name, handler.RuntimePath)
+		if handler.CanaryRuntimePath != "" {
+			handler.canaryFeatures = probeRuntimeFeatures(name, handler.CanaryRuntimePath)
+		} else {
+			handler.canaryFeatures = runtimeHandlerFeatures{}
 		}
+	}
+}
 
-		versionString := strings.ReplaceAll(strings.TrimSpace(string(versionOutput)), "\n", ", ")
-		logrus.Infof("Using runtime handler %s", versionString)
+// probeRuntimeFeatures returns the features reported by the runtime at
+// runtimePath of the runtime handler name, or empty features if they are not
+// available.
+func probeRuntimeFeatures(name, runtimePath string) runtimeHandlerFeatures {
+	var f runtimeHandlerFeatures
 
-		// If this returns an error, we just ignore it and assume the features sub-command is
-		// not supported by the runtime.
-		output, err := cmdrunner.CombinedOutput(handler.RuntimePath, "features")
-		if err != nil {
-			logrus.Errorf("Getting %s OCI runtime features failed: %s: %v", handler.RuntimePath, output, err)
+	versionOutput, err := cmdrunner.CombinedOutput(runtimePath, "--version")
+	if err != nil {
+		logrus.Errorf("Unable to determine version of runtime handler %q: %v", name, err)
 
-			continue
-		}
+		return f
+	}
 
-		// Ignore error if we can't load runtime features.
-		if err := handler.LoadRuntimeFeatures(output); err != nil {
-			logrus.Errorf("Unable to load OCI features for runtime handler %q: %v", name, err)
+	versionString := strings.ReplaceAll(strings.TrimSpace(string(versionOutput)), "\n", ", ")
+	logrus.Infof("Using runtime handler %s", versionString)
 
-			continue
-		}
+	// If this returns an error, we just ignore it and assume the features sub-command is
+	// not supported by the runtime.
+	output, err := cmdrunner.CombinedOutput(runtimePath, "features")
+	if err != nil {
+		logrus.Errorf("Getting %s OCI runtime features failed: %s: %v", runtimePath, output, err)
 
-		if handler.RuntimeSupportsIDMap() {
-			logrus.Debugf("Runtime handler %q supports User and Group ID-mappings", name)
-		}
+		return f
+	}
+
+	// Ignore error if we can't load runtime features.
+	if err := f.load(output); err != nil {
+		logrus.Errorf("Unable to load OCI features for runtime handler %q: %v", name, err)
 
-		// Recursive Read-only (RRO) mounts require runtime handler support,
-		// such as runc v1.1 or crun v1.4. For Linux, the minimum kernel
-		// version 5.12 or a kernel with the necessary changes backported
-		// is required.
-		rro := handler.RuntimeSupportsMountFlag("rro")
-		if rro {
-			logrus.Debugf("Runtime handler %q supports Recursive Read-only (RRO) mounts", name)
+		return runtimeHandlerFeatures{}
+	}
 
-			// A given runtime might support Recursive Read-only (RRO) mounts,
-			// but the current kernel might not.
-			if err := checkKernelRROMountSupport(); err != nil {
-				logrus.Warnf("Runtime handler %q supports Recursive Read-only (RRO) mounts, but kernel does not: %v", name, err)
+	if f.supportsIDMap() {
+		logrus.Debugf("Runtime handler %q supports User and Group ID-mappings", name)
+	}
 
-				rro = false
-			}
-		}
+	// Recursive Read-only (RRO) mounts require runtime handler support,
+	// such as runc v1.1 or crun v1.4. For Linux, the minimum kernel
+	// version 5.12 or a kernel with the necessary changes backported
+	// is required.
+	rro := f.supportsMountFlag("rro")
+	if rro {
+		logrus.Debugf("Runtime handler %q supports Recursive Read-only (RRO) mounts", name)
 
-		handler.features.RecursiveReadOnlyMounts = rro
+		// A given runtime might support Recursive Read-only (RRO) mounts,
+		// but the current kernel might not.
+		if err := checkKernelRROMountSupport(); err != nil {
+			logrus.Warnf("Runtime handler %q supports Recursive Read-only (RRO) mounts, but kernel does not: %v", name, err)
+
+			rro = false
+		}
 	}
+
+	f.RecursiveReadOnlyMounts = rro
+
+	return f
 }
 
 func (c *RuntimeConfig) TranslateMonitorFields(onExecution bool) error {
 	for name, handler := range c.Runtimes {
-		if handler.RuntimeType == DefaultRuntimeType || handler.RuntimeType == "" {
+		if handler.RuntimeType == DefaultRuntimeType || handler.RuntimeType == "" || handler.RuntimeType == RuntimeTypeWasm {
 			if err := c.TranslateMonitorFieldsForHandler(handler, onExecution); err != nil {
 				return fmt.Errorf("failed to translate monitor fields for runtime %s: %w", name, err)
 			}
@@ -1935,6 +2288,24 @@ func (c *CheckpointRestoreConfig) RestoreContainerEnabled() bool {
 	return c.ContainerLevelEnabled == ContainerCheckpointRestoreLevelCheckpointRestore
 }
 
+// CheckpointPodEnabled returns whether checkpointing pod sandboxes is enabled
+// (i.e. the pod level is "checkpoint_only" or "checkpoint_restore").
+func (c *CheckpointRestoreConfig) CheckpointPodEnabled() bool {
+	switch c.PodLevelEnabled {
+	case ContainerCheckpointRestoreLevelCheckpointOnly,
+		ContainerCheckpointRestoreLevelCheckpointRestore:
+		return true
+	default:
+		return false
+	}
+}
+
+// RestorePodEnabled returns whether restoring pod sandboxes is enabled (i.e.
+// the pod level is "checkpoint_restore").
+func (c *CheckpointRestoreConfig) RestorePodEnabled() bool {
+	return c.PodLevelEnabled == ContainerCheckpointRestoreLevelCheckpointRestore
+}
+
 func validateExecutablePath(executable, currentPath string) (string, error) {
 	if currentPath == "" {
 		path, err := exec.LookPath(executable)
@@ -2001,6 +2372,26 @@ func (c *NetworkConfig) Validate(onExecution bool) error {
 		return fmt.Errorf("invalid cni_status_grace_period: must not be negative, got %v", c.CNIStatusGracePeriod)
 	}
 
+	for name, timeout := range map[string]time.Duration{
+		"cni_setup_timeout":    c.CNISetupTimeout,
+		"cni_teardown_timeout": c.CNITeardownTimeout,
+		"cni_gc_timeout":       c.CNIGCTimeout,
+	} {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid %s: must be positive, got %v", name, timeout)
+		}
+	}
+
+	if c.CNITeardownRetries < 0 || c.CNITeardownRetries > maxCNITeardownRetries {
+		return fmt.Errorf("invalid cni_teardown_retries: must be between 0 and %d, got %d", maxCNITeardownRetries, c.CNITeardownRetries)
+	}
+
+	if c.DNSCacheAddress != "" {
+		if err := c.validateDNSCache(); err != nil {
+			return err
+		}
+	}
+
 	if onExecution {
 		err := utils.IsDirectory(c.NetworkDir)
 		if err != nil {
@@ -2042,6 +2433,8 @@ func (c *NetworkConfig) Validate(onExecution bool) error {
 			return fmt.Errorf("initialize CNI plugin: %w", err)
 		}
 
+		cniManager.SetGCTimeout(c.CNIGCTimeout)
+
 		c.cniManager = cniManager
 	}
 
@@ -2076,6 +2469,14 @@ func (r *RuntimeHandler) Validate(name string) error {
 		return fmt.Errorf("no sync log: %w", err)
 	}
 
+	if err := r.ValidateLogFormat(name); err != nil {
+		return err
+	}
+
+	if err := r.ContainerLogRotation.Validate(); err != nil {
+		return fmt.Errorf("invalid log rotation for runtime %q: %w", name, err)
+	}
+
 	if err := r.ValidateWebsocketStreaming(name); err != nil {
 		return fmt.Errorf("websocket streaming: %w", err)
 	}
@@ -2088,11 +2489,19 @@ func (r *RuntimeHandler) Validate(name string) error {
 		return err
 	}
 
+	if err := r.ValidateRuntimeCanary(name); err != nil {
+		return fmt.Errorf("canary: %w", err)
+	}
+
+	if err := r.ValidateRuntimeFallbackErrors(name); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (r *RuntimeHandler) ValidateRuntimeVMBinaryPattern() bool {
-	if r.RuntimeType != RuntimeTypeVM {
+	if r.RuntimeType != RuntimeTypeVM && r.RuntimeType != RuntimeTypeShimV2 {
 		return true
 	}
 
@@ -2135,9 +2544,52 @@ func (r *RuntimeHandler) ValidateRuntimePath(name string) error {
 	return nil
 }
 
+// ValidateRuntimeCanary checks if the `CanaryRuntimePath` exists and the
+// `CanaryPercentage` is valid.
+func (r *RuntimeHandler) ValidateRuntimeCanary(name string) error {
+	if r.CanaryPercentage < 0 || r.CanaryPercentage > 100 {
+		return fmt.Errorf("invalid canary_percentage %d for runtime %q: must be between 0 and 100", r.CanaryPercentage, name)
+	}
+
+	if r.CanaryRuntimePath == "" {
+		return nil
+	}
+
+	if r.RuntimeType != DefaultRuntimeType && r.RuntimeType != "" {
+		return fmt.Errorf("canary_runtime_path is only allowed with runtime type 'oci', runtime type is '%s'", r.RuntimeType)
+	}
+
+	if _, err := os.Stat(r.CanaryRuntimePath); err != nil {
+		return fmt.Errorf("invalid canary_runtime_path for runtime %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// ValidateRuntimeFallbackErrors compiles the `FallbackErrors` once, so that
+// they can be matched against every failed pod sandbox creation.
+func (r *RuntimeHandler) ValidateRuntimeFallbackErrors(name string) error {
+	fallbackErrors := make([]*regexp.Regexp, 0, len(r.FallbackErrors))
+
+	for _, expr := range r.FallbackErrors {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("invalid fallback_errors for runtime %q: %w", name, err)
+		}
+
+		fallbackErrors = append(fallbackErrors, re)
+	}
+
+	r.fallbackErrors = fallbackErrors
+
+	return nil
+}
+
 // ValidateRuntimeType checks if the `RuntimeType` is valid.
 func (r *RuntimeHandler) ValidateRuntimeType(name string) error {
-	if r.RuntimeType != "" && r.RuntimeType != DefaultRuntimeType && r.RuntimeType != RuntimeTypeVM && r.RuntimeType != RuntimeTypePod {
+	switch r.RuntimeType {
+	case "", DefaultRuntimeType, RuntimeTypeVM, RuntimeTypePod, RuntimeTypeShimV2, RuntimeTypeWasm:
+	default:
 		return fmt.Errorf("invalid `runtime_type` %q for runtime %q",
 			r.RuntimeType, name)
 	}
@@ -2230,6 +2682,12 @@ func (r *RuntimeHandler) ValidateContainerCreateTimeout(name string) {
 
 // ValidateWebsocketStreaming can be used to verify if the runtime supports WebSocket streaming.
 func (r *RuntimeHandler) ValidateWebsocketStreaming(name string) error {
+	// Exec and attach of shimv2 runtimes are served by the CRI-O streaming
+	// server through the task API of the shim.
+	if r.RuntimeType == RuntimeTypeShimV2 {
+		return nil
+	}
+
 	if r.RuntimeType != RuntimeTypePod {
 		if r.StreamWebsockets {
 			return fmt.Errorf(`only the 'runtime_type = "pod"' supports websocket streaming, not %q (runtime %q)`, r.RuntimeType, name)
@@ -2273,7 +2731,11 @@ func (r *RuntimeHandler) ValidateWebsocketStreaming(name string) error {
 // sub-command output, where said output contains a JSON document called "Features
 // Structure" that describes the runtime handler's supported features.
 func (r *RuntimeHandler) LoadRuntimeFeatures(input []byte) error {
-	if err := json.Unmarshal(input, &r.features); err != nil {
+	return r.features.load(input)
+}
+
+func (f *runtimeHandlerFeatures) load(input []byte) error {
+	if err := json.Unmarshal(input, f); err != nil {
 		return fmt.Errorf("unable to unmarshal features structure: %w", err)
 	}
 
@@ -2285,7 +2747,7 @@ func (r *RuntimeHandler) LoadRuntimeFeatures(input []byte) error {
 	//
 	// See the following for more details about the Features Structure:
 	//   https://github.com/opencontainers/runtime-spec/blob/main/features.md
-	if r.features.OCIVersionMin == "" || r.features.OCIVersionMax == "" {
+	if !f.loaded() {
 		return errors.New("runtime features structure is not valid")
 	}
 
@@ -2295,11 +2757,15 @@ func (r *RuntimeHandler) LoadRuntimeFeatures(input []byte) error {
 // RuntimeSupportsIDMap returns whether this runtime supports the "runtime features"
 // command, and that the output of that command advertises IDMap mounts as an option.
 func (r *RuntimeHandler) RuntimeSupportsIDMap() bool {
-	if r.features.Linux == nil || r.features.Linux.MountExtensions == nil || r.features.Linux.MountExtensions.IDMap == nil {
+	return r.features.supportsIDMap()
+}
+
+func (f *runtimeHandlerFeatures) supportsIDMap() bool {
+	if f.Linux == nil || f.Linux.MountExtensions == nil || f.Linux.MountExtensions.IDMap == nil {
 		return false
 	}
 
-	if enabled := r.features.Linux.MountExtensions.IDMap.Enabled; enabled == nil || !*enabled {
+	if enabled := f.Linux.MountExtensions.IDMap.Enabled; enabled == nil || !*enabled {
 		return false
 	}
 
@@ -2313,7 +2779,42 @@ func (r *RuntimeHandler) RuntimeSupportsRROMounts() bool {
 
 // RuntimeSupportsMountFlag returns whether this runtime supports the specified mount option.
 func (r *RuntimeHandler) RuntimeSupportsMountFlag(flag string) bool {
-	return slices.Contains(r.features.MountOptions, flag)
+	return r.features.supportsMountFlag(flag)
+}
+
+func (f *runtimeHandlerFeatures) supportsMountFlag(flag string) bool {
+	return slices.Contains(f.MountOptions, flag)
+}
+
+// RuntimeCanaryPath returns the CanaryRuntimePath for CanaryPercentage of the
+// calls, and an empty string otherwise.
+func (r *RuntimeHandler) RuntimeCanaryPath() string {
+	if r.CanaryRuntimePath == "" || rand.IntN(100) >= r.CanaryPercentage {
+		return ""
+	}
+
+	return r.CanaryRuntimePath
+}
+
+// RuntimeFallbackHandler returns the FallbackRuntimeHandler if the runtime
+// error matches one of the FallbackErrors, and an empty string otherwise.
+func (r *RuntimeHandler) RuntimeFallbackHandler(runtimeErr error) string {
+	if r.FallbackRuntimeHandler == "" || runtimeErr == nil {
+		return ""
+	}
+
+	if len(r.FallbackErrors) == 0 {
+		return r.FallbackRuntimeHandler
+	}
+
+	// The expressions got compiled when validating the config.
+	for _, re := range r.fallbackErrors {
+		if re.MatchString(runtimeErr.Error()) {
+			return r.FallbackRuntimeHandler
+		}
+	}
+
+	return ""
 }
 
 // RuntimeDefaultAnnotations returns the default annotations for this handler.
@@ -2405,6 +2906,45 @@ func (c *Config) CNIPluginGC(ctx context.Context, validPodList cnimgr.PodNetwork
 	return c.cniManager.GC(ctx, validPodList)
 }
 
+// validateDNSCache validates the DNS cache options.
+func (c *NetworkConfig) validateDNSCache() error {
+	ip := net.ParseIP(c.DNSCacheAddress)
+	if ip == nil {
+		return fmt.Errorf("invalid dns_cache_address: %q is not an IP address", c.DNSCacheAddress)
+	}
+
+	// The address is used from within the network namespace of the pods.
+	if ip.IsLoopback() {
+		return fmt.Errorf("invalid dns_cache_address: %q is a loopback address, which is not reachable from pods", c.DNSCacheAddress)
+	}
+
+	for _, upstream := range c.DNSCacheUpstreams {
+		if net.ParseIP(upstream) != nil {
+			continue
+		}
+
+		host, port, err := net.SplitHostPort(upstream)
+		if err != nil || net.ParseIP(host) == nil || port == "" {
+			return fmt.Errorf("invalid dns_cache_upstreams: %q must be an IP or IP:port", upstream)
+		}
+	}
+
+	if c.DNSCacheSize <= 0 {
+		return fmt.Errorf("invalid dns_cache_size: must be positive, got %d", c.DNSCacheSize)
+	}
+
+	if c.DNSCacheMaxTTL <= 0 {
+		return fmt.Errorf("invalid dns_cache_max_ttl: must be positive, got %v", c.DNSCacheMaxTTL)
+	}
+
+	return nil
+}
+
+// CNIPluginSetGCObserver sets the observer for the plugin's GC calls.
+func (c *NetworkConfig) CNIPluginSetGCObserver(observer cnimgr.GCObserver) {
+	c.cniManager.SetGCObserver(observer)
+}
+
 // CNIManagerShutdown shuts down the CNI Manager.
 func (c *NetworkConfig) CNIManagerShutdown() {
 	c.cniManager.Shutdown()
@@ -2416,6 +2956,22 @@ func (c *Config) SetSingleConfigPath(singleConfigPath string) {
 }
 
 func (c *StatsConfig) Validate() error {
+	if c.OnDemandCacheDuration < 0 {
+		return errors.New("on_demand_cache_duration must not be negative")
+	}
+
+	if c.AdaptiveCollectionPeriod < 0 {
+		return errors.New("adaptive_collection_period must not be negative")
+	}
+
+	if c.AdaptiveCollectionPeriod > 0 && (c.CollectionPeriod == 0 || c.AdaptiveCollectionPeriod >= c.CollectionPeriod) {
+		return fmt.Errorf("adaptive_collection_period %d must be less than collection_period %d", c.AdaptiveCollectionPeriod, c.CollectionPeriod)
+	}
+
+	if c.AdaptiveCollectionCPUThreshold < 0 {
+		return errors.New("adaptive_collection_cpu_threshold must not be negative")
+	}
+
 	if len(c.IncludedPodMetrics) == 1 && c.IncludedPodMetrics[0] == AllMetrics {
 		c.includedPodMetrics = AvailableMetrics
 
@@ -28,6 +28,18 @@ const (
 	// If not, crio wipe will clear the storage directory.
 	CrioCleanShutdownFile = "/var/db/crio/clean.shutdown"
 
+	// CrioUsernsAllocationsFile is the location CRI-O persists the user
+	// namespace ID ranges allocated for the pods.
+	CrioUsernsAllocationsFile = "/var/db/crio/userns-allocations.json"
+
+	// CrioCPUAllocationsFile is the location CRI-O persists the exclusive
+	// CPUs allocated for the containers.
+	CrioCPUAllocationsFile = "/var/db/crio/cpu-allocations.json"
+
+	// CrioDrainFile is the location of the file indicating that the node gets
+	// drained, which makes CRI-O checkpoint containers on shutdown
+	CrioDrainFile = "/var/run/crio/drain"
+
 	DefaultRuntime       = "ocijail"
 	DefaultRuntimeType   = "oci"
 	DefaultRuntimeRoot   = "/var/run/ocijail"
@@ -3,6 +3,7 @@ package config_test
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"os"
 	"os/exec"
 	"path"
@@ -11,6 +12,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	"go.podman.io/storage"
 
 	"github.com/cri-o/cri-o/internal/config/cgmgr"
@@ -143,6 +145,89 @@ var _ = t.Describe("Config", func() {
 			// Then
 			Expect(err).To(HaveOccurred())
 		})
+		It("should fail on invalid pod checkpoint/restore level", func() {
+			// Given
+			sut.PodLevelEnabled = "invalid"
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("pod_level_enabled"))
+		})
+
+		It("should enable pod checkpointing only", func() {
+			// Given
+			sut.PodLevelEnabled = config.ContainerCheckpointRestoreLevelCheckpointOnly
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.CheckpointPodEnabled()).To(BeTrue())
+			Expect(sut.RestorePodEnabled()).To(BeFalse())
+		})
+
+		It("should fail on invalid checkpoint compression", func() {
+			// Given
+			sut.CheckpointRestoreConfig.Compression = "lz4"
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("compression"))
+		})
+
+		It("should fail on too many checkpoint pre-dump iterations", func() {
+			// Given
+			sut.PreDumpIterations = 11
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on invalid automatic checkpoint memory pressure", func() {
+			// Given
+			sut.AutoCheckpointMemoryPressure = 0
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("auto_checkpoint_memory_pressure"))
+		})
+
+		It("should create the automatic checkpoint directory", func() {
+			// Given
+			sut.AutoCheckpointDirectory = filepath.Join(t.MustTempDir("auto-checkpoint"), "checkpoints")
+
+			// When
+			err := sut.CheckpointRestoreConfig.Validate(true)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sut.AutoCheckpointDirectory).To(BeADirectory())
+		})
+
+		It("should fail on not existing checkpoint encryption keys path", func() {
+			// Given
+			sut.EncryptionKeysPath = "/not-existing"
+
+			// When
+			err := sut.CheckpointRestoreConfig.Validate(true)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("encryption_keys_path"))
+		})
 	})
 
 	t.Describe("ValidateAPIConfig", func() {
@@ -295,6 +380,71 @@ var _ = t.Describe("Config", func() {
 			Expect(err.Error()).To(ContainSubstring("relative/path"))
 		})
 
+		It("should succeed with valid userns pools", func() {
+			// Given
+			sut.UsernsPools = config.UsernsPools{
+				"default": {HostID: 100000, Size: 65536},
+				"kata":    {HostID: 165536, Size: 65536, RuntimeHandlers: []string{config.DefaultRuntime}},
+			}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail with overlapping userns pools", func() {
+			// Given
+			sut.UsernsPools = config.UsernsPools{
+				"a": {HostID: 100000, Size: 65536},
+				"b": {HostID: 150000, Size: 65536},
+			}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`userns pools "a" and "b" overlap`))
+		})
+
+		It("should fail with empty userns pool", func() {
+			// Given
+			sut.UsernsPools = config.UsernsPools{"empty": {HostID: 100000}}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with userns pool below the minimum mappable ID", func() {
+			// Given
+			sut.MinimumMappableUID = 200000
+			sut.UsernsPools = config.UsernsPools{"default": {HostID: 100000, Size: 65536}}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with userns pool for unknown runtime handler", func() {
+			// Given
+			sut.UsernsPools = config.UsernsPools{
+				"default": {HostID: 100000, Size: 65536, RuntimeHandlers: []string{"unknown"}},
+			}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should succeed during runtime", func() {
 			// Given
 			sut = runtimeValidConfig()
@@ -611,6 +761,177 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("should fail on invalid CPUAllocatorPool", func() {
+			// Given
+			sut.CPUAllocatorPool = "unparsable"
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if CPUAllocatorPool overlaps with SharedCPUSet", func() {
+			// Given
+			sut.CPUAllocatorPool = "2-5"
+			sut.SharedCPUSet = "0-2"
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should succeed with valid CPUAllocatorPool", func() {
+			// Given
+			sut.CPUAllocatorPool = "2-5"
+			sut.SharedCPUSet = "0-1"
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should succeed with valid RuntimeTuning", func() {
+			// Given
+			sut.RuntimeTuning = []string{config.RuntimeTuningJVM, config.RuntimeTuningDotnet}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail on unsupported RuntimeTuning", func() {
+			// Given
+			sut.RuntimeTuning = []string{"python"}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on duplicate RuntimeTuning", func() {
+			// Given
+			sut.RuntimeTuning = []string{config.RuntimeTuningNode, config.RuntimeTuningNode}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should succeed with valid exec hooks", func() {
+			// Given
+			sut.ExecHooks = config.ExecHooks{
+				"hook": &config.ExecHookConfig{
+					Path:   "/usr/local/bin/hook",
+					Stages: []string{config.HookStagePreStart},
+				},
+			}
+			sut.Runtimes[sut.DefaultRuntime].HandlerHooks = []string{
+				config.HandlerHookHighPerformance, "hook",
+			}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.ExecHooks["hook"].Timeout).To(Equal(10 * time.Second))
+			Expect(sut.ExecHooks["hook"].FailurePolicy).To(Equal(config.HookFailurePolicyFail))
+		})
+
+		It("should fail on exec hook with relative path", func() {
+			// Given
+			sut.ExecHooks = config.ExecHooks{
+				"hook": &config.ExecHookConfig{Path: "hook"},
+			}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on exec hook with invalid stage", func() {
+			// Given
+			sut.ExecHooks = config.ExecHooks{
+				"hook": &config.ExecHookConfig{
+					Path:   "/usr/local/bin/hook",
+					Stages: []string{"post-create"},
+				},
+			}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on exec hook with invalid failure policy", func() {
+			// Given
+			sut.ExecHooks = config.ExecHooks{
+				"hook": &config.ExecHookConfig{
+					Path:          "/usr/local/bin/hook",
+					FailurePolicy: "retry",
+				},
+			}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on exec hook named like a built-in hook", func() {
+			// Given
+			sut.ExecHooks = config.ExecHooks{
+				config.HandlerHookGOMAXPROCS: &config.ExecHookConfig{Path: "/usr/local/bin/hook"},
+			}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on unknown handler hook", func() {
+			// Given
+			sut.Runtimes[sut.DefaultRuntime].HandlerHooks = []string{"unknown"}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on duplicate handler hook", func() {
+			// Given
+			sut.Runtimes[sut.DefaultRuntime].HandlerHooks = []string{
+				config.HandlerHookGOMAXPROCS, config.HandlerHookGOMAXPROCS,
+			}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should inherit from .Conmon even if bogus", func() {
 			// Given
 			sut.Conmon = invalidPath
@@ -751,11 +1072,12 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
-		It("should fail with wrong runtime_type", func() {
+		It("should fail with invalid canary_percentage", func() {
 			// Given
 			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
-				RuntimePath: validFilePath,
-				RuntimeType: "wrong",
+				RuntimePath:       validFilePath,
+				CanaryRuntimePath: validFilePath,
+				CanaryPercentage:  101,
 			}
 
 			// When
@@ -765,11 +1087,12 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
-		It("should fail with wrong allowed_annotation", func() {
+		It("should fail with wrong canary_runtime_path", func() {
 			// Given
 			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
-				RuntimePath:        validFilePath,
-				AllowedAnnotations: []string{"wrong"},
+				RuntimePath:       validFilePath,
+				CanaryRuntimePath: invalidPath,
+				CanaryPercentage:  10,
 			}
 
 			// When
@@ -778,31 +1101,120 @@ var _ = t.Describe("Config", func() {
 			// Then
 			Expect(err).To(HaveOccurred())
 		})
-		It("should have allowed and disallowed annotation", func() {
+
+		It("should fail with not existing fallback_runtime_handler", func() {
 			// Given
 			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
-				RuntimePath:        validFilePath,
-				AllowedAnnotations: []string{v2.Devices},
+				RuntimePath:            validFilePath,
+				FallbackRuntimeHandler: invalid,
 			}
 
 			// When
 			err := sut.ValidateRuntimes()
 
 			// Then
-			Expect(err).ToNot(HaveOccurred())
-			Expect(sut.Runtimes[config.DefaultRuntime].AllowedAnnotations).To(ContainElement(v2.Devices))
-			Expect(sut.Runtimes[config.DefaultRuntime].DisallowedAnnotations).NotTo(ContainElement(v2.Devices))
+			Expect(err).To(HaveOccurred())
 		})
 
-		It("should allow no_sync_log for implicit default runtime", func() {
+		It("should fail if the runtime handler falls back to itself", func() {
+			// Given
 			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
-				RuntimePath: validFilePath,
+				RuntimePath:            validFilePath,
+				FallbackRuntimeHandler: config.DefaultRuntime,
 			}
-			sut.Runtimes[config.DefaultRuntime].NoSyncLog = true
 
-			err := sut.Runtimes[config.DefaultRuntime].Validate(config.DefaultRuntime)
+			// When
+			err := sut.ValidateRuntimes()
 
-			Expect(err).ToNot(HaveOccurred())
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with invalid fallback_errors", func() {
+			// Given
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath:    validFilePath,
+				FallbackErrors: []string{"("},
+			}
+
+			// When
+			err := sut.ValidateRuntimes()
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should succeed with canary and fallback runtime handler", func() {
+			// Given
+			sut.Runtimes["fallback"] = &config.RuntimeHandler{RuntimePath: validFilePath}
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath:            validFilePath,
+				CanaryRuntimePath:      validFilePath,
+				CanaryPercentage:       10,
+				FallbackRuntimeHandler: "fallback",
+				FallbackErrors:         []string{"^unknown version"},
+			}
+
+			// When
+			err := sut.ValidateRuntimes()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.Runtimes[config.DefaultRuntime].RuntimeFallbackHandler(errors.New("unknown version 2"))).To(Equal("fallback"))
+		})
+
+		It("should fail with wrong runtime_type", func() {
+			// Given
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath: validFilePath,
+				RuntimeType: "wrong",
+			}
+
+			// When
+			err := sut.ValidateRuntimes()
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with wrong allowed_annotation", func() {
+			// Given
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath:        validFilePath,
+				AllowedAnnotations: []string{"wrong"},
+			}
+
+			// When
+			err := sut.ValidateRuntimes()
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+		It("should have allowed and disallowed annotation", func() {
+			// Given
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath:        validFilePath,
+				AllowedAnnotations: []string{v2.Devices},
+			}
+
+			// When
+			err := sut.ValidateRuntimes()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.Runtimes[config.DefaultRuntime].AllowedAnnotations).To(ContainElement(v2.Devices))
+			Expect(sut.Runtimes[config.DefaultRuntime].DisallowedAnnotations).NotTo(ContainElement(v2.Devices))
+		})
+
+		It("should allow no_sync_log for implicit default runtime", func() {
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath: validFilePath,
+			}
+			sut.Runtimes[config.DefaultRuntime].NoSyncLog = true
+
+			err := sut.Runtimes[config.DefaultRuntime].Validate(config.DefaultRuntime)
+
+			Expect(err).ToNot(HaveOccurred())
 			Expect(sut.Runtimes[config.DefaultRuntime].NoSyncLog).To(BeTrue())
 		})
 
@@ -856,6 +1268,19 @@ var _ = t.Describe("Config", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("should allow 'stream_websockets == true' for the 'shimv2' runtime", func() {
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimePath:      validFilePath,
+				RuntimeType:      config.RuntimeTypeShimV2,
+				StreamWebsockets: true,
+			}
+
+			err := sut.Runtimes[config.DefaultRuntime].ValidateWebsocketStreaming(config.DefaultRuntime)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sut.Runtimes[config.DefaultRuntime].StreamWebsockets).To(BeTrue())
+		})
+
 		conmonrsFakeBinary := func(command string) (name string) {
 			file, err := os.CreateTemp("", "conmonrs-fake-*")
 			Expect(err).NotTo(HaveOccurred())
@@ -1228,6 +1653,127 @@ var _ = t.Describe("Config", func() {
 			// Then
 			Expect(err).ToNot(HaveOccurred())
 		})
+
+		It("should fail on zero CNISetupTimeout", func() {
+			// Given
+			sut.CNISetupTimeout = 0
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cni_setup_timeout"))
+		})
+
+		It("should fail on negative CNITeardownTimeout", func() {
+			// Given
+			sut.CNITeardownTimeout = -1 * time.Second
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cni_teardown_timeout"))
+		})
+
+		It("should fail on zero CNIGCTimeout", func() {
+			// Given
+			sut.CNIGCTimeout = 0
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cni_gc_timeout"))
+		})
+
+		It("should fail on too many CNITeardownRetries", func() {
+			// Given
+			sut.CNITeardownRetries = 6
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cni_teardown_retries"))
+		})
+
+		It("should succeed with CNITeardownRetries", func() {
+			// Given
+			sut.CNITeardownRetries = 3
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should succeed with DNS cache", func() {
+			// Given
+			sut.DNSCacheAddress = "169.254.20.10"
+			sut.DNSCacheUpstreams = []string{"10.96.0.10", "[fd00::10]:5353"}
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail on invalid DNSCacheAddress", func() {
+			// Given
+			sut.DNSCacheAddress = "localhost"
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("dns_cache_address"))
+		})
+
+		It("should fail on loopback DNSCacheAddress", func() {
+			// Given
+			sut.DNSCacheAddress = "127.0.0.1"
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("loopback"))
+		})
+
+		It("should fail on invalid DNSCacheUpstreams", func() {
+			// Given
+			sut.DNSCacheAddress = "169.254.20.10"
+			sut.DNSCacheUpstreams = []string{"dns.cri-o.io:53"}
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("dns_cache_upstreams"))
+		})
+
+		It("should fail on zero DNSCacheSize", func() {
+			// Given
+			sut.DNSCacheAddress = "169.254.20.10"
+			sut.DNSCacheSize = 0
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("dns_cache_size"))
+		})
 	})
 
 	t.Describe("ValidateRootConfig", func() {
@@ -1748,6 +2294,32 @@ var _ = t.Describe("Config", func() {
 			Expect(ok).To(BeFalse())
 		})
 
+		It("should succeed when using RuntimeTypeShimV2 and runtime_path follows the containerd pattern", func() {
+			// Given
+			sut.Runtimes["runc-shim"] = &config.RuntimeHandler{
+				RuntimePath: "containerd-shim-runc-v2", RuntimeType: config.RuntimeTypeShimV2,
+			}
+
+			// When
+			ok := sut.Runtimes["runc-shim"].ValidateRuntimeVMBinaryPattern()
+
+			// Then
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should fail when using RuntimeTypeShimV2 and runtime_path does not follow the containerd pattern", func() {
+			// Given
+			sut.Runtimes["runc-shim"] = &config.RuntimeHandler{
+				RuntimePath: "runc", RuntimeType: config.RuntimeTypeShimV2,
+			}
+
+			// When
+			ok := sut.Runtimes["runc-shim"].ValidateRuntimeVMBinaryPattern()
+
+			// Then
+			Expect(ok).To(BeFalse())
+		})
+
 		It("should fail when the binary name only contains the containerd-shim pattern as a substring", func() {
 			// Given the pattern is anchored, near-miss names that embed
 			// containerd-shim-* as a prefix or suffix must not match.
@@ -1769,6 +2341,214 @@ var _ = t.Describe("Config", func() {
 		})
 	})
 
+	t.Describe("ValidateRuntimeType", func() {
+		It("should succeed with the shimv2 runtime type", func() {
+			// Given
+			sut.Runtimes["wasm"] = &config.RuntimeHandler{
+				RuntimeType: config.RuntimeTypeShimV2,
+			}
+
+			// When
+			err := sut.Runtimes["wasm"].ValidateRuntimeType("wasm")
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should succeed with the wasm runtime type", func() {
+			// Given
+			sut.Runtimes["wasm"] = &config.RuntimeHandler{
+				RuntimeType: config.RuntimeTypeWasm,
+			}
+
+			// When
+			err := sut.Runtimes["wasm"].ValidateRuntimeType("wasm")
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail with an unknown runtime type", func() {
+			// Given
+			sut.Runtimes["wasm"] = &config.RuntimeHandler{
+				RuntimeType: invalid,
+			}
+
+			// When
+			err := sut.Runtimes["wasm"].ValidateRuntimeType("wasm")
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with shimv2 runtime type when runtime_config_path is used", func() {
+			// Given
+			sut.Runtimes["wasm"] = &config.RuntimeHandler{
+				RuntimeConfigPath: validFilePath, RuntimeType: config.RuntimeTypeShimV2,
+			}
+
+			// When
+			err := sut.Runtimes["wasm"].ValidateRuntimeConfigPath("wasm")
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	t.Describe("ValidateLogFormat", func() {
+		It("should succeed with the json log format for the pod runtime type", func() {
+			// Given
+			sut.Runtimes["pod"] = &config.RuntimeHandler{
+				RuntimeType: config.RuntimeTypePod, LogFormat: config.LogFormatJSON,
+			}
+
+			// When
+			err := sut.Runtimes["pod"].ValidateLogFormat("pod")
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail with the json log format for the oci runtime type", func() {
+			// Given
+			sut.Runtimes[config.DefaultRuntime] = &config.RuntimeHandler{
+				RuntimeType: config.DefaultRuntimeType, LogFormat: config.LogFormatJSON,
+			}
+
+			// When
+			err := sut.Runtimes[config.DefaultRuntime].ValidateLogFormat(config.DefaultRuntime)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with an invalid log format", func() {
+			// Given
+			sut.Runtimes["pod"] = &config.RuntimeHandler{
+				RuntimeType: config.RuntimeTypePod, LogFormat: invalid,
+			}
+
+			// When
+			err := sut.Runtimes["pod"].ValidateLogFormat("pod")
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	t.Describe("ContainerLogRotation", func() {
+		It("should succeed to validate a log rotation", func() {
+			// Given
+			rotation := &config.ContainerLogRotation{
+				LogRotateSize: "50MiB", LogRotateAge: "24h",
+			}
+
+			// When
+			err := rotation.Validate()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rotation.Enabled()).To(BeTrue())
+			Expect(rotation.RotateSize()).To(BeEquivalentTo(50 * 1024 * 1024))
+			Expect(rotation.RotateAge()).To(Equal(24 * time.Hour))
+			Expect(rotation.RotateMaxFiles()).To(Equal(5))
+		})
+
+		It("should fail with a log rotation size below the buffer size", func() {
+			// Given
+			rotation := &config.ContainerLogRotation{LogRotateSize: "1k"}
+
+			// When
+			err := rotation.Validate()
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with an invalid log rotation age", func() {
+			// Given
+			rotation := &config.ContainerLogRotation{LogRotateAge: invalid}
+
+			// When
+			err := rotation.Validate()
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with invalid namespace defaults", func() {
+			// Given
+			sut.NamespaceLogRotation = map[string]*config.ContainerLogRotation{
+				"default": {LogRotateMaxFiles: -1},
+			}
+
+			// When
+			err := sut.ValidateNamespaceLogRotation()
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should prefer the log rotation of the runtime handler", func() {
+			// Given
+			handler := &config.RuntimeHandler{
+				ContainerLogRotation: config.ContainerLogRotation{LogRotateSize: "10MiB"},
+			}
+			Expect(handler.ContainerLogRotation.Validate()).To(Succeed())
+			sut.Runtimes["rotating"] = handler
+			sut.NamespaceLogRotation = map[string]*config.ContainerLogRotation{
+				"default": {LogRotateAge: "1h"},
+			}
+			Expect(sut.ValidateNamespaceLogRotation()).To(Succeed())
+
+			// When
+			handlerRotation := sut.ContainerLogRotation("rotating", "default")
+			namespaceRotation := sut.ContainerLogRotation("", "default")
+			noRotation := sut.ContainerLogRotation("", "other")
+
+			// Then
+			Expect(handlerRotation.LogRotateSize).To(Equal("10MiB"))
+			Expect(namespaceRotation.LogRotateAge).To(Equal("1h"))
+			Expect(noRotation).To(BeNil())
+		})
+	})
+
+	t.Describe("WasmRuntimeHandler", func() {
+		It("should return nothing without wasm runtime handler", func() {
+			// Given
+			// When
+			handler := sut.WasmRuntimeHandler()
+
+			// Then
+			Expect(handler).To(BeEmpty())
+		})
+
+		It("should return the first wasm runtime handler by name", func() {
+			// Given
+			sut.Runtimes["wasm-b"] = &config.RuntimeHandler{RuntimeType: config.RuntimeTypeWasm}
+			sut.Runtimes["wasm-a"] = &config.RuntimeHandler{RuntimeType: config.RuntimeTypeWasm}
+
+			// When
+			handler := sut.WasmRuntimeHandler()
+
+			// Then
+			Expect(handler).To(Equal("wasm-a"))
+		})
+
+		It("should prefer the default runtime", func() {
+			// Given
+			sut.Runtimes["wasm-a"] = &config.RuntimeHandler{RuntimeType: config.RuntimeTypeWasm}
+			sut.Runtimes["wasm-b"] = &config.RuntimeHandler{RuntimeType: config.RuntimeTypeWasm}
+			sut.DefaultRuntime = "wasm-b"
+
+			// When
+			handler := sut.WasmRuntimeHandler()
+
+			// Then
+			Expect(handler).To(Equal("wasm-b"))
+		})
+	})
+
 	t.Describe("ValidateRuntimeConfigPath", func() {
 		It("should fail with OCI runtime type when runtime_config_path is used", func() {
 			// Given
@@ -1936,6 +2716,229 @@ var _ = t.Describe("Config", func() {
 			// Then
 			Expect(ok).To(BeTrue())
 		})
+
+		It("should not return features if they are not loaded", func() {
+			// Given
+			handler := &config.RuntimeHandler{}
+
+			// When
+			res := handler.RuntimeFeatures()
+
+			// Then
+			Expect(res).To(BeNil())
+		})
+
+		It("should succeed to validate any spec if features are not loaded", func() {
+			// Given
+			handler := &config.RuntimeHandler{}
+			spec := &rspec.Spec{
+				Mounts: []rspec.Mount{{Destination: "/mnt", Options: []string{"rro"}}},
+			}
+
+			// When
+			err := handler.ValidateRuntimeFeatures(spec)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should select the canary runtime path by percentage", func() {
+			// Given
+			handler := &config.RuntimeHandler{CanaryRuntimePath: validFilePath}
+
+			// When
+			handler.CanaryPercentage = 0
+
+			// Then
+			Expect(handler.RuntimeCanaryPath()).To(BeEmpty())
+
+			// When
+			handler.CanaryPercentage = 100
+
+			// Then
+			Expect(handler.RuntimeCanaryPath()).To(Equal(validFilePath))
+		})
+
+		It("should return the fallback runtime handler for matching errors", func() {
+			// Given
+			handler := &config.RuntimeHandler{
+				FallbackRuntimeHandler: "fallback",
+				FallbackErrors:         []string{"^unknown version", "exec format error"},
+			}
+			Expect(handler.ValidateRuntimeFallbackErrors("runc")).To(Succeed())
+
+			// When
+			// Then
+			Expect(handler.RuntimeFallbackHandler(errors.New("unknown version 2"))).To(Equal("fallback"))
+			Expect(handler.RuntimeFallbackHandler(errors.New("fork/exec: exec format error"))).To(Equal("fallback"))
+			Expect(handler.RuntimeFallbackHandler(errors.New("no space left on device"))).To(BeEmpty())
+
+			// When
+			handler.FallbackErrors = nil
+
+			// Then
+			Expect(handler.RuntimeFallbackHandler(errors.New("no space left on device"))).To(Equal("fallback"))
+		})
+
+		t.Describe("ValidateRuntimeFeatures", func() {
+			var handler *config.RuntimeHandler
+
+			BeforeEach(func() {
+				handler = &config.RuntimeHandler{}
+				Expect(handler.LoadRuntimeFeatures(
+					[]byte(`
+						{
+						  "ociVersionMin": "1.0.0",
+						  "ociVersionMax": "1.2.0",
+						  "mountOptions": ["ro", "rbind"],
+						  "linux": {
+						    "namespaces": ["mount", "network", "pid"],
+						    "capabilities": ["CAP_CHOWN", "CAP_KILL"],
+						    "seccomp": {
+						      "enabled": true,
+						      "actions": ["SCMP_ACT_ALLOW", "SCMP_ACT_ERRNO"],
+						      "archs": ["SCMP_ARCH_X86_64"],
+						      "knownFlags": ["SECCOMP_FILTER_FLAG_LOG"],
+						      "supportedFlags": []
+						    }
+						  }
+						}
+					`),
+				)).To(Succeed())
+			})
+
+			It("should return the loaded features", func() {
+				// Given
+				// When
+				res := handler.RuntimeFeatures()
+
+				// Then
+				Expect(res).NotTo(BeNil())
+				Expect(res.MountOptions).To(ConsistOf("ro", "rbind"))
+			})
+
+			It("should succeed with a supported spec", func() {
+				// Given
+				spec := &rspec.Spec{
+					Mounts:  []rspec.Mount{{Destination: "/mnt", Options: []string{"rbind", "ro", "size=65536k"}}},
+					Process: &rspec.Process{Capabilities: &rspec.LinuxCapabilities{Bounding: []string{"CAP_CHOWN", "CAP_KILL"}}},
+					Linux: &rspec.Linux{
+						Namespaces: []rspec.LinuxNamespace{{Type: rspec.MountNamespace}},
+						Seccomp: &rspec.LinuxSeccomp{
+							DefaultAction: rspec.ActErrno,
+							Architectures: []rspec.Arch{rspec.ArchX86_64},
+							Syscalls:      []rspec.LinuxSyscall{{Names: []string{"read"}, Action: rspec.ActAllow}},
+						},
+					},
+				}
+
+				// When
+				err := handler.ValidateRuntimeFeatures(spec)
+
+				// Then
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should fail with an unsupported runtime mount option", func() {
+				// Given
+				spec := &rspec.Spec{
+					Mounts: []rspec.Mount{{Destination: "/mnt", Options: []string{"rro"}}},
+				}
+
+				// When
+				err := handler.ValidateRuntimeFeatures(spec)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail with an unsupported ID-mapped mount", func() {
+				// Given
+				spec := &rspec.Spec{
+					Mounts: []rspec.Mount{{
+						Destination: "/mnt",
+						UIDMappings: []rspec.LinuxIDMapping{{ContainerID: 0, HostID: 1000, Size: 1}},
+					}},
+				}
+
+				// When
+				err := handler.ValidateRuntimeFeatures(spec)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail with an unsupported namespace", func() {
+				// Given
+				spec := &rspec.Spec{Linux: &rspec.Linux{
+					Namespaces: []rspec.LinuxNamespace{{Type: rspec.TimeNamespace}},
+				}}
+
+				// When
+				err := handler.ValidateRuntimeFeatures(spec)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail with an unsupported capability", func() {
+				// Given
+				spec := &rspec.Spec{
+					Process: &rspec.Process{Capabilities: &rspec.LinuxCapabilities{Effective: []string{"CAP_SYS_ADMIN"}}},
+				}
+
+				// When
+				err := handler.ValidateRuntimeFeatures(spec)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail with an unsupported seccomp action", func() {
+				// Given
+				spec := &rspec.Spec{Linux: &rspec.Linux{
+					Seccomp: &rspec.LinuxSeccomp{DefaultAction: rspec.ActNotify},
+				}}
+
+				// When
+				err := handler.ValidateRuntimeFeatures(spec)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail with an unsupported seccomp architecture", func() {
+				// Given
+				spec := &rspec.Spec{Linux: &rspec.Linux{
+					Seccomp: &rspec.LinuxSeccomp{
+						DefaultAction: rspec.ActAllow,
+						Architectures: []rspec.Arch{rspec.ArchAARCH64},
+					},
+				}}
+
+				// When
+				err := handler.ValidateRuntimeFeatures(spec)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should fail with an unsupported seccomp flag", func() {
+				// Given
+				spec := &rspec.Spec{Linux: &rspec.Linux{
+					Seccomp: &rspec.LinuxSeccomp{
+						DefaultAction: rspec.ActAllow,
+						Flags:         []rspec.LinuxSeccompFlag{rspec.LinuxSeccompFlagLog},
+					},
+				}}
+
+				// When
+				err := handler.ValidateRuntimeFeatures(spec)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+			})
+		})
 	})
 
 	t.Describe("ValidateContainerCreateTimeout", func() {
@@ -2079,6 +3082,53 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("should succeed with an adaptive collection period less than the collection period", func() {
+			// Given
+			sut.CollectionPeriod = 10
+			sut.AdaptiveCollectionPeriod = 2
+
+			// When
+			err := sut.StatsConfig.Validate()
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail with an adaptive collection period not less than the collection period", func() {
+			// Given
+			sut.CollectionPeriod = 10
+			sut.AdaptiveCollectionPeriod = 10
+
+			// When
+			err := sut.StatsConfig.Validate()
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with an adaptive collection period on-demand", func() {
+			// Given
+			sut.CollectionPeriod = 0
+			sut.AdaptiveCollectionPeriod = 2
+
+			// When
+			err := sut.StatsConfig.Validate()
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with a negative on-demand cache duration", func() {
+			// Given
+			sut.OnDemandCacheDuration = -time.Second
+
+			// When
+			err := sut.StatsConfig.Validate()
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should fail when all not in the first element", func() {
 			// Given
 			sut.IncludedPodMetrics = []string{"cpu", "memory", "all"}
@@ -26,4 +26,16 @@ const (
 	// that checks whether we've had time to sync before shutting down.
 	// If not, crio wipe will clear the storage directory.
 	CrioCleanShutdownFile = "/var/lib/crio/clean.shutdown"
+
+	// CrioUsernsAllocationsFile is the location CRI-O persists the user
+	// namespace ID ranges allocated for the pods.
+	CrioUsernsAllocationsFile = "/var/lib/crio/userns-allocations.json"
+
+	// CrioCPUAllocationsFile is the location CRI-O persists the exclusive
+	// CPUs allocated for the containers.
+	CrioCPUAllocationsFile = "/var/lib/crio/cpu-allocations.json"
+
+	// CrioDrainFile is the location of the file indicating that the node gets
+	// drained, which makes CRI-O checkpoint containers on shutdown.
+	CrioDrainFile = "/var/run/crio/drain"
 )
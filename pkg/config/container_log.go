@@ -0,0 +1,160 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	units "github.com/docker/go-units"
+)
+
+const (
+	// LogFormatCRI is the CRI text format of container logs.
+	LogFormatCRI = "cri"
+
+	// LogFormatJSON writes each line of the container output as JSON object
+	// containing the log, the stream and the time.
+	LogFormatJSON = "json"
+
+	// defaultLogRotateMaxFiles is the default number of rotated log files
+	// kept per container.
+	defaultLogRotateMaxFiles = 5
+)
+
+// ContainerLogRotation configures the rotation of container log files by
+// CRI-O, which reopens the container log after renaming the file.
+type ContainerLogRotation struct {
+	// LogRotateSize is the size, for example "50MiB", from which on the log
+	// file of a container gets rotated.
+	LogRotateSize string `toml:"log_rotate_size,omitempty"`
+
+	// LogRotateAge is the duration, for example "24h", after which the log
+	// file of a container gets rotated.
+	LogRotateAge string `toml:"log_rotate_age,omitempty"`
+
+	// LogRotateMaxFiles is the number of rotated log files kept per
+	// container, 5 if not set.
+	LogRotateMaxFiles int `toml:"log_rotate_max_files,omitempty"`
+
+	// LogRotateCompress enables the gzip compression of rotated log files.
+	LogRotateCompress bool `toml:"log_rotate_compress,omitempty"`
+
+	rotateSize int64
+	rotateAge  time.Duration
+}
+
+// Validate parses the size and the age of the log rotation.
+func (r *ContainerLogRotation) Validate() error {
+	if r.LogRotateSize != "" {
+		size, err := units.RAMInBytes(r.LogRotateSize)
+		if err != nil {
+			return fmt.Errorf("invalid log_rotate_size %q: %w", r.LogRotateSize, err)
+		}
+
+		if size < OCIBufSize {
+			return fmt.Errorf("log_rotate_size %q must be at least %d bytes", r.LogRotateSize, OCIBufSize)
+		}
+
+		r.rotateSize = size
+	}
+
+	if r.LogRotateAge != "" {
+		age, err := time.ParseDuration(r.LogRotateAge)
+		if err != nil {
+			return fmt.Errorf("invalid log_rotate_age %q: %w", r.LogRotateAge, err)
+		}
+
+		if age < time.Minute {
+			return fmt.Errorf("log_rotate_age %q must be at least one minute", r.LogRotateAge)
+		}
+
+		r.rotateAge = age
+	}
+
+	if r.LogRotateMaxFiles < 0 {
+		return errors.New("log_rotate_max_files must not be negative")
+	}
+
+	return nil
+}
+
+// Enabled returns whether container logs get rotated by size or age.
+func (r *ContainerLogRotation) Enabled() bool {
+	return r != nil && (r.rotateSize > 0 || r.rotateAge > 0)
+}
+
+// RotateSize returns the size in bytes from which on log files get rotated,
+// or zero if they are not rotated by size.
+func (r *ContainerLogRotation) RotateSize() int64 {
+	return r.rotateSize
+}
+
+// RotateAge returns the age after which log files get rotated, or zero if
+// they are not rotated by age.
+func (r *ContainerLogRotation) RotateAge() time.Duration {
+	return r.rotateAge
+}
+
+// RotateMaxFiles returns the number of rotated log files kept per container.
+func (r *ContainerLogRotation) RotateMaxFiles() int {
+	if r.LogRotateMaxFiles == 0 {
+		return defaultLogRotateMaxFiles
+	}
+
+	return r.LogRotateMaxFiles
+}
+
+// ValidateLogFormat checks if the `LogFormat` is supported by the
+// `RuntimeType`. conmon only writes the CRI format.
+func (r *RuntimeHandler) ValidateLogFormat(name string) error {
+	switch r.LogFormat {
+	case "", LogFormatCRI:
+		return nil
+	case LogFormatJSON:
+	default:
+		return fmt.Errorf("invalid log_format %q for runtime %q", r.LogFormat, name)
+	}
+
+	switch r.RuntimeType {
+	case RuntimeTypePod, RuntimeTypeVM, RuntimeTypeShimV2:
+		return nil
+	}
+
+	return fmt.Errorf("log_format %q is not supported by runtime type %q of runtime %q", r.LogFormat, r.RuntimeType, name)
+}
+
+// ContainerLogRotation returns the log rotation of the containers of the
+// runtime handler in the namespace. The log rotation of the runtime handler
+// takes precedence over the default of the namespace. It returns nil if the
+// container logs are not rotated by CRI-O.
+func (c *RuntimeConfig) ContainerLogRotation(handler, namespace string) *ContainerLogRotation {
+	if handler == "" {
+		handler = c.DefaultRuntime
+	}
+
+	if rh, ok := c.Runtimes[handler]; ok && rh.ContainerLogRotation.Enabled() {
+		return &rh.ContainerLogRotation
+	}
+
+	if rotation, ok := c.NamespaceLogRotation[namespace]; ok && rotation.Enabled() {
+		return rotation
+	}
+
+	return nil
+}
+
+// ValidateNamespaceLogRotation validates the log rotation defaults of the
+// namespaces.
+func (c *RuntimeConfig) ValidateNamespaceLogRotation() error {
+	for namespace, rotation := range c.NamespaceLogRotation {
+		if rotation == nil {
+			continue
+		}
+
+		if err := rotation.Validate(); err != nil {
+			return fmt.Errorf("namespace %q: %w", namespace, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Names of the built-in runtime handler hooks.
+const (
+	// HandlerHookHighPerformance are the hooks configuring CPU load balancing,
+	// CPU quota, IRQ load balancing, C-states and frequency governors of
+	// high-performance containers.
+	HandlerHookHighPerformance = "high-performance"
+
+	// HandlerHookCPULoadBalancing are the hooks enabling CPU load balancing
+	// of containers not disabling it.
+	HandlerHookCPULoadBalancing = "cpu-load-balancing"
+
+	// HandlerHookGOMAXPROCS are the hooks injecting GOMAXPROCS into burstable
+	// and best-effort containers.
+	HandlerHookGOMAXPROCS = "gomaxprocs"
+
+	// HandlerHookRuntimeTuning are the hooks injecting the environment
+	// variables of the runtime_tuning language runtimes into containers.
+	HandlerHookRuntimeTuning = "runtime-tuning"
+)
+
+// Language runtimes tuned by the runtime tuning hooks.
+const (
+	// RuntimeTuningJVM sets the active processor count and maximum heap
+	// percentage of the JVM using JAVA_TOOL_OPTIONS.
+	RuntimeTuningJVM = "jvm"
+
+	// RuntimeTuningNode sets UV_THREADPOOL_SIZE and the maximum old space
+	// size of Node.js using NODE_OPTIONS.
+	RuntimeTuningNode = "node"
+
+	// RuntimeTuningDotnet sets DOTNET_PROCESSOR_COUNT.
+	RuntimeTuningDotnet = "dotnet"
+)
+
+// Stages of the runtime handler hooks.
+const (
+	HookStagePreCreate = "pre-create"
+	HookStagePreStart  = "pre-start"
+	HookStagePreStop   = "pre-stop"
+	HookStagePostStop  = "post-stop"
+)
+
+// Failure policies of the exec hooks.
+const (
+	// HookFailurePolicyFail fails the container operation if the hook fails.
+	HookFailurePolicyFail = "fail"
+
+	// HookFailurePolicyIgnore logs failures of the hook and continues.
+	HookFailurePolicyIgnore = "ignore"
+)
+
+// defaultExecHookTimeout is the default timeout of an exec hook.
+const defaultExecHookTimeout = 10 * time.Second
+
+// builtinHandlerHooks are the names of the built-in runtime handler hooks.
+var builtinHandlerHooks = []string{
+	HandlerHookHighPerformance, HandlerHookCPULoadBalancing, HandlerHookGOMAXPROCS, HandlerHookRuntimeTuning,
+}
+
+// RuntimeTunings are all language runtimes supported by runtime_tuning.
+var RuntimeTunings = []string{RuntimeTuningJVM, RuntimeTuningNode, RuntimeTuningDotnet}
+
+// hookStages are all stages of the runtime handler hooks.
+var hookStages = []string{HookStagePreCreate, HookStagePreStart, HookStagePreStop, HookStagePostStop}
+
+// ExecHooks are the runtime handler hooks running external binaries by name.
+type ExecHooks map[string]*ExecHookConfig
+
+// ExecHookConfig is a runtime handler hook running an external binary. The
+// binary gets the stage, the container spec and the sandbox as JSON on stdin.
+type ExecHookConfig struct {
+	// Path is the absolute path of the binary.
+	Path string `toml:"path"`
+
+	// Args are the arguments passed to the binary.
+	Args []string `toml:"args,omitempty"`
+
+	// Env are the environment variables of the binary, in the form KEY=VALUE.
+	Env []string `toml:"env,omitempty"`
+
+	// Stages are the stages the hook runs at, all stages if empty.
+	Stages []string `toml:"stages,omitempty"`
+
+	// Timeout is the maximum duration of a single run of the binary.
+	Timeout time.Duration `toml:"timeout,omitempty"`
+
+	// FailurePolicy is either "fail" (the default) to fail the container
+	// operation if the binary fails, or "ignore" to only log the failure.
+	// Failures at the "pre-stop" and "post-stop" stages are always ignored.
+	FailurePolicy string `toml:"failure_policy,omitempty"`
+}
+
+// Validate checks the exec hooks and sets their defaults.
+func (e ExecHooks) Validate() error {
+	for name, hook := range e {
+		if slices.Contains(builtinHandlerHooks, name) {
+			return fmt.Errorf("exec hook %q conflicts with a built-in hook", name)
+		}
+
+		if err := hook.Validate(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the exec hook configuration and sets its defaults.
+func (h *ExecHookConfig) Validate(name string) error {
+	if !filepath.IsAbs(h.Path) {
+		return fmt.Errorf("path of exec hook %q must be absolute", name)
+	}
+
+	for _, stage := range h.Stages {
+		if !slices.Contains(hookStages, stage) {
+			return fmt.Errorf("invalid stage %q of exec hook %q", stage, name)
+		}
+	}
+
+	if h.Timeout < 0 {
+		return fmt.Errorf("timeout of exec hook %q cannot be negative", name)
+	}
+
+	if h.Timeout == 0 {
+		h.Timeout = defaultExecHookTimeout
+	}
+
+	switch h.FailurePolicy {
+	case "":
+		h.FailurePolicy = HookFailurePolicyFail
+	case HookFailurePolicyFail, HookFailurePolicyIgnore:
+	default:
+		return fmt.Errorf("invalid failure_policy %q of exec hook %q", h.FailurePolicy, name)
+	}
+
+	return nil
+}
+
+// RunsAt returns true if the hook runs at the stage.
+func (h *ExecHookConfig) RunsAt(stage string) bool {
+	return len(h.Stages) == 0 || slices.Contains(h.Stages, stage)
+}
+
+// ValidateHandlerHooks checks that the handler hooks of the runtime handler
+// are either built-in or configured exec hooks.
+func (r *RuntimeHandler) ValidateHandlerHooks(name string, execHooks ExecHooks) error {
+	for i, hook := range r.HandlerHooks {
+		if _, ok := execHooks[hook]; !ok && !slices.Contains(builtinHandlerHooks, hook) {
+			return fmt.Errorf("runtime handler %q references unknown handler hook %q", name, hook)
+		}
+
+		if slices.Contains(r.HandlerHooks[:i], hook) {
+			return fmt.Errorf("runtime handler %q references handler hook %q twice", name, hook)
+		}
+	}
+
+	return nil
+}
+
+// validateRuntimeTuning checks that the language runtimes are supported and
+// not listed twice.
+func validateRuntimeTuning(runtimes []string) error {
+	for i, runtime := range runtimes {
+		if !slices.Contains(RuntimeTunings, runtime) {
+			return fmt.Errorf("unsupported runtime_tuning language runtime %q, valid values are %v", runtime, RuntimeTunings)
+		}
+
+		if slices.Contains(runtimes[:i], runtime) {
+			return fmt.Errorf("runtime_tuning language runtime %q is listed twice", runtime)
+		}
+	}
+
+	return nil
+}
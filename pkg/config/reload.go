@@ -329,6 +329,10 @@ func (c *Config) ReloadRuntimes(newConfig *Config) error {
 	}
 
 	if !updated {
+		// The runtime binaries may have been updated in place, so their
+		// features have to be probed again.
+		c.initializeRuntimeFeatures()
+
 		return nil
 	}
 
@@ -8,6 +8,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	"go.podman.io/common/pkg/apparmor"
 
 	"github.com/cri-o/cri-o/pkg/config"
@@ -459,6 +460,49 @@ var _ = t.Describe("Config", func() {
 			Expect(sut.Runtimes).To(HaveKeyWithValue("new", newRuntime))
 			Expect(sut.Runtimes["new"].RuntimePath).To(Equal(existingRuntimePath))
 		})
+
+		It("should probe the runtime features again without any config change", func() {
+			// Given
+			dir := t.MustTempDir("runtime-features")
+			writeRuntime := func(name, mountOptions string) string {
+				runtimePath := filepath.Join(dir, name)
+				Expect(os.WriteFile(runtimePath, []byte(`#!/bin/sh
+if [ "$1" = "features" ]; then
+	echo '{"ociVersionMin": "1.0.0", "ociVersionMax": "1.1.0", "mountOptions": [`+mountOptions+`]}'
+fi
+`), 0o755)).To(Succeed())
+
+				return runtimePath
+			}
+			handler := &config.RuntimeHandler{
+				RuntimePath:       writeRuntime("runtime", `"rbind"`),
+				CanaryRuntimePath: writeRuntime("canary", `"rbind", "rnoexec"`),
+			}
+			sut.Runtimes["features"] = handler
+			spec := &rspec.Spec{
+				Mounts: []rspec.Mount{{Destination: "/mnt", Options: []string{"rbind", "rnoexec"}}},
+			}
+
+			// When
+			err := sut.ReloadRuntimes(sut)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(handler.RuntimeFeatures().MountOptions).To(ConsistOf("rbind"))
+			Expect(handler.ValidateRuntimePathFeatures(handler.RuntimePath, spec)).NotTo(Succeed())
+			Expect(handler.ValidateRuntimePathFeatures(handler.CanaryRuntimePath, spec)).To(Succeed())
+
+			// When
+			writeRuntime("runtime", `"rbind", "rnoexec"`)
+			writeRuntime("canary", `"rbind"`)
+			err = sut.ReloadRuntimes(sut)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(handler.RuntimeFeatures().MountOptions).To(ConsistOf("rbind", "rnoexec"))
+			Expect(handler.ValidateRuntimePathFeatures(handler.RuntimePath, spec)).To(Succeed())
+			Expect(handler.ValidateRuntimePathFeatures(handler.CanaryRuntimePath, spec)).NotTo(Succeed())
+		})
 	})
 
 	t.Describe("ReloadPinnedImages", func() {
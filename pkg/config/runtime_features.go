@@ -0,0 +1,193 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-spec/specs-go/features"
+)
+
+// runtimeMountOptions are the mount options which are interpreted by the
+// runtime handler instead of the kernel, and therefore are only supported if
+// advertised in its features.
+var runtimeMountOptions = []string{
+	"idmap", "ridmap",
+	"rro", "rrw",
+	"rnosuid", "rsuid",
+	"rnodev", "rdev",
+	"rnoexec", "rexec",
+	"rnodiratime", "rdiratime",
+	"rrelatime", "rnorelatime",
+	"rnoatime", "ratime",
+	"rstrictatime", "rnostrictatime",
+	"rnosymfollow", "rsymfollow",
+}
+
+// RuntimeFeatures returns the features advertised by the "features"
+// sub-command of the runtime handler, or nil if they are not available.
+func (r *RuntimeHandler) RuntimeFeatures() *features.Features {
+	if !r.runtimeFeaturesLoaded() {
+		return nil
+	}
+
+	f := r.features.Features
+
+	return &f
+}
+
+// runtimeFeaturesLoaded returns whether the features of the runtime handler
+// have been loaded successfully.
+func (r *RuntimeHandler) runtimeFeaturesLoaded() bool {
+	return r.features.loaded()
+}
+
+func (f *runtimeHandlerFeatures) loaded() bool {
+	return f.OCIVersionMin != "" && f.OCIVersionMax != ""
+}
+
+// ValidateRuntimeFeatures validates the mounts, namespaces, capabilities and
+// the seccomp profile of the spec against the features of the runtime handler. Runtime
+// handlers without known features are assumed to support everything.
+func (r *RuntimeHandler) ValidateRuntimeFeatures(spec *rspec.Spec) error {
+	return r.features.validate(spec)
+}
+
+// ValidateRuntimePathFeatures validates the spec like ValidateRuntimeFeatures,
+// but against the features of the runtime binary at runtimePath, which is
+// either the RuntimePath or the CanaryRuntimePath of the runtime handler. An
+// empty runtimePath refers to the RuntimePath. The features of other runtime
+// paths, such as the PlatformRuntimePaths, are unknown.
+func (r *RuntimeHandler) ValidateRuntimePathFeatures(runtimePath string, spec *rspec.Spec) error {
+	switch runtimePath {
+	case "", r.RuntimePath:
+		return r.features.validate(spec)
+	case r.CanaryRuntimePath:
+		return r.canaryFeatures.validate(spec)
+	default:
+		return nil
+	}
+}
+
+func (f *runtimeHandlerFeatures) validate(spec *rspec.Spec) error {
+	if spec == nil || !f.loaded() {
+		return nil
+	}
+
+	for i := range spec.Mounts {
+		if err := f.validateMountFeatures(&spec.Mounts[i]); err != nil {
+			return err
+		}
+	}
+
+	if spec.Process != nil {
+		if err := f.validateCapabilityFeatures(spec.Process.Capabilities); err != nil {
+			return err
+		}
+	}
+
+	if spec.Linux == nil {
+		return nil
+	}
+
+	if err := f.validateNamespaceFeatures(spec.Linux.Namespaces); err != nil {
+		return err
+	}
+
+	return f.validateSeccompFeatures(spec.Linux.Seccomp)
+}
+
+func (f *runtimeHandlerFeatures) validateMountFeatures(m *rspec.Mount) error {
+	for _, option := range m.Options {
+		if slices.Contains(runtimeMountOptions, option) && !f.supportsMountFlag(option) {
+			return fmt.Errorf("mount option %q of mount %s is not supported by the runtime handler", option, m.Destination)
+		}
+	}
+
+	if (len(m.UIDMappings) > 0 || len(m.GIDMappings) > 0) && !f.supportsIDMap() {
+		return fmt.Errorf("ID-mapped mount %s is not supported by the runtime handler", m.Destination)
+	}
+
+	return nil
+}
+
+func (f *runtimeHandlerFeatures) validateNamespaceFeatures(namespaces []rspec.LinuxNamespace) error {
+	if f.Linux == nil || f.Linux.Namespaces == nil {
+		return nil
+	}
+
+	for _, ns := range namespaces {
+		if !slices.Contains(f.Linux.Namespaces, string(ns.Type)) {
+			return fmt.Errorf("namespace %q is not supported by the runtime handler", ns.Type)
+		}
+	}
+
+	return nil
+}
+
+func (f *runtimeHandlerFeatures) validateCapabilityFeatures(capabilities *rspec.LinuxCapabilities) error {
+	if capabilities == nil || f.Linux == nil || f.Linux.Capabilities == nil {
+		return nil
+	}
+
+	for _, set := range [][]string{
+		capabilities.Bounding,
+		capabilities.Effective,
+		capabilities.Inheritable,
+		capabilities.Permitted,
+		capabilities.Ambient,
+	} {
+		for _, capability := range set {
+			if !slices.Contains(f.Linux.Capabilities, capability) {
+				return fmt.Errorf("capability %q is not supported by the runtime handler", capability)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *runtimeHandlerFeatures) validateSeccompFeatures(profile *rspec.LinuxSeccomp) error {
+	if profile == nil || f.Linux == nil || f.Linux.Seccomp == nil {
+		return nil
+	}
+
+	seccompFeatures := f.Linux.Seccomp
+
+	if seccompFeatures.Enabled != nil && !*seccompFeatures.Enabled {
+		return errors.New("seccomp is not supported by the runtime handler")
+	}
+
+	if len(seccompFeatures.Actions) > 0 {
+		actions := []rspec.LinuxSeccompAction{profile.DefaultAction}
+		for _, syscall := range profile.Syscalls {
+			actions = append(actions, syscall.Action)
+		}
+
+		for _, action := range actions {
+			if action != "" && !slices.Contains(seccompFeatures.Actions, string(action)) {
+				return fmt.Errorf("seccomp action %q is not supported by the runtime handler", action)
+			}
+		}
+	}
+
+	if len(seccompFeatures.Archs) > 0 {
+		for _, arch := range profile.Architectures {
+			if !slices.Contains(seccompFeatures.Archs, string(arch)) {
+				return fmt.Errorf("seccomp architecture %q is not supported by the runtime handler", arch)
+			}
+		}
+	}
+
+	// Runtime handlers knowing about flags report the supported subset.
+	if len(seccompFeatures.KnownFlags) > 0 {
+		for _, flag := range profile.Flags {
+			if !slices.Contains(seccompFeatures.SupportedFlags, string(flag)) {
+				return fmt.Errorf("seccomp flag %q is not supported by the runtime handler", flag)
+			}
+		}
+	}
+
+	return nil
+}
@@ -276,6 +276,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.MinInjectedGOMAXPROCS, c.MinInjectedGOMAXPROCS),
 		},
+		{
+			templateString: templateStringCrioRuntimeRuntimeTuning,
+			group:          crioRuntimeConfig,
+			isDefaultValue: slices.Equal(dc.RuntimeTuning, c.RuntimeTuning),
+		},
 		{
 			templateString: templateStringCrioRuntimeSelinux,
 			group:          crioRuntimeConfig,
@@ -441,6 +446,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.MinimumMappableGID, c.MinimumMappableGID),
 		},
+		{
+			templateString: templateStringCrioRuntimeUsernsAllocationsFile,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.UsernsAllocationsFile, c.UsernsAllocationsFile),
+		},
 		{
 			templateString: templateStringCrioRuntimeCtrStopTimeout,
 			group:          crioRuntimeConfig,
@@ -461,6 +471,16 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.SharedCPUSet, c.SharedCPUSet),
 		},
+		{
+			templateString: templateStringCrioRuntimeCPUAllocatorPool,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.CPUAllocatorPool, c.CPUAllocatorPool),
+		},
+		{
+			templateString: templateStringCrioRuntimeCPUAllocationsFile,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.CPUAllocationsFile, c.CPUAllocationsFile),
+		},
 		{
 			templateString: templateStringCrioRuntimeNamespacesDir,
 			group:          crioRuntimeConfig,
@@ -501,6 +521,41 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioCheckpointRestoreConfig,
 			isDefaultValue: simpleEqual(dc.ContainerLevelEnabled, c.ContainerLevelEnabled),
 		},
+		{
+			templateString: templateStringCrioCheckpointRestorePodLevelEnabled,
+			group:          crioCheckpointRestoreConfig,
+			isDefaultValue: simpleEqual(dc.PodLevelEnabled, c.PodLevelEnabled),
+		},
+		{
+			templateString: templateStringCrioCheckpointRestoreCompression,
+			group:          crioCheckpointRestoreConfig,
+			isDefaultValue: simpleEqual(dc.CheckpointRestoreConfig.Compression, c.CheckpointRestoreConfig.Compression),
+		},
+		{
+			templateString: templateStringCrioCheckpointRestorePreDumpIterations,
+			group:          crioCheckpointRestoreConfig,
+			isDefaultValue: simpleEqual(dc.PreDumpIterations, c.PreDumpIterations),
+		},
+		{
+			templateString: templateStringCrioCheckpointRestoreEncryptionKeysPath,
+			group:          crioCheckpointRestoreConfig,
+			isDefaultValue: simpleEqual(dc.EncryptionKeysPath, c.EncryptionKeysPath),
+		},
+		{
+			templateString: templateStringCrioCheckpointRestoreAutoCheckpointDirectory,
+			group:          crioCheckpointRestoreConfig,
+			isDefaultValue: simpleEqual(dc.AutoCheckpointDirectory, c.AutoCheckpointDirectory),
+		},
+		{
+			templateString: templateStringCrioCheckpointRestoreAutoCheckpointDrainFile,
+			group:          crioCheckpointRestoreConfig,
+			isDefaultValue: simpleEqual(dc.AutoCheckpointDrainFile, c.AutoCheckpointDrainFile),
+		},
+		{
+			templateString: templateStringCrioCheckpointRestoreAutoCheckpointMemoryPressure,
+			group:          crioCheckpointRestoreConfig,
+			isDefaultValue: simpleEqual(dc.AutoCheckpointMemoryPressure, c.AutoCheckpointMemoryPressure),
+		},
 		{
 			templateString: templateStringCrioRuntimeWorkloads,
 			group:          crioRuntimeConfig,
@@ -521,6 +576,21 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.Timezone, c.Timezone),
 		},
+		{
+			templateString: templateStringCrioRuntimeUsernsPools,
+			group:          crioRuntimeConfig,
+			isDefaultValue: UsernsPoolsEqual(dc.UsernsPools, c.UsernsPools),
+		},
+		{
+			templateString: templateStringCrioRuntimeExecHooks,
+			group:          crioRuntimeConfig,
+			isDefaultValue: ExecHooksEqual(dc.ExecHooks, c.ExecHooks),
+		},
+		{
+			templateString: templateStringCrioRuntimeNamespaceLogRotation,
+			group:          crioRuntimeConfig,
+			isDefaultValue: NamespaceLogRotationEqual(dc.NamespaceLogRotation, c.NamespaceLogRotation),
+		},
 		{
 			templateString: templateStringCrioImageDefaultTransport,
 			group:          crioImageConfig,
@@ -616,6 +686,46 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioNetworkConfig,
 			isDefaultValue: simpleEqual(dc.CNIStatusGracePeriod, c.CNIStatusGracePeriod),
 		},
+		{
+			templateString: templateStringCrioNetworkCNISetupTimeout,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.CNISetupTimeout, c.CNISetupTimeout),
+		},
+		{
+			templateString: templateStringCrioNetworkCNITeardownTimeout,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.CNITeardownTimeout, c.CNITeardownTimeout),
+		},
+		{
+			templateString: templateStringCrioNetworkCNITeardownRetries,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.CNITeardownRetries, c.CNITeardownRetries),
+		},
+		{
+			templateString: templateStringCrioNetworkCNIGCTimeout,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.CNIGCTimeout, c.CNIGCTimeout),
+		},
+		{
+			templateString: templateStringCrioNetworkDNSCacheAddress,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.DNSCacheAddress, c.DNSCacheAddress),
+		},
+		{
+			templateString: templateStringCrioNetworkDNSCacheUpstreams,
+			group:          crioNetworkConfig,
+			isDefaultValue: slices.Equal(dc.DNSCacheUpstreams, c.DNSCacheUpstreams),
+		},
+		{
+			templateString: templateStringCrioNetworkDNSCacheSize,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.DNSCacheSize, c.DNSCacheSize),
+		},
+		{
+			templateString: templateStringCrioNetworkDNSCacheMaxTTL,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.DNSCacheMaxTTL, c.DNSCacheMaxTTL),
+		},
 		{
 			templateString: templateStringCrioMetricsEnableMetrics,
 			group:          crioMetricsConfig,
@@ -676,6 +786,21 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioStatsConfig,
 			isDefaultValue: simpleEqual(dc.CollectionPeriod, c.CollectionPeriod),
 		},
+		{
+			templateString: templateStringCrioStatsOnDemandCacheDuration,
+			group:          crioStatsConfig,
+			isDefaultValue: simpleEqual(dc.OnDemandCacheDuration, c.OnDemandCacheDuration),
+		},
+		{
+			templateString: templateStringCrioStatsAdaptiveCollectionPeriod,
+			group:          crioStatsConfig,
+			isDefaultValue: simpleEqual(dc.AdaptiveCollectionPeriod, c.AdaptiveCollectionPeriod),
+		},
+		{
+			templateString: templateStringCrioStatsAdaptiveCollectionCPUThreshold,
+			group:          crioStatsConfig,
+			isDefaultValue: simpleEqual(dc.AdaptiveCollectionCPUThreshold, c.AdaptiveCollectionCPUThreshold),
+		},
 		{
 			templateString: templateStringCrioStatsIncludedPodMetrics,
 			group:          crioStatsConfig,
@@ -768,6 +893,63 @@ func WorkloadsEqual(a, b Workloads) bool {
 	return true
 }
 
+func UsernsPoolsEqual(a, b UsernsPools) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, valueA := range a {
+		valueB, ok := b[key]
+		if !ok {
+			return false
+		}
+
+		if !reflect.DeepEqual(valueA, valueB) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func ExecHooksEqual(a, b ExecHooks) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, valueA := range a {
+		valueB, ok := b[key]
+		if !ok {
+			return false
+		}
+
+		if !reflect.DeepEqual(valueA, valueB) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func NamespaceLogRotationEqual(a, b map[string]*ContainerLogRotation) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, valueA := range a {
+		valueB, ok := b[key]
+		if !ok {
+			return false
+		}
+
+		if !reflect.DeepEqual(valueA, valueB) {
+			return false
+		}
+	}
+
+	return true
+}
+
 const templateStringPrefix = `# The CRI-O configuration file specifies all of the available configuration
 # options and command-line flags for the crio(8) OCI Kubernetes Container Runtime
 # daemon, but in a TOML format that can be more easily modified and versioned.
@@ -1020,6 +1202,18 @@ const templateStringCrioRuntimeMinInjectedGOMAXPROCS = `# Enables GOMAXPROCS inj
 
 `
 
+const templateStringCrioRuntimeRuntimeTuning = `# Language runtimes tuned using environment variables derived from the CPU and
+# memory limits of the containers. Supported values are:
+# - "jvm": Sets -XX:ActiveProcessorCount and -XX:MaxRAMPercentage in JAVA_TOOL_OPTIONS.
+# - "node": Sets UV_THREADPOOL_SIZE and --max-old-space-size in NODE_OPTIONS.
+# - "dotnet": Sets DOTNET_PROCESSOR_COUNT.
+# Variables already set via the image or pod spec are kept. Pods can opt out
+# using the "skip-runtime-tuning.crio.io" annotation.
+{{ $.Comment }}runtime_tuning = [
+{{ range $runtime := .RuntimeTuning }}{{ $.Comment }}{{ printf "\t%q,\n" $runtime }}{{ end }}{{ $.Comment }}]
+
+`
+
 const templateStringCrioRuntimeSelinux = `# If true, SELinux will be used for pod separation on the host.
 # This option is deprecated, and be interpreted from whether SELinux is enabled on the host in the future.
 {{ $.Comment }}selinux = {{ .SELinux }}
@@ -1240,6 +1434,12 @@ const templateStringCrioRuntimeMinimumMappableGID = `# If set, CRI-O will reject
 
 `
 
+const templateStringCrioRuntimeUsernsAllocationsFile = `# Location CRI-O persists the user namespace ID ranges allocated from the
+# userns pools. The allocations are reconciled with the existing pods on startup.
+{{ $.Comment }}userns_allocations_file = "{{ .UsernsAllocationsFile }}"
+
+`
+
 const templateStringCrioRuntimeCtrStopTimeout = `# The minimal amount of time in seconds to wait before issuing a timeout
 # regarding the proper termination of the container. The lowest possible
 # value is 30s, whereas lower values are not considered by CRI-O.
@@ -1277,6 +1477,25 @@ const templateStringCrioRuntimeSharedCpuset = `# shared_cpuset  determines the C
 
 `
 
+const templateStringCrioRuntimeCPUAllocatorPool = `# cpu_allocator_pool determines the CPU set CRI-O allocates exclusive CPUs from
+# for containers of Guaranteed pods requesting an integer number of CPUs, if the
+# kubelet did not assign a cpuset (for example on nodes without the kubelet CPU
+# manager). CPUs are taken from a single NUMA node where possible and are used
+# by the high-performance runtime hooks like CPUs assigned by the kubelet.
+# Containers without exclusive CPUs and without a cpuset assigned by the kubelet
+# are restricted to the online CPUs outside of the pool, so the pool must not
+# contain all online CPUs. An empty value disables the allocator. You can
+# specify CPUs in the Linux CPU list format.
+{{ $.Comment }}cpu_allocator_pool = "{{ .CPUAllocatorPool }}"
+
+`
+
+const templateStringCrioRuntimeCPUAllocationsFile = `# Location CRI-O persists the exclusive CPUs allocated from the cpu_allocator_pool.
+# The allocations are reconciled with the existing containers on startup.
+{{ $.Comment }}cpu_allocations_file = "{{ .CPUAllocationsFile }}"
+
+`
+
 const templateStringCrioRuntimeNamespacesDir = `# The directory where the state of the managed namespaces gets tracked.
 # Only used when manage_ns_lifecycle is true.
 {{ $.Comment }}namespaces_dir = "{{ .NamespacesDir }}"
@@ -1342,14 +1561,26 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 # stream_websockets = false
 # seccomp_profile = ""
 # container_create_timeout = 240
+# canary_runtime_path = ""
+# canary_percentage = 0
+# fallback_runtime_handler = ""
+# fallback_errors = []
+# log_format = "cri"
+# log_rotate_size = ""
+# log_rotate_age = ""
+# log_rotate_max_files = 5
+# log_rotate_compress = false
 # Where:
 # - runtime-handler: Name used to identify the runtime.
 # - runtime_path (optional, string): Absolute path to the runtime executable in
 #   the host filesystem. If omitted, the runtime-handler identifier should match
 #   the runtime executable name, and the runtime executable should be placed
 #   in $PATH.
-# - runtime_type (optional, string): Type of runtime, one of: "oci", "vm". If
-#   omitted, an "oci" runtime is assumed.
+# - runtime_type (optional, string): Type of runtime, one of: "oci", "vm",
+#   "shimv2", "pod", "wasm". The "shimv2" type runs any containerd shim, like the
+#   runc or WebAssembly shims. The "wasm" type is an OCI runtime able to execute
+#   WebAssembly modules, which runs the containers of WebAssembly images in pods
+#   of "oci" runtime handlers. If omitted, an "oci" runtime is assumed.
 # - runtime_root (optional, string): Root directory for storage of containers
 #   state.
 # - runtime_config_path (optional, string): the path for the runtime configuration
@@ -1378,6 +1609,10 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 #     For images, the plain annotation "seccomp-profile.kubernetes.cri-o.io"
 #     can be used without the required "/POD" suffix or a container name.
 #   "io.kubernetes.cri-o.DisableFIPS" for disabling FIPS mode in a Kubernetes pod within a FIPS-enabled cluster.
+#   "restore-pod.crio.io" for restoring the containers of a pod from a pod checkpoint archive.
+#   "auto-checkpoint.crio.io" for checkpointing the containers of a pod automatically on node drain or memory pressure.
+#   "io-limits.crio.io" for setting per-device cgroup v2 io.max and io.weight limits of a container.
+#   "numa-memory-placement.crio.io" for restricting the memory nodes of a container to the NUMA nodes of its CPUs ("cpus") or of its CPUs and CDI devices ("cpus-and-devices").
 # - monitor_path (optional, string): The path of the monitor binary. Replaces
 #   deprecated option "conmon".
 # - monitor_cgroup (optional, string): The cgroup the container monitor process will be put in.
@@ -1410,6 +1645,30 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 #   adjusted to 30 seconds (the minimum allowed value). This allows different runtime handlers to have
 #   different container creation timeouts, which is useful for VM-based runtimes that may need longer
 #   timeouts than OCI runtimes.
+# - canary_runtime_path (optional, string): Absolute path to an alternative runtime executable, for example
+#   a new runtime version, used by "canary_percentage" percent of the new pods instead of "runtime_path".
+#   All containers of a pod use the same executable. This option is only valid for the 'oci' runtime type.
+# - canary_percentage (optional, int): The percentage (0-100) of new pods using the "canary_runtime_path".
+# - fallback_runtime_handler (optional, string): The name of the runtime handler used to retry creating a
+#   pod if the runtime of this handler failed to create its infra container. It does not apply to pods
+#   without an infra container if "drop_infra_ctr" is enabled.
+# - fallback_errors (optional, array of strings): Regular expressions matching the runtime errors which
+#   trigger the "fallback_runtime_handler". If empty, every runtime error does.
+# - log_format (optional, string): The format of the container logs, either "cri" or "json", which writes
+#   each line as JSON object with the "log", "stream" and "time" keys. The "json" format is only supported
+#   by the 'pod', 'vm' and 'shimv2' runtime types. Note that the kubelet only reads the "cri" format.
+# - log_rotate_size (optional, string): The size, for example "50MiB", from which on CRI-O rotates the log
+#   file of a container by renaming it and reopening the container log.
+# - log_rotate_age (optional, string): The duration, for example "24h", after which CRI-O rotates the log
+#   file of a container.
+# - log_rotate_max_files (optional, int): The number of rotated log files kept per container.
+# - log_rotate_compress (optional, bool): Compress rotated log files using gzip, except the newest one.
+#   The log rotation of the runtime handler takes precedence over the namespace_log_rotation defaults.
+#   The kubelet should not rotate the same logs, so its containerLogMaxSize should exceed log_rotate_size.
+# - handler_hooks (optional, array of strings): The ordered list of runtime handler hooks used for the
+#   containers of the handler, instead of the hooks chosen based on the pod annotations and configuration.
+#   Valid entries are the built-in hooks "high-performance", "cpu-load-balancing", "gomaxprocs" and "runtime-tuning", and
+#   the names of the exec_hooks. The hooks run in order at every stage, and the first failure stops the chain.
 #
 # Using the seccomp notifier feature:
 #
@@ -1454,7 +1713,19 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 {{ if $runtime_handler.AllowedAnnotations }}{{ $.Comment }}allowed_annotations = [
 {{ range $opt := $runtime_handler.AllowedAnnotations }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]{{ end }}
 {{ $.Comment }}privileged_without_host_devices = {{ $runtime_handler.PrivilegedWithoutHostDevices }}
-{{ if $runtime_handler.PlatformRuntimePaths }}platform_runtime_paths = {
+{{ if $runtime_handler.CanaryRuntimePath }}{{ $.Comment }}canary_runtime_path = "{{ $runtime_handler.CanaryRuntimePath }}"
+{{ $.Comment }}canary_percentage = {{ $runtime_handler.CanaryPercentage }}
+{{ end }}{{ if $runtime_handler.FallbackRuntimeHandler }}{{ $.Comment }}fallback_runtime_handler = "{{ $runtime_handler.FallbackRuntimeHandler }}"
+{{ end }}{{ if $runtime_handler.FallbackErrors }}{{ $.Comment }}fallback_errors = [
+{{ range $opt := $runtime_handler.FallbackErrors }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]
+{{ end }}{{ if $runtime_handler.LogFormat }}{{ $.Comment }}log_format = "{{ $runtime_handler.LogFormat }}"
+{{ end }}{{ if $runtime_handler.LogRotateSize }}{{ $.Comment }}log_rotate_size = "{{ $runtime_handler.LogRotateSize }}"
+{{ end }}{{ if $runtime_handler.LogRotateAge }}{{ $.Comment }}log_rotate_age = "{{ $runtime_handler.LogRotateAge }}"
+{{ end }}{{ if $runtime_handler.LogRotateMaxFiles }}{{ $.Comment }}log_rotate_max_files = {{ $runtime_handler.LogRotateMaxFiles }}
+{{ end }}{{ if $runtime_handler.LogRotateCompress }}{{ $.Comment }}log_rotate_compress = {{ $runtime_handler.LogRotateCompress }}
+{{ end }}{{ if $runtime_handler.HandlerHooks }}{{ $.Comment }}handler_hooks = [
+{{ range $opt := $runtime_handler.HandlerHooks }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]
+{{ end }}{{ if $runtime_handler.PlatformRuntimePaths }}platform_runtime_paths = {
 {{- $first := true }}{{- range $key, $value := $runtime_handler.PlatformRuntimePaths }}
 {{- if not $first }},{{ end }}{{- printf "%q = %q" $key $value }}{{- $first = false }}{{- end }}}
 {{ end }}
@@ -1472,6 +1743,21 @@ const templateStringCrioRuntimeWorkloads = `# The workloads table defines ways t
 # The currently supported resources are "cpuperiod" "cpuquota", "cpushares", "cpulimit" and "cpuset". The values for "cpuperiod" and "cpuquota" are denoted in microseconds.
 # The value for "cpulimit" is denoted in millicores, this value is used to calculate the "cpuquota" with the supplied "cpuperiod" or the default "cpuperiod".
 # Note that the "cpulimit" field overrides the "cpuquota" value supplied in this configuration.
+# The memory resources "memorylimit", "memoryreservation", "memoryswap" and "memoryhigh" are denoted in bytes,
+# where "memoryswap" is the limit of memory plus swap and "memoryhigh" the cgroup v2 memory.high throttling limit.
+# The "memoryhighratio" resource sets memory.high as a fraction of the memory limit instead, for example 0.8.
+# If "memoryhighpressurethreshold" is set, CRI-O relaxes memory.high towards the memory limit while the memory
+# pressure (PSI) of the container is above the threshold in percent, and tightens it back once the pressure eased.
+# The "memorymin" resource sets the cgroup v2 memory.min in bytes and "oomgroup" enables the cgroup v2
+# memory.oom.group, which kills all processes of the container on OOM.
+# The "pidslimit" resource limits the number of processes and "blkioweight" sets the blkio weight between 10 and 1000.
+# The "hugepagelimits" resource is a table of hugepage limits in bytes keyed by the page size, for example "2MB".
+# The "numamemoryplacement" resource restricts the cpuset.mems of a container to the NUMA nodes of its CPUs ("cpus")
+# or of its CPUs and CDI devices ("cpus-and-devices"), intersected with the memory nodes already requested by the kubelet.
+# The "iolimits" resource is a table of cgroup io.max limits ("rbps", "wbps", "riops" and "wiops") and io.weight ("weight")
+# keyed by the block device, which is either a device path, its "major:minor" numbers or "graphroot" for the device backing
+# the container storage root. Partitions are resolved to their disk, and devices missing on the host or not backing the storage
+# root fail the container creation. The limits are skipped with a warning if the storage root is not on a block device.
 # Each resource can have a default value specified, or be empty.
 # For a container to opt-into this workload, the pod should be configured with the annotation $activation_annotation (key only, value is ignored).
 # To customize per-container, an annotation of the form $annotation_prefix.$resource/$ctrName = "value" can be specified
@@ -1487,6 +1773,10 @@ const templateStringCrioRuntimeWorkloads = `# The workloads table defines ways t
 # cpuquota = "1000"
 # cpuperiod = "100000"
 # cpulimit = "35"
+# memorylimit = 1073741824
+# pidslimit = 1024
+# [crio.runtime.workloads.workload-type.resources.hugepagelimits]
+# "2MB" = 4194304
 # Where:
 # The workload name is workload-type.
 # To specify, the pod must have the "io.crio.workload" annotation (this is a precise string match).
@@ -1503,7 +1793,99 @@ const templateStringCrioRuntimeWorkloads = `# The workloads table defines ways t
 {{ $.Comment }}cpuquota = {{ $workload_config.Resources.CPUQuota }}
 {{ $.Comment }}cpuperiod = {{ $workload_config.Resources.CPUPeriod }}
 {{ $.Comment }}cpushares = {{ $workload_config.Resources.CPUShares }}
-{{ $.Comment }}cpulimit = {{ $workload_config.Resources.CPULimit }}{{ end }}
+{{ $.Comment }}cpulimit = {{ $workload_config.Resources.CPULimit }}
+{{ $.Comment }}memorylimit = {{ $workload_config.Resources.MemoryLimit }}
+{{ $.Comment }}memoryreservation = {{ $workload_config.Resources.MemoryReservation }}
+{{ $.Comment }}memoryswap = {{ $workload_config.Resources.MemorySwap }}
+{{ $.Comment }}memoryhigh = {{ $workload_config.Resources.MemoryHigh }}
+{{ $.Comment }}pidslimit = {{ $workload_config.Resources.PidsLimit }}
+{{ $.Comment }}blkioweight = {{ $workload_config.Resources.BlkioWeight }}
+{{ $.Comment }}memoryhighratio = {{ $workload_config.Resources.MemoryHighRatio }}
+{{ $.Comment }}memoryhighpressurethreshold = {{ $workload_config.Resources.MemoryHighPressureThreshold }}
+{{ $.Comment }}memorymin = {{ $workload_config.Resources.MemoryMin }}{{ with $workload_config.Resources.NUMAMemoryPlacement }}
+{{ $.Comment }}numamemoryplacement = "{{ . }}"{{ end }}{{ with $workload_config.Resources.OOMGroup }}
+{{ $.Comment }}oomgroup = {{ . }}{{ end }}{{ if $workload_config.Resources.HugepageLimits }}
+{{ $.Comment }}[crio.runtime.workloads.{{ $workload_type }}.resources.hugepagelimits]{{ range $page_size, $limit := $workload_config.Resources.HugepageLimits }}
+{{ $.Comment }}"{{ $page_size }}" = {{ $limit }}{{ end }}{{ end }}{{ range $device, $limit := $workload_config.Resources.IOLimits }}
+{{ $.Comment }}[crio.runtime.workloads.{{ $workload_type }}.resources.iolimits.{{ printf "%q" $device }}]
+{{ $.Comment }}rbps = {{ $limit.ReadBps }}
+{{ $.Comment }}wbps = {{ $limit.WriteBps }}
+{{ $.Comment }}riops = {{ $limit.ReadIOPS }}
+{{ $.Comment }}wiops = {{ $limit.WriteIOPS }}
+{{ $.Comment }}weight = {{ $limit.Weight }}{{ end }}{{ end }}
+{{ end }}
+`
+
+const templateStringCrioRuntimeUsernsPools = `# The userns_pools table defines ranges of host IDs CRI-O allocates the user
+# namespaces of pods from, instead of relying on the automatic allocation of
+# containers/storage. A pool is used for pods requesting the "auto" user
+# namespace mode without additional mapping options, and the same host IDs are
+# used for UIDs and GIDs. Container IDs are mapped one to one to the allocated
+# range, so the user and groups of the pod have to be within its size. Pools
+# must not overlap with the subordinate IDs containers/storage picks the user
+# namespaces of other "auto" mode pods from.
+# A pool can be restricted to runtime handlers and Kubernetes namespaces, where
+# the most specific matching pool takes precedence. The allocations can be inspected using "crio status userns".
+# Example:
+# [crio.runtime.userns_pools.isolated]
+# host_id = 200000
+# size = 6553600
+# runtime_handlers = ["kata"]
+# namespaces = ["untrusted"]
+{{ range $pool_name, $pool_config := .UsernsPools }}
+{{ $.Comment }}[crio.runtime.userns_pools.{{ $pool_name }}]
+{{ $.Comment }}host_id = {{ $pool_config.HostID }}
+{{ $.Comment }}size = {{ $pool_config.Size }}
+{{ $.Comment }}runtime_handlers = [{{ range $i, $h := $pool_config.RuntimeHandlers }}{{ if $i }}, {{ end }}{{ printf "%q" $h }}{{ end }}]
+{{ $.Comment }}namespaces = [{{ range $i, $n := $pool_config.Namespaces }}{{ if $i }}, {{ end }}{{ printf "%q" $n }}{{ end }}]
+{{ end }}
+`
+
+const templateStringCrioRuntimeExecHooks = `# The exec_hooks table defines runtime handler hooks running an external binary,
+# which can be referenced by name in the handler_hooks of the runtime handlers.
+# The binary gets a JSON object with the "stage", the "container_id", the OCI
+# runtime "spec" of the container and the "sandbox" on stdin. It runs at the
+# "pre-create", "pre-start", "pre-stop" and "post-stop" stages, or at the
+# configured stages only, and is killed after the timeout (default "10s").
+# If the binary fails at the "pre-create" or "pre-start" stage, the container
+# operation fails as well, unless the failure_policy is "ignore". Failures at
+# the stop stages are only logged. The binary only gets the configured env.
+# Example:
+# [crio.runtime.exec_hooks.numa-report]
+# path = "/usr/local/bin/numa-report"
+# args = ["--verbose"]
+# env = ["LOG_LEVEL=debug"]
+# stages = ["pre-start", "post-stop"]
+# timeout = "5s"
+# failure_policy = "ignore"
+{{ range $hook_name, $hook_config := .ExecHooks }}
+{{ $.Comment }}[crio.runtime.exec_hooks.{{ $hook_name }}]
+{{ $.Comment }}path = "{{ $hook_config.Path }}"
+{{ $.Comment }}args = [{{ range $i, $a := $hook_config.Args }}{{ if $i }}, {{ end }}{{ printf "%q" $a }}{{ end }}]
+{{ $.Comment }}env = [{{ range $i, $e := $hook_config.Env }}{{ if $i }}, {{ end }}{{ printf "%q" $e }}{{ end }}]
+{{ $.Comment }}stages = [{{ range $i, $s := $hook_config.Stages }}{{ if $i }}, {{ end }}{{ printf "%q" $s }}{{ end }}]
+{{ $.Comment }}timeout = "{{ $hook_config.Timeout }}"
+{{ $.Comment }}failure_policy = "{{ $hook_config.FailurePolicy }}"
+{{ end }}
+`
+
+const templateStringCrioRuntimeNamespaceLogRotation = `# The namespace_log_rotation table defines the defaults for the rotation of
+# container logs by CRI-O per Kubernetes namespace, which apply if the runtime
+# handler of the container does not rotate logs itself. The log file of a
+# container is rotated once it reaches log_rotate_size or is older than
+# log_rotate_age, by renaming it and reopening the container log.
+# Example:
+# [crio.runtime.namespace_log_rotation.kube-system]
+# log_rotate_size = "50MiB"
+# log_rotate_age = "24h"
+# log_rotate_max_files = 5
+# log_rotate_compress = true
+{{ range $namespace, $rotation := .NamespaceLogRotation }}
+{{ $.Comment }}[crio.runtime.namespace_log_rotation.{{ $namespace }}]
+{{ $.Comment }}log_rotate_size = "{{ $rotation.LogRotateSize }}"
+{{ $.Comment }}log_rotate_age = "{{ $rotation.LogRotateAge }}"
+{{ $.Comment }}log_rotate_max_files = {{ $rotation.LogRotateMaxFiles }}
+{{ $.Comment }}log_rotate_compress = {{ $rotation.LogRotateCompress }}
 {{ end }}
 `
 
@@ -1542,6 +1924,68 @@ const templateStringCrioCheckpointRestoreContainerLevelEnabled = `# container_le
 
 `
 
+const templateStringCrioCheckpointRestorePodLevelEnabled = `# pod_level_enabled configures the level of pod checkpoint and restore (CRIU)
+# support, which checkpoints all running containers of a pod sandbox together
+# with its metadata and /dev/shm into one archive using the inspect endpoint
+# "/checkpoint/:id?location=<archive>". Pods are restored by creating a new pod
+# sandbox with the "restore-pod.crio.io" annotation set to the archive path,
+# which has to be allowed for the runtime handler or workload.
+# It accepts the same values as container_level_enabled.
+{{ $.Comment }}pod_level_enabled = "{{ .CheckpointRestoreConfig.PodLevelEnabled }}"
+
+`
+
+const templateStringCrioCheckpointRestoreCompression = `# compression is the compression of checkpoint archives and of the layer of
+# checkpoint images. It is one of "none", "gzip" or "zstd". Restoring detects
+# the compression automatically.
+# Checkpoints are written as OCI image into the local containers-storage if the
+# checkpoint location starts with "containers-storage:", and are additionally
+# pushed to a registry if it starts with "docker://".
+{{ $.Comment }}compression = "{{ .CheckpointRestoreConfig.Compression }}"
+
+`
+
+const templateStringCrioCheckpointRestorePreDumpIterations = `# pre_dump_iterations is the number of CRIU pre-dump iterations (0-10) copying
+# the memory of a running container before it gets paused for the final dump,
+# which then only copies the memory pages changed since the last pre-dump. This
+# shortens the time large-memory containers are frozen. It requires memory
+# tracking support of the kernel, otherwise a single full dump is done.
+{{ $.Comment }}pre_dump_iterations = {{ .CheckpointRestoreConfig.PreDumpIterations }}
+
+`
+
+const templateStringCrioCheckpointRestoreEncryptionKeysPath = `# encryption_keys_path is the path of the directory containing the public keys
+# (JWE) and certificates (PKCS7) of the recipients for which checkpoint archives
+# and pushed checkpoint images get encrypted. Checkpoints are not encrypted if
+# it is empty. Encrypted checkpoints are decrypted on restore using the keys of
+# the decryption_keys_path.
+{{ $.Comment }}encryption_keys_path = "{{ .CheckpointRestoreConfig.EncryptionKeysPath }}"
+
+`
+
+const templateStringCrioCheckpointRestoreAutoCheckpointDirectory = `# auto_checkpoint_directory is the directory into which containers of pods with
+# the "auto-checkpoint.crio.io" annotation are checkpointed automatically. The
+# annotation is a comma separated list of the policies "drain" and
+# "memory-pressure". Automatic checkpoints are disabled if it is empty.
+{{ $.Comment }}auto_checkpoint_directory = "{{ .CheckpointRestoreConfig.AutoCheckpointDirectory }}"
+
+`
+
+const templateStringCrioCheckpointRestoreAutoCheckpointDrainFile = `# auto_checkpoint_drain_file is the file indicating that the node gets drained.
+# If it exists when CRI-O receives SIGTERM, the containers of pods with the
+# "drain" policy are checkpointed before shutting down.
+{{ $.Comment }}auto_checkpoint_drain_file = "{{ .CheckpointRestoreConfig.AutoCheckpointDrainFile }}"
+
+`
+
+const templateStringCrioCheckpointRestoreAutoCheckpointMemoryPressure = `# auto_checkpoint_memory_pressure is the memory pressure threshold in percent
+# (1-100). Containers of pods with the "memory-pressure" policy are checkpointed
+# once the share of time in which some of their tasks stalled on memory within
+# the last 10 seconds crosses it.
+{{ $.Comment }}auto_checkpoint_memory_pressure = {{ .CheckpointRestoreConfig.AutoCheckpointMemoryPressure }}
+
+`
+
 const templateStringCrioImage = `# The crio.image table contains settings pertaining to the management of OCI images.
 #
 # CRI-O reads its configured registries defaults from the system wide
@@ -1686,6 +2130,59 @@ const templateStringCrioNetworkCNIStatusGracePeriod = `# Enable continuous CNI S
 
 `
 
+const templateStringCrioNetworkCNISetupTimeout = `# Maximum duration of the CNI ADD and CHECK operations when setting up a pod
+# network. The timeout is added on top of the deadline of the RunPodSandbox
+# request, so that a slow but successful setup can still be reused by a retried
+# request.
+{{ $.Comment }}cni_setup_timeout = "{{ .CNISetupTimeout }}"
+
+`
+
+const templateStringCrioNetworkCNITeardownTimeout = `# Maximum duration of a single CNI DEL operation when tearing down a pod network.
+{{ $.Comment }}cni_teardown_timeout = "{{ .CNITeardownTimeout }}"
+
+`
+
+const templateStringCrioNetworkCNITeardownRetries = `# Number of retries for a failed CNI DEL operation, with an exponential backoff
+# starting at 500ms. The value must be between 0 and 5. Retries are skipped if
+# the pod network namespace does not exist any more, and stop at the deadline of
+# the request, like StopPodSandbox.
+{{ $.Comment }}cni_teardown_retries = {{ .CNITeardownRetries }}
+
+`
+
+const templateStringCrioNetworkCNIGCTimeout = `# Maximum duration of the CNI GC operation cleaning up resources of stale pods.
+{{ $.Comment }}cni_gc_timeout = "{{ .CNIGCTimeout }}"
+
+`
+
+const templateStringCrioNetworkDNSCacheAddress = `# Node local IP address of the caching DNS resolver for pods. The resolver
+# listens on port 53 of this address and the nameservers in the resolv.conf of
+# non host network pods are replaced by it. The replaced nameservers are used as
+# upstreams for the queries of the pod. The address has to be reachable from the
+# pod network, for example a link-local address assigned to a dummy interface.
+# The resolver is disabled if empty.
+{{ $.Comment }}dns_cache_address = "{{ .DNSCacheAddress }}"
+
+`
+
+const templateStringCrioNetworkDNSCacheUpstreams = `# Upstream nameservers (IP or IP:port) of the DNS cache for pods without own
+# nameservers. Queries of unknown clients are dropped.
+{{ $.Comment }}dns_cache_upstreams = [
+{{ range $opt := .DNSCacheUpstreams }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]
+
+`
+
+const templateStringCrioNetworkDNSCacheSize = `# Maximum number of answers kept by the DNS cache.
+{{ $.Comment }}dns_cache_size = {{ .DNSCacheSize }}
+
+`
+
+const templateStringCrioNetworkDNSCacheMaxTTL = `# Maximum duration an answer is kept by the DNS cache, regardless of its TTL.
+{{ $.Comment }}dns_cache_max_ttl = "{{ .DNSCacheMaxTTL }}"
+
+`
+
 const templateStringCrioMetrics = `# A necessary configuration for Prometheus based metrics retrieval
 [crio.metrics]
 
@@ -1771,6 +2268,25 @@ const templateStringCrioStatsCollectionPeriod = `# The number of seconds between
 
 `
 
+const templateStringCrioStatsOnDemandCacheDuration = `# The duration for which stats collected on-demand, if collection_period is 0,
+# are reused by subsequent stats requests. If set to 0, every request collects the stats.
+{{ $.Comment }}on_demand_cache_duration = "{{ .OnDemandCacheDuration }}"
+
+`
+
+const templateStringCrioStatsAdaptiveCollectionPeriod = `# The number of seconds between collecting the stats of busy containers, whose
+# CPU usage is above adaptive_collection_cpu_threshold. It has to be less than
+# collection_period. If set to 0, all stats are collected every collection_period.
+{{ $.Comment }}adaptive_collection_period = {{ .AdaptiveCollectionPeriod }}
+
+`
+
+const templateStringCrioStatsAdaptiveCollectionCPUThreshold = `# The CPU usage in millicores above which containers are considered busy by the
+# adaptive stats collection.
+{{ $.Comment }}adaptive_collection_cpu_threshold = {{ .AdaptiveCollectionCPUThreshold }}
+
+`
+
 const templateStringCrioStatsIncludedPodMetrics = `# List of included pod metrics.
 # You can also specify "all" to include all available metrics. If you specify "all", it should be the only item in the list.
 {{ $.Comment }}included_pod_metrics = [
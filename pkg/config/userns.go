@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// UsernsPools are the user namespace ID pools by name.
+type UsernsPools map[string]*UsernsPoolConfig
+
+// UsernsPoolConfig is a range of host IDs CRI-O allocates the user namespaces
+// of pods from. The same host IDs are used for UIDs and GIDs.
+type UsernsPoolConfig struct {
+	// HostID is the first host ID of the pool.
+	HostID uint32 `toml:"host_id"`
+
+	// Size is the number of host IDs in the pool.
+	Size uint32 `toml:"size"`
+
+	// RuntimeHandlers restricts the pool to pods using one of the runtime
+	// handlers. The pool applies to all runtime handlers if empty.
+	RuntimeHandlers []string `toml:"runtime_handlers,omitempty"`
+
+	// Namespaces restricts the pool to pods in one of the Kubernetes
+	// namespaces. The pool applies to all namespaces if empty.
+	Namespaces []string `toml:"namespaces,omitempty"`
+}
+
+// Validate checks the pools for overlaps and unknown runtime handlers.
+func (u UsernsPools) Validate(c *RuntimeConfig) error {
+	names := make([]string, 0, len(u))
+	for name := range u {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for i, name := range names {
+		pool := u[name]
+		if err := pool.Validate(name, c); err != nil {
+			return err
+		}
+
+		for _, other := range names[:i] {
+			if pool.overlaps(u[other]) {
+				return fmt.Errorf("userns pools %q and %q overlap", other, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the pool configuration.
+func (p *UsernsPoolConfig) Validate(name string, c *RuntimeConfig) error {
+	if p.Size == 0 {
+		return fmt.Errorf("size of userns pool %q must be positive", name)
+	}
+
+	if uint64(p.HostID)+uint64(p.Size) > 1<<32 {
+		return fmt.Errorf("userns pool %q exceeds the maximum host ID", name)
+	}
+
+	if p.HostID == 0 {
+		return fmt.Errorf("userns pool %q must not contain host ID 0", name)
+	}
+
+	for _, minimum := range []int64{c.MinimumMappableUID, c.MinimumMappableGID} {
+		if minimum >= 0 && int64(p.HostID) < minimum {
+			return fmt.Errorf("userns pool %q starts below the minimum mappable ID %d", name, minimum)
+		}
+	}
+
+	for _, handler := range p.RuntimeHandlers {
+		if _, ok := c.Runtimes[handler]; !ok {
+			return fmt.Errorf("userns pool %q references unknown runtime handler %q", name, handler)
+		}
+	}
+
+	return nil
+}
+
+func (p *UsernsPoolConfig) overlaps(other *UsernsPoolConfig) bool {
+	return uint64(p.HostID) < uint64(other.HostID)+uint64(other.Size) &&
+		uint64(other.HostID) < uint64(p.HostID)+uint64(p.Size)
+}
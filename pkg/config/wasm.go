@@ -0,0 +1,24 @@
+package config
+
+import (
+	"maps"
+	"slices"
+)
+
+// WasmRuntimeHandler returns the name of the runtime handler of type "wasm"
+// which runs the containers of WebAssembly images. The default runtime is
+// preferred, otherwise the first one by name is used. An empty string is
+// returned if no such runtime handler is configured.
+func (c *RuntimeConfig) WasmRuntimeHandler() string {
+	if handler, ok := c.Runtimes[c.DefaultRuntime]; ok && handler.RuntimeType == RuntimeTypeWasm {
+		return c.DefaultRuntime
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Runtimes)) {
+		if c.Runtimes[name].RuntimeType == RuntimeTypeWasm {
+			return name
+		}
+	}
+
+	return ""
+}
@@ -2,12 +2,19 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	units "github.com/docker/go-units"
+
 	"github.com/opencontainers/runtime-tools/generate"
 	"github.com/sirupsen/logrus"
 	"k8s.io/utils/cpuset"
+
+	"github.com/cri-o/cri-o/internal/config/node"
 )
 
 const (
@@ -18,6 +25,23 @@ const (
 	// defined here:
 	// https://github.com/torvalds/linux/blob/cac03ac368fabff0122853de2422d4e17a32de08/kernel/sched/core.c#L10546
 	minQuotaPeriod = 1000
+	// The minimum and maximum blkio weight supported by the kernel.
+	minBlkioWeight = 10
+	maxBlkioWeight = 1000
+
+	// MemoryHighFile is the cgroup v2 file of the memory usage throttle limit.
+	MemoryHighFile = "memory.high"
+
+	// NUMAMemoryPlacementCPUs restricts the memory nodes of a container to
+	// the NUMA nodes of its CPUs.
+	NUMAMemoryPlacementCPUs = "cpus"
+	// NUMAMemoryPlacementCPUsAndDevices restricts the memory nodes of a
+	// container to the NUMA nodes of its CPUs and CDI devices.
+	NUMAMemoryPlacementCPUsAndDevices = "cpus-and-devices"
+
+	// IODeviceGraphRoot is the iolimits device resolved to the block device
+	// backing the storage root of the containers.
+	IODeviceGraphRoot = "graphroot"
 )
 
 type Workloads map[string]*WorkloadConfig
@@ -46,6 +70,19 @@ type WorkloadConfig struct {
 	// `cpuperiod`: configure cpu period for a given container
 	// `cpuset`: configure cpuset for a given container
 	// `cpulimit`: configure cpu quota in millicores for a given container, overrides the `cpuquota` field
+	// `memorylimit`: configure the memory limit in bytes for a given container
+	// `memoryreservation`: configure the memory reservation in bytes for a given container
+	// `memoryswap`: configure the memory plus swap limit in bytes for a given container
+	// `memoryhigh`: configure the cgroup v2 memory.high throttling limit in bytes for a given container
+	// `memoryhighratio`: configure the cgroup v2 memory.high as a fraction of the memory limit for a given container
+	// `memoryhighpressurethreshold`: configure the memory pressure in percent above which memory.high gets relaxed
+	// `memorymin`: configure the cgroup v2 memory.min protection in bytes for a given container
+	// `oomgroup`: configure the cgroup v2 memory.oom.group to kill the whole container on OOM
+	// `pidslimit`: configure the pids limit for a given container
+	// `hugepagelimits`: configure the hugepage limits in bytes per page size for a given container
+	// `blkioweight`: configure the blkio weight for a given container
+	// `iolimits`: configure the io.max bandwidth and IOPS limits and the io.weight per block device for a given container
+	// `numamemoryplacement`: restrict the memory nodes of a given container to the NUMA nodes of its CPUs ("cpus") or of its CPUs and CDI devices ("cpus-and-devices")
 	// The value of the map is the default value for that resource.
 	// If a container is configured to use this workload, and does not specify
 	// the annotation with the resource and value, the default value will apply.
@@ -67,6 +104,47 @@ type Resources struct {
 	CPUSet string `json:"cpuset,omitempty"`
 	// Specifies the CPU limit in millicores. This will be used to calculate the CPU quota.
 	CPULimit int64 `json:"cpulimit,omitempty"`
+	// Specifies the memory limit in bytes.
+	MemoryLimit int64 `json:"memorylimit,omitempty"`
+	// Specifies the memory reservation (soft limit) in bytes.
+	MemoryReservation int64 `json:"memoryreservation,omitempty"`
+	// Specifies the memory plus swap limit in bytes, -1 for unlimited swap.
+	MemorySwap int64 `json:"memoryswap,omitempty"`
+	// Specifies the memory usage throttle limit in bytes, only supported on cgroup v2.
+	MemoryHigh int64 `json:"memoryhigh,omitempty"`
+	// Specifies the memory usage throttle limit as a fraction of the memory limit, only supported on cgroup v2.
+	MemoryHighRatio float64 `json:"memoryhighratio,omitempty"`
+	// Specifies the memory pressure in percent above which CRI-O dynamically relaxes the memory usage throttle limit.
+	MemoryHighPressureThreshold float64 `json:"memoryhighpressurethreshold,omitempty"`
+	// Specifies the memory in bytes protected from reclaim, only supported on cgroup v2.
+	MemoryMin int64 `json:"memorymin,omitempty"`
+	// Specifies whether the whole container gets killed on OOM, only supported on cgroup v2.
+	OOMGroup *bool `json:"oomgroup,omitempty"`
+	// Specifies the maximum number of processes, -1 for unlimited.
+	PidsLimit int64 `json:"pidslimit,omitempty"`
+	// Specifies the hugepage limits in bytes, keyed by the page size, for example "2MB".
+	HugepageLimits map[string]uint64 `json:"hugepagelimits,omitempty"`
+	// Specifies the blkio weight, between 10 and 1000.
+	BlkioWeight uint16 `json:"blkioweight,omitempty"`
+	// Specifies the IO limits keyed by the block device, which is either a
+	// device path, its "major:minor" numbers or "graphroot".
+	IOLimits map[string]*IODeviceLimit `json:"iolimits,omitempty"`
+	// Specifies whether the memory nodes are restricted to the NUMA nodes of the CPUs ("cpus") or of the CPUs and CDI devices ("cpus-and-devices").
+	NUMAMemoryPlacement string `json:"numamemoryplacement,omitempty"`
+}
+
+// IODeviceLimit are the IO limits of a container for a single block device.
+type IODeviceLimit struct {
+	// Specifies the read bandwidth limit in bytes per second.
+	ReadBps uint64 `json:"rbps,omitempty" toml:"rbps,omitempty"`
+	// Specifies the write bandwidth limit in bytes per second.
+	WriteBps uint64 `json:"wbps,omitempty" toml:"wbps,omitempty"`
+	// Specifies the read limit in IO operations per second.
+	ReadIOPS uint64 `json:"riops,omitempty" toml:"riops,omitempty"`
+	// Specifies the write limit in IO operations per second.
+	WriteIOPS uint64 `json:"wiops,omitempty" toml:"wiops,omitempty"`
+	// Specifies the weight of the device, between 10 and 1000.
+	Weight uint16 `json:"weight,omitempty" toml:"weight,omitempty"`
 }
 
 func (w Workloads) Validate() error {
@@ -154,6 +232,17 @@ func (w Workloads) MutateSpecGivenAnnotations(ctrName string, specgen *generate.
 	return nil
 }
 
+// ContainerResources returns the resources of the workload activated by the
+// sandbox annotations for the container, or nil if no workload is activated.
+func (w Workloads) ContainerResources(ctrName string, sboxAnnotations map[string]string) (*Resources, error) {
+	workload := w.workloadGivenActivationAnnotation(sboxAnnotations)
+	if workload == nil {
+		return nil, nil
+	}
+
+	return resourcesFromAnnotation(workload.AnnotationPrefix, ctrName, sboxAnnotations, workload.Resources)
+}
+
 func (w Workloads) workloadGivenActivationAnnotation(sboxAnnotations map[string]string) *WorkloadConfig {
 	for _, wc := range w {
 		for annotation := range sboxAnnotations {
@@ -208,9 +297,120 @@ func resourcesFromAnnotation(prefix, ctrName string, allAnnotations map[string]s
 		resources.CPUQuota = milliCPUToQuota(resources.CPULimit, int64(resources.CPUPeriod))
 	}
 
+	if resources.MemoryLimit == 0 {
+		resources.MemoryLimit = defaultResources.MemoryLimit
+	}
+
+	if resources.MemoryReservation == 0 {
+		resources.MemoryReservation = defaultResources.MemoryReservation
+	}
+
+	if resources.MemorySwap == 0 {
+		resources.MemorySwap = defaultResources.MemorySwap
+	}
+
+	if resources.MemoryHigh == 0 {
+		resources.MemoryHigh = defaultResources.MemoryHigh
+	}
+
+	if resources.MemoryHighRatio == 0 {
+		resources.MemoryHighRatio = defaultResources.MemoryHighRatio
+	}
+
+	if resources.MemoryHighPressureThreshold == 0 {
+		resources.MemoryHighPressureThreshold = defaultResources.MemoryHighPressureThreshold
+	}
+
+	if resources.MemoryMin == 0 {
+		resources.MemoryMin = defaultResources.MemoryMin
+	}
+
+	if resources.OOMGroup == nil {
+		resources.OOMGroup = defaultResources.OOMGroup
+	}
+
+	if resources.PidsLimit == 0 {
+		resources.PidsLimit = defaultResources.PidsLimit
+	}
+
+	for pageSize, limit := range defaultResources.HugepageLimits {
+		if _, ok := resources.HugepageLimits[pageSize]; ok {
+			continue
+		}
+
+		if resources.HugepageLimits == nil {
+			resources.HugepageLimits = map[string]uint64{}
+		}
+
+		resources.HugepageLimits[pageSize] = limit
+	}
+
+	if resources.BlkioWeight == 0 {
+		resources.BlkioWeight = defaultResources.BlkioWeight
+	}
+
+	for device, limit := range defaultResources.IOLimits {
+		if _, ok := resources.IOLimits[device]; ok {
+			continue
+		}
+
+		if resources.IOLimits == nil {
+			resources.IOLimits = map[string]*IODeviceLimit{}
+		}
+
+		resources.IOLimits[device] = limit
+	}
+
+	if err := resources.validateLimits(); err != nil {
+		return nil, fmt.Errorf("invalid resources in annotation %s: %w", annotationKey, err)
+	}
+
 	return resources, nil
 }
 
+// ValidateIOLimits validates the devices and values of the IO limits. The
+// devices are resolved on the host when the container gets created.
+func ValidateIOLimits(limits map[string]*IODeviceLimit) error {
+	for device, limit := range limits {
+		if _, _, err := ParseIODevice(device); err != nil {
+			return err
+		}
+
+		if limit == nil {
+			return fmt.Errorf("iolimits of device %q cannot be empty", device)
+		}
+
+		if limit.Weight != 0 && (limit.Weight < minBlkioWeight || limit.Weight > maxBlkioWeight) {
+			return fmt.Errorf("iolimits weight of device %q has to be between 10 and 1000", device)
+		}
+	}
+
+	return nil
+}
+
+// ParseIODevice parses the "major:minor" numbers of an iolimits device. It
+// returns -1 for devices given by path or "graphroot", which have to be
+// resolved on the host.
+func ParseIODevice(device string) (major, minor int64, err error) {
+	if device == IODeviceGraphRoot || filepath.IsAbs(device) {
+		return -1, -1, nil
+	}
+
+	majorStr, minorStr, ok := strings.Cut(device, ":")
+	if ok {
+		major, err = strconv.ParseInt(majorStr, 10, 64)
+		if err == nil {
+			minor, err = strconv.ParseInt(minorStr, 10, 64)
+		}
+	}
+
+	if !ok || err != nil || major < 0 || minor < 0 {
+		return -1, -1, fmt.Errorf("iolimits device %q has to be %q, an absolute path or major:minor", device, IODeviceGraphRoot)
+	}
+
+	return major, minor, nil
+}
+
 // milliCPUToQuota converts milliCPU to CFS quota and period values.
 // Input parameters and resulting value is number of microseconds.
 func milliCPUToQuota(milliCPU, period int64) (quota int64) {
@@ -247,6 +447,95 @@ func (r *Resources) ValidateDefaults() error {
 		return fmt.Errorf("cpuperiod %d cannot be less than 1000 microseconds", r.CPUPeriod)
 	}
 
+	return r.validateLimits()
+}
+
+// validateLimits validates the memory, pids, hugepage and blkio resources,
+// which can be overridden by annotation as well.
+func (r *Resources) validateLimits() error {
+	if r.MemoryLimit < 0 {
+		return fmt.Errorf("memorylimit %d cannot be negative", r.MemoryLimit)
+	}
+
+	if r.MemoryReservation < 0 {
+		return fmt.Errorf("memoryreservation %d cannot be negative", r.MemoryReservation)
+	}
+
+	if r.MemoryHigh < 0 {
+		return fmt.Errorf("memoryhigh %d cannot be negative", r.MemoryHigh)
+	}
+
+	if r.MemoryMin < 0 {
+		return fmt.Errorf("memorymin %d cannot be negative", r.MemoryMin)
+	}
+
+	if r.MemoryHighRatio < 0 || r.MemoryHighRatio > 1 {
+		return fmt.Errorf("memoryhighratio %g has to be between 0 and 1", r.MemoryHighRatio)
+	}
+
+	if r.MemoryHighRatio != 0 && r.MemoryHigh != 0 {
+		return errors.New("memoryhighratio and memoryhigh cannot be used together")
+	}
+
+	if r.MemoryHighPressureThreshold < 0 || r.MemoryHighPressureThreshold > 100 {
+		return fmt.Errorf("memoryhighpressurethreshold %g has to be between 0 and 100", r.MemoryHighPressureThreshold)
+	}
+
+	if r.MemoryHighPressureThreshold != 0 && r.MemoryHigh == 0 && r.MemoryHighRatio == 0 {
+		return errors.New("memoryhighpressurethreshold requires memoryhigh or memoryhighratio")
+	}
+
+	if r.MemorySwap < -1 {
+		return fmt.Errorf("memoryswap %d cannot be less than -1", r.MemorySwap)
+	}
+
+	if r.MemoryLimit != 0 {
+		if r.MemoryReservation > r.MemoryLimit {
+			return fmt.Errorf("memoryreservation %d cannot be greater than memorylimit %d", r.MemoryReservation, r.MemoryLimit)
+		}
+
+		if r.MemoryHigh > r.MemoryLimit {
+			return fmt.Errorf("memoryhigh %d cannot be greater than memorylimit %d", r.MemoryHigh, r.MemoryLimit)
+		}
+
+		if r.MemoryMin > r.MemoryLimit {
+			return fmt.Errorf("memorymin %d cannot be greater than memorylimit %d", r.MemoryMin, r.MemoryLimit)
+		}
+
+		if r.MemorySwap > 0 && r.MemorySwap < r.MemoryLimit {
+			return fmt.Errorf("memoryswap %d cannot be less than memorylimit %d", r.MemorySwap, r.MemoryLimit)
+		}
+	}
+
+	if r.PidsLimit < -1 {
+		return fmt.Errorf("pidslimit %d cannot be less than -1", r.PidsLimit)
+	}
+
+	for pageSize := range r.HugepageLimits {
+		size, err := units.RAMInBytes(pageSize)
+		if err != nil {
+			return fmt.Errorf("unable to parse hugepage size %q: %w", pageSize, err)
+		}
+
+		if size <= 0 || size&(size-1) != 0 {
+			return fmt.Errorf("hugepage size %q is not a power of two", pageSize)
+		}
+	}
+
+	if r.BlkioWeight != 0 && (r.BlkioWeight < minBlkioWeight || r.BlkioWeight > maxBlkioWeight) {
+		return errors.New("blkioweight has to be between 10 and 1000")
+	}
+
+	if err := ValidateIOLimits(r.IOLimits); err != nil {
+		return err
+	}
+
+	switch r.NUMAMemoryPlacement {
+	case "", NUMAMemoryPlacementCPUs, NUMAMemoryPlacementCPUsAndDevices:
+	default:
+		return fmt.Errorf("numamemoryplacement %q has to be %q or %q", r.NUMAMemoryPlacement, NUMAMemoryPlacementCPUs, NUMAMemoryPlacementCPUsAndDevices)
+	}
+
 	return nil
 }
 
@@ -270,4 +559,77 @@ func (r *Resources) MutateSpec(specgen *generate.Generator) {
 	if r.CPUPeriod != 0 {
 		specgen.SetLinuxResourcesCPUPeriod(r.CPUPeriod)
 	}
+
+	r.mutateMemory(specgen)
+
+	if r.PidsLimit != 0 {
+		specgen.SetLinuxResourcesPidsLimit(r.PidsLimit)
+	}
+
+	for pageSize, limit := range r.HugepageLimits {
+		specgen.AddLinuxResourcesHugepageLimit(pageSize, limit)
+	}
+
+	if r.BlkioWeight != 0 {
+		specgen.SetLinuxResourcesBlockIOWeight(r.BlkioWeight)
+	}
+}
+
+func (r *Resources) mutateMemory(specgen *generate.Generator) {
+	if r.MemoryLimit != 0 {
+		specgen.SetLinuxResourcesMemoryLimit(r.MemoryLimit)
+
+		// The memory plus swap limit of the CRI request must not be below
+		// the overridden memory limit.
+		if r.MemorySwap == 0 {
+			if memory := specgen.Config.Linux.Resources.Memory; memory.Swap != nil && *memory.Swap > 0 && *memory.Swap < r.MemoryLimit {
+				specgen.SetLinuxResourcesMemorySwap(r.MemoryLimit)
+			}
+		}
+	}
+
+	if r.MemoryReservation != 0 {
+		specgen.SetLinuxResourcesMemoryReservation(r.MemoryReservation)
+	}
+
+	if r.MemorySwap != 0 {
+		specgen.SetLinuxResourcesMemorySwap(r.MemorySwap)
+	}
+
+	if r.MemoryHigh == 0 && r.MemoryHighRatio == 0 && r.MemoryMin == 0 && r.OOMGroup == nil {
+		return
+	}
+
+	if !node.CgroupIsV2() {
+		logrus.Warnf("Ignoring memoryhigh, memoryhighratio, memorymin and oomgroup of workload, which are only supported on cgroup v2")
+
+		return
+	}
+
+	memoryHigh := r.MemoryHigh
+
+	if r.MemoryHighRatio != 0 {
+		if memory := specgen.Config.Linux.Resources.Memory; memory != nil && memory.Limit != nil && *memory.Limit > 0 {
+			memoryHigh = int64(r.MemoryHighRatio * float64(*memory.Limit))
+		} else {
+			logrus.Debugf("Ignoring memoryhighratio %g of workload for container without memory limit", r.MemoryHighRatio)
+		}
+	}
+
+	if memoryHigh != 0 {
+		specgen.AddLinuxResourcesUnified(MemoryHighFile, strconv.FormatInt(memoryHigh, 10))
+	}
+
+	if r.MemoryMin != 0 {
+		specgen.AddLinuxResourcesUnified("memory.min", strconv.FormatInt(r.MemoryMin, 10))
+	}
+
+	if r.OOMGroup != nil {
+		oomGroup := "0"
+		if *r.OOMGroup {
+			oomGroup = "1"
+		}
+
+		specgen.AddLinuxResourcesUnified("memory.oom.group", oomGroup)
+	}
 }
@@ -6,6 +6,7 @@ import (
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
 
+	"github.com/cri-o/cri-o/internal/config/node"
 	"github.com/cri-o/cri-o/pkg/config"
 )
 
@@ -259,4 +260,310 @@ var _ = t.Describe("Workloads config", func() {
 			})
 		}
 	})
+
+	It("resources should fail to validate invalid limits", func() {
+		testCases := []struct {
+			description string
+			resources   config.Resources
+		}{
+			{
+				description: "when memorylimit is negative",
+				resources:   config.Resources{MemoryLimit: -1},
+			},
+			{
+				description: "when memoryreservation is greater than memorylimit",
+				resources:   config.Resources{MemoryLimit: 1024, MemoryReservation: 2048},
+			},
+			{
+				description: "when memoryhigh is greater than memorylimit",
+				resources:   config.Resources{MemoryLimit: 1024, MemoryHigh: 2048},
+			},
+			{
+				description: "when memoryswap is less than memorylimit",
+				resources:   config.Resources{MemoryLimit: 2048, MemorySwap: 1024},
+			},
+			{
+				description: "when pidslimit is less than -1",
+				resources:   config.Resources{PidsLimit: -2},
+			},
+			{
+				description: "when the hugepage size is invalid",
+				resources:   config.Resources{HugepageLimits: map[string]uint64{"3MB": 1024}},
+			},
+			{
+				description: "when blkioweight is out of range",
+				resources:   config.Resources{BlkioWeight: 5},
+			},
+			{
+				description: "when memoryhighratio is greater than 1",
+				resources:   config.Resources{MemoryHighRatio: 1.5},
+			},
+			{
+				description: "when memoryhighratio and memoryhigh are both set",
+				resources:   config.Resources{MemoryHighRatio: 0.8, MemoryHigh: 1024},
+			},
+			{
+				description: "when memoryhighpressurethreshold is set without memory.high",
+				resources:   config.Resources{MemoryHighPressureThreshold: 20},
+			},
+			{
+				description: "when memoryhighpressurethreshold is greater than 100",
+				resources:   config.Resources{MemoryHighRatio: 0.8, MemoryHighPressureThreshold: 120},
+			},
+			{
+				description: "when memorymin is greater than memorylimit",
+				resources:   config.Resources{MemoryLimit: 1024, MemoryMin: 2048},
+			},
+			{
+				description: "when numamemoryplacement is unknown",
+				resources:   config.Resources{NUMAMemoryPlacement: "devices"},
+			},
+			{
+				description: "when an iolimits device is invalid",
+				resources:   config.Resources{IOLimits: map[string]*config.IODeviceLimit{"sda": {ReadBps: 1024}}},
+			},
+			{
+				description: "when an iolimits weight is out of range",
+				resources:   config.Resources{IOLimits: map[string]*config.IODeviceLimit{"8:0": {Weight: 5}}},
+			},
+		}
+
+		for _, tc := range testCases {
+			By(tc.description, func() {
+				err := tc.resources.ValidateDefaults()
+				Expect(err).To(HaveOccurred())
+			})
+		}
+	})
+
+	It("resources should mutate the memory, pids, hugepage and blkio limits", func() {
+		// Given
+		resources := config.Resources{
+			MemoryLimit:       2048,
+			MemoryReservation: 1024,
+			PidsLimit:         100,
+			HugepageLimits:    map[string]uint64{"2MB": 4194304},
+			BlkioWeight:       500,
+		}
+		Expect(resources.ValidateDefaults()).To(Succeed())
+
+		g := &generate.Generator{
+			Config: &rspec.Spec{
+				Linux: &rspec.Linux{
+					Resources: &rspec.LinuxResources{
+						Memory: &rspec.LinuxMemory{
+							Limit: new(int64(1024)),
+							Swap:  new(int64(1024)),
+						},
+					},
+				},
+			},
+		}
+
+		// When
+		resources.MutateSpec(g)
+
+		// Then
+		Expect(g.Config.Linux.Resources.Memory.Limit).To(Equal(new(int64(2048))))
+		Expect(g.Config.Linux.Resources.Memory.Reservation).To(Equal(new(int64(1024))))
+		Expect(g.Config.Linux.Resources.Memory.Swap).To(Equal(new(int64(2048))))
+		Expect(g.Config.Linux.Resources.Pids.Limit).To(Equal(new(int64(100))))
+		Expect(g.Config.Linux.Resources.HugepageLimits).To(ConsistOf(rspec.LinuxHugepageLimit{Pagesize: "2MB", Limit: 4194304}))
+		Expect(g.Config.Linux.Resources.BlockIO.Weight).To(Equal(new(uint16(500))))
+	})
+
+	It("should merge the memory and hugepage limits of the annotation with the defaults", func() {
+		// Given
+		const (
+			containerName            = "limitbox"
+			resourceContainerPrefix  = "resources.workload.openshift.io"
+			workloadTargetAnnotation = "target.workload.openshift.io/management"
+		)
+
+		workloads := config.Workloads{
+			"management": &config.WorkloadConfig{
+				AnnotationPrefix:     resourceContainerPrefix,
+				ActivationAnnotation: workloadTargetAnnotation,
+				Resources: &config.Resources{
+					MemoryLimit:    4096,
+					PidsLimit:      100,
+					HugepageLimits: map[string]uint64{"2MB": 4194304, "1GB": 1073741824},
+				},
+			},
+		}
+		Expect(workloads.Validate()).To(Succeed())
+
+		annotations := map[string]string{
+			workloadTargetAnnotation:                      "",
+			resourceContainerPrefix + "/" + containerName: `{"memorylimit":8192,"hugepagelimits":{"2MB":8388608}}`,
+		}
+
+		g := &generate.Generator{
+			Config: &rspec.Spec{
+				Linux: &rspec.Linux{
+					Resources: &rspec.LinuxResources{},
+				},
+			},
+		}
+
+		// When
+		err := workloads.MutateSpecGivenAnnotations(containerName, g, annotations)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(g.Config.Linux.Resources.Memory.Limit).To(Equal(new(int64(8192))))
+		Expect(g.Config.Linux.Resources.Pids.Limit).To(Equal(new(int64(100))))
+		Expect(g.Config.Linux.Resources.HugepageLimits).To(ConsistOf(
+			rspec.LinuxHugepageLimit{Pagesize: "2MB", Limit: 8388608},
+			rspec.LinuxHugepageLimit{Pagesize: "1GB", Limit: 1073741824},
+		))
+	})
+
+	It("should fail to mutate the container spec on invalid annotation limits", func() {
+		// Given
+		const workloadTargetAnnotation = "target.workload.openshift.io/management"
+
+		workloads := config.Workloads{
+			"management": &config.WorkloadConfig{
+				AnnotationPrefix:     "resources.workload.openshift.io",
+				ActivationAnnotation: workloadTargetAnnotation,
+				Resources:            &config.Resources{MemoryLimit: 4096},
+			},
+		}
+
+		annotations := map[string]string{
+			workloadTargetAnnotation:                   "",
+			"resources.workload.openshift.io/limitbox": `{"memoryreservation":8192}`,
+		}
+
+		g := &generate.Generator{
+			Config: &rspec.Spec{
+				Linux: &rspec.Linux{
+					Resources: &rspec.LinuxResources{},
+				},
+			},
+		}
+
+		// When
+		err := workloads.MutateSpecGivenAnnotations("limitbox", g, annotations)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("resources should mutate memory.high, memory.min and memory.oom.group", func() {
+		if !node.CgroupIsV2() {
+			Skip("requires cgroup v2")
+		}
+
+		// Given
+		resources := config.Resources{
+			MemoryLimit:     1000,
+			MemoryHighRatio: 0.8,
+			MemoryMin:       200,
+			OOMGroup:        new(true),
+		}
+		Expect(resources.ValidateDefaults()).To(Succeed())
+
+		g := &generate.Generator{
+			Config: &rspec.Spec{
+				Linux: &rspec.Linux{
+					Resources: &rspec.LinuxResources{},
+				},
+			},
+		}
+
+		// When
+		resources.MutateSpec(g)
+
+		// Then
+		Expect(g.Config.Linux.Resources.Unified).To(Equal(map[string]string{
+			"memory.high":      "800",
+			"memory.min":       "200",
+			"memory.oom.group": "1",
+		}))
+	})
+
+	It("should return the container resources merged with the annotation", func() {
+		// Given
+		const workloadTargetAnnotation = "target.workload.openshift.io/management"
+
+		workloads := config.Workloads{
+			"management": &config.WorkloadConfig{
+				AnnotationPrefix:     "resources.workload.openshift.io",
+				ActivationAnnotation: workloadTargetAnnotation,
+				Resources: &config.Resources{
+					MemoryHighRatio: 0.8,
+					OOMGroup:        new(true),
+				},
+			},
+		}
+		Expect(workloads.Validate()).To(Succeed())
+
+		annotations := map[string]string{
+			workloadTargetAnnotation:                   "",
+			"resources.workload.openshift.io/limitbox": `{"memoryhighpressurethreshold":20,"oomgroup":false}`,
+		}
+
+		// When
+		resources, err := workloads.ContainerResources("limitbox", annotations)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources.MemoryHighRatio).To(Equal(0.8))
+		Expect(resources.MemoryHighPressureThreshold).To(Equal(20.0))
+		Expect(resources.OOMGroup).To(Equal(new(false)))
+	})
+
+	It("should merge the iolimits devices of the annotation with the defaults", func() {
+		// Given
+		const workloadTargetAnnotation = "target.workload.openshift.io/management"
+
+		workloads := config.Workloads{
+			"management": &config.WorkloadConfig{
+				AnnotationPrefix:     "resources.workload.openshift.io",
+				ActivationAnnotation: workloadTargetAnnotation,
+				Resources: &config.Resources{
+					IOLimits: map[string]*config.IODeviceLimit{
+						config.IODeviceGraphRoot: {ReadBps: 1024},
+						"8:16":                   {Weight: 100},
+					},
+				},
+			},
+		}
+		Expect(workloads.Validate()).To(Succeed())
+
+		annotations := map[string]string{
+			workloadTargetAnnotation:                   "",
+			"resources.workload.openshift.io/limitbox": `{"iolimits":{"graphroot":{"wiops":100},"/dev/sdc":{"weight":500}}}`,
+		}
+
+		// When
+		resources, err := workloads.ContainerResources("limitbox", annotations)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources.IOLimits).To(Equal(map[string]*config.IODeviceLimit{
+			config.IODeviceGraphRoot: {WriteIOPS: 100},
+			"8:16":                   {Weight: 100},
+			"/dev/sdc":               {Weight: 500},
+		}))
+	})
+
+	It("should return no container resources without activated workload", func() {
+		// Given
+		workloads := config.Workloads{
+			"management": &config.WorkloadConfig{
+				ActivationAnnotation: "target.workload.openshift.io/management",
+				Resources:            &config.Resources{},
+			},
+		}
+
+		// When
+		resources, err := workloads.ContainerResources("limitbox", map[string]string{})
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources).To(BeNil())
+	})
 })
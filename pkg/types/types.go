@@ -1,6 +1,7 @@
 package types
 
 import (
+	"github.com/opencontainers/runtime-spec/specs-go/features"
 	"go.podman.io/storage/pkg/idtools"
 )
 
@@ -19,6 +20,33 @@ type ContainerInfo struct {
 	Sandbox         string            `json:"sandbox"`
 	IPs             []string          `json:"ip_addresses"`
 	HostNetwork     *bool             `json:"host_network"`
+	NUMAPlacement   *NUMAPlacement    `json:"numa_placement,omitempty"`
+}
+
+// NUMAPlacement stores the NUMA memory placement chosen for a container.
+type NUMAPlacement struct {
+	Policy      string `json:"policy"`
+	CPUs        string `json:"cpus"`
+	MemoryNodes string `json:"memory_nodes"`
+}
+
+// PodAccounting stores the resource accounting of a pod without infra
+// container, whose pod overhead is only applied to the pod cgroup. CPU usage
+// is in nano cores and memory usage is the working set in bytes.
+type PodAccounting struct {
+	ContainersCPUUsageNanoCores      uint64 `json:"containers_cpu_usage_nano_cores"`
+	ContainersMemoryWorkingSetBytes  uint64 `json:"containers_memory_working_set_bytes"`
+	OverheadCPUUsageNanoCores        uint64 `json:"overhead_cpu_usage_nano_cores"`
+	OverheadMemoryWorkingSetBytes    uint64 `json:"overhead_memory_working_set_bytes"`
+	DeclaredOverheadCPUNanoCores     uint64 `json:"declared_overhead_cpu_nano_cores,omitempty"`
+	DeclaredOverheadMemoryLimitBytes uint64 `json:"declared_overhead_memory_limit_bytes,omitempty"`
+	OverheadExceeded                 bool   `json:"overhead_exceeded"`
+}
+
+// SandboxFreezerInfo stores the freezer state of a pod sandbox.
+type SandboxFreezerInfo struct {
+	ID     string `json:"id"`
+	Frozen bool   `json:"frozen"`
 }
 
 // IDMappings specifies the ID mappings used for containers.
@@ -35,3 +63,55 @@ type CrioInfo struct {
 	CgroupDriver      string     `json:"cgroup_driver"`
 	DefaultIDMappings IDMappings `json:"default_id_mappings"`
 }
+
+// UsernsIDRange is a range of host IDs used by a user namespace.
+type UsernsIDRange struct {
+	HostID uint32 `json:"host_id"`
+	Size   uint32 `json:"size"`
+}
+
+// UsernsPoolInfo stores information about a configured user namespace ID pool.
+type UsernsPoolInfo struct {
+	Name            string   `json:"name"`
+	HostID          uint32   `json:"host_id"`
+	Size            uint32   `json:"size"`
+	Allocated       uint64   `json:"allocated"`
+	RuntimeHandlers []string `json:"runtime_handlers,omitempty"`
+	Namespaces      []string `json:"namespaces,omitempty"`
+}
+
+// UsernsAllocation stores the host ID ranges used by the user namespace of a
+// pod sandbox.
+type UsernsAllocation struct {
+	SandboxID string          `json:"sandbox_id"`
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	Pool      string          `json:"pool,omitempty"` // Empty if the ranges have not been allocated by CRI-O.
+	Uids      []UsernsIDRange `json:"uids"`
+	Gids      []UsernsIDRange `json:"gids"`
+}
+
+// UsernsOverlap stores two pod sandboxes sharing host IDs.
+type UsernsOverlap struct {
+	SandboxIDs []string `json:"sandbox_ids"`
+	Uids       bool     `json:"uids"`
+	Gids       bool     `json:"gids"`
+}
+
+// UsernsInfo stores information about the user namespace ID allocations.
+type UsernsInfo struct {
+	Pools       []UsernsPoolInfo   `json:"pools"`
+	Allocations []UsernsAllocation `json:"allocations"`
+	Overlaps    []UsernsOverlap    `json:"overlaps"`
+}
+
+// RuntimeHandlerInfo stores information about a configured runtime handler.
+type RuntimeHandlerInfo struct {
+	Name                    string             `json:"name"`
+	Default                 bool               `json:"default"`
+	RuntimePath             string             `json:"runtime_path"`
+	RuntimeType             string             `json:"runtime_type"`
+	RecursiveReadOnlyMounts bool               `json:"recursive_read_only_mounts"`
+	UserNamespaces          bool               `json:"user_namespaces"`
+	Features                *features.Features `json:"features,omitempty"` // Nil if the runtime does not support the "features" sub-command.
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+
+	"github.com/cri-o/cri-o/internal/lib"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	v2 "github.com/cri-o/cri-o/pkg/annotations/v2"
+)
+
+const (
+	// autoCheckpointPolicyDrain checkpoints the containers of a pod when
+	// CRI-O is shut down while the node gets drained.
+	autoCheckpointPolicyDrain = "drain"
+
+	// autoCheckpointPolicyMemoryPressure checkpoints the containers of a pod
+	// when their memory pressure crosses the configured threshold.
+	autoCheckpointPolicyMemoryPressure = "memory-pressure"
+
+	// autoCheckpointInterval is the interval in which the memory pressure of
+	// the containers is checked, matching the window of the used average.
+	autoCheckpointInterval = 10 * time.Second
+)
+
+// autoCheckpointPolicies returns the automatic checkpoint policies of the
+// pod sandbox annotations.
+func autoCheckpointPolicies(annotations map[string]string) []string {
+	value, ok := v2.GetAnnotationValue(annotations, v2.AutoCheckpoint)
+	if !ok {
+		return nil
+	}
+
+	var policies []string
+
+	for policy := range strings.SplitSeq(value, ",") {
+		if policy = strings.TrimSpace(policy); policy != "" {
+			policies = append(policies, policy)
+		}
+	}
+
+	return policies
+}
+
+// autoCheckpointContainers returns the running containers of pod sandboxes
+// opting in for automatic checkpoints with the policy.
+func (s *Server) autoCheckpointContainers(policy string) []*oci.Container {
+	var containers []*oci.Container
+
+	for _, sb := range s.ContainerServer.ListSandboxes() {
+		if !slices.Contains(autoCheckpointPolicies(sb.Annotations()), policy) {
+			continue
+		}
+
+		for _, ctr := range sb.Containers().List() {
+			if ctr.State().Status == oci.ContainerStateRunning {
+				containers = append(containers, ctr)
+			}
+		}
+	}
+
+	return containers
+}
+
+// autoCheckpointContainer checkpoints the running container into the
+// automatic checkpoint directory, leaving it running.
+func (s *Server) autoCheckpointContainer(ctx context.Context, sb *sandbox.Sandbox, ctr *oci.Container, policy string) error {
+	encryptConfig, err := getEncryptionKeys(s.config.EncryptionKeysPath)
+	if err != nil {
+		return fmt.Errorf("get checkpoint encryption keys: %w", err)
+	}
+
+	target := filepath.Join(s.config.AutoCheckpointDirectory, fmt.Sprintf(
+		"%s_%s_%s-%s-%d.tar",
+		sb.Metadata().GetNamespace(),
+		sb.Metadata().GetName(),
+		ctr.Metadata().GetName(),
+		policy,
+		time.Now().Unix(),
+	))
+
+	log.Infof(ctx, "Automatically checkpointing container %s on %s to %s", ctr.ID(), policy, target)
+
+	if _, err := s.ContainerCheckpoint(ctx, &metadata.ContainerConfig{ID: ctr.ID()}, &lib.ContainerCheckpointOptions{
+		TargetFile:        target,
+		KeepRunning:       true,
+		Compression:       s.config.CheckpointRestoreConfig.Compression.Archive(),
+		PreDumpIterations: s.config.PreDumpIterations,
+		EncryptConfig:     encryptConfig,
+	}); err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "Automatically checkpointed container %s to %s", ctr.ID(), target)
+
+	return nil
+}
+
+// autoCheckpointEnabled returns whether automatic checkpoints are configured.
+func (s *Server) autoCheckpointEnabled() bool {
+	return s.config.AutoCheckpointDirectory != "" && s.config.CheckpointContainerEnabled()
+}
+
+// AutoCheckpointOnDrain checkpoints the containers of pod sandboxes with the
+// drain policy if the node gets drained. It is called when CRI-O receives
+// SIGTERM.
+func (s *Server) AutoCheckpointOnDrain(ctx context.Context) {
+	if !s.autoCheckpointEnabled() || s.config.AutoCheckpointDrainFile == "" {
+		return
+	}
+
+	if _, err := os.Stat(s.config.AutoCheckpointDrainFile); err != nil {
+		log.Debugf(ctx, "Skipping automatic checkpoints, node is not drained: %v", err)
+
+		return
+	}
+
+	for _, ctr := range s.autoCheckpointContainers(autoCheckpointPolicyDrain) {
+		sb := s.getSandbox(ctx, ctr.Sandbox())
+		if sb == nil {
+			continue
+		}
+
+		if err := s.autoCheckpointContainer(ctx, sb, ctr, autoCheckpointPolicyDrain); err != nil {
+			log.Errorf(ctx, "Unable to automatically checkpoint container %s: %v", ctr.ID(), err)
+		}
+	}
+}
+
+// startAutoCheckpointMonitor starts a routine checkpointing the containers of
+// pod sandboxes with the memory-pressure policy once their memory pressure
+// crosses the threshold.
+func (s *Server) startAutoCheckpointMonitor(ctx context.Context) {
+	if !s.autoCheckpointEnabled() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(autoCheckpointInterval)
+		defer ticker.Stop()
+
+		// A container is checkpointed again only after its memory
+		// pressure dropped below the threshold in between.
+		pressured := map[string]bool{}
+
+		for {
+			select {
+			case <-s.monitorsChan:
+				return
+			case <-ticker.C:
+				pressured = s.autoCheckpointOnMemoryPressure(ctx, pressured)
+			}
+		}
+	}()
+}
+
+// autoCheckpointOnMemoryPressure checkpoints the containers whose memory
+// pressure crossed the threshold and which are not part of the already
+// pressured containers. It returns the containers above the threshold.
+func (s *Server) autoCheckpointOnMemoryPressure(ctx context.Context, pressured map[string]bool) map[string]bool {
+	aboveThreshold := map[string]bool{}
+
+	for _, ctr := range s.autoCheckpointContainers(autoCheckpointPolicyMemoryPressure) {
+		sb := s.getSandbox(ctx, ctr.Sandbox())
+		if sb == nil {
+			continue
+		}
+
+		pressure, err := s.containerMemoryPressure(sb, ctr)
+		if err != nil {
+			log.Debugf(ctx, "Unable to get memory pressure of container %s: %v", ctr.ID(), err)
+
+			continue
+		}
+
+		if pressure < float64(s.config.AutoCheckpointMemoryPressure) {
+			continue
+		}
+
+		aboveThreshold[ctr.ID()] = true
+
+		if pressured[ctr.ID()] {
+			continue
+		}
+
+		log.Warnf(ctx, "Memory pressure of container %s is %.2f%%", ctr.ID(), pressure)
+
+		if err := s.autoCheckpointContainer(ctx, sb, ctr, autoCheckpointPolicyMemoryPressure); err != nil {
+			log.Errorf(ctx, "Unable to automatically checkpoint container %s: %v", ctr.ID(), err)
+		}
+	}
+
+	return aboveThreshold
+}
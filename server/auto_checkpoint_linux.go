@@ -0,0 +1,24 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/oci"
+)
+
+// containerMemoryPressure returns the share of time in percent in which some
+// tasks of the container stalled on memory within the last 10 seconds.
+func (s *Server) containerMemoryPressure(sb *sandbox.Sandbox, ctr *oci.Container) (float64, error) {
+	cgroupStats, err := s.config.CgroupManager().ContainerCgroupStats(sb.CgroupParent(), ctr.ID())
+	if err != nil {
+		return 0, err
+	}
+
+	psi := cgroupStats.MemoryStats.PSI
+	if psi == nil {
+		return 0, errors.New("memory pressure not available")
+	}
+
+	return psi.Some.Avg10, nil
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"slices"
+	"testing"
+
+	v2 "github.com/cri-o/cri-o/pkg/annotations/v2"
+)
+
+func TestAutoCheckpointPolicies(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		expected    []string
+	}{
+		{
+			name:        "no annotation",
+			annotations: map[string]string{},
+			expected:    nil,
+		},
+		{
+			name:        "single policy",
+			annotations: map[string]string{v2.AutoCheckpoint: autoCheckpointPolicyDrain},
+			expected:    []string{autoCheckpointPolicyDrain},
+		},
+		{
+			name:        "multiple policies with spaces",
+			annotations: map[string]string{v2.AutoCheckpoint: " drain, memory-pressure ,"},
+			expected:    []string{autoCheckpointPolicyDrain, autoCheckpointPolicyMemoryPressure},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if policies := autoCheckpointPolicies(tc.annotations); !slices.Equal(policies, tc.expected) {
+				t.Fatalf("Expected policies %v, got %v", tc.expected, policies)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package server
+
+import (
+	"errors"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/oci"
+)
+
+// containerMemoryPressure is not supported on this platform.
+func (s *Server) containerMemoryPressure(*sandbox.Sandbox, *oci.Container) (float64, error) {
+	return 0, errors.New("memory pressure not supported")
+}
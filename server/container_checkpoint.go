@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	metadata "github.com/checkpoint-restore/checkpointctl/lib"
 	"google.golang.org/grpc/codes"
@@ -24,6 +25,16 @@ func (s *Server) CheckpointContainer(ctx context.Context, req *types.CheckpointC
 		return nil, status.Errorf(codes.NotFound, "could not find container %q: %v", req.GetContainerId(), err)
 	}
 
+	imageName, push, err := lib.ParseCheckpointImageLocation(req.GetLocation())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	encryptConfig, err := getEncryptionKeys(s.config.EncryptionKeysPath)
+	if err != nil {
+		return nil, fmt.Errorf("get checkpoint encryption keys: %w", err)
+	}
+
 	log.Infof(ctx, "Checkpointing container: %s", req.GetContainerId())
 	config := &metadata.ContainerConfig{
 		ID: req.GetContainerId(),
@@ -32,7 +43,17 @@ func (s *Server) CheckpointContainer(ctx context.Context, req *types.CheckpointC
 		TargetFile: req.GetLocation(),
 		// For the forensic container checkpointing use case we
 		// keep the container running after checkpointing it.
-		KeepRunning: true,
+		KeepRunning:       true,
+		Compression:       s.config.CheckpointRestoreConfig.Compression.Archive(),
+		ImageName:         imageName,
+		Push:              push,
+		SystemContext:     s.config.SystemContext,
+		PreDumpIterations: s.config.PreDumpIterations,
+		EncryptConfig:     encryptConfig,
+	}
+
+	if imageName != nil {
+		opts.TargetFile = ""
 	}
 
 	_, err = s.ContainerCheckpoint(ctx, config, opts)
@@ -58,6 +58,23 @@ var _ = t.Describe("ContainerCheckpoint", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("should fail with invalid checkpoint image name", func() {
+			// Given
+			addContainerAndSandbox()
+
+			// When
+			_, err := sut.CheckpointContainer(
+				context.Background(),
+				&types.CheckpointContainerRequest{
+					ContainerId: testContainer.ID(),
+					Location:    "docker://Invalid",
+				},
+			)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should fail with invalid container id", func() {
 			// Given
 			// When
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opencontainers/runtime-tools/generate"
+	"k8s.io/utils/cpuset"
+
+	"github.com/cri-o/cri-o/internal/cpuallocator"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+)
+
+// cpuSharesPerCPU are the CPU shares the kubelet assigns per requested CPU.
+const cpuSharesPerCPU = 1024
+
+// exclusiveCPUs returns the number of exclusive CPUs a container is eligible
+// for. Only containers of Guaranteed pods requesting an integer number of CPUs
+// without a cpuset assigned by the kubelet are eligible.
+func exclusiveCPUs(specgen *generate.Generator, cgroupParent string) int {
+	if strings.Contains(cgroupParent, "burstable") || strings.Contains(cgroupParent, "besteffort") {
+		return 0
+	}
+
+	lspec := specgen.Config.Linux
+	if lspec == nil || lspec.Resources == nil || lspec.Resources.CPU == nil {
+		return 0
+	}
+
+	cpu := lspec.Resources.CPU
+	if cpu.Cpus != "" || cpu.Quota == nil || cpu.Period == nil || cpu.Shares == nil ||
+		*cpu.Quota <= 0 || *cpu.Period == 0 || uint64(*cpu.Quota)%*cpu.Period != 0 {
+		return 0
+	}
+
+	count := uint64(*cpu.Quota) / *cpu.Period
+	if *cpu.Shares != count*cpuSharesPerCPU {
+		return 0
+	}
+
+	if lspec.Resources.Memory == nil || lspec.Resources.Memory.Limit == nil || *lspec.Resources.Memory.Limit <= 0 {
+		return 0
+	}
+
+	return int(count)
+}
+
+// setSharedCPUs restricts a container without a cpuset to the shared CPUs,
+// so that it does not run on the exclusive CPUs of other containers.
+func setSharedCPUs(specgen *generate.Generator, shared cpuset.CPUSet) {
+	lspec := specgen.Config.Linux
+	if lspec != nil && lspec.Resources != nil && lspec.Resources.CPU != nil && lspec.Resources.CPU.Cpus != "" {
+		return
+	}
+
+	specgen.SetLinuxResourcesCPUCpus(shared.String())
+}
+
+// allocateContainerCPUs assigns exclusive CPUs to an eligible container. The
+// CPUs are set as the cpuset of the container, which makes them subject to
+// the high-performance runtime hooks. All other containers without a cpuset
+// are restricted to the CPUs outside of the pool, like the shared pool of the
+// kubelet CPU manager.
+func (s *Server) allocateContainerCPUs(ctx context.Context, specgen *generate.Generator, sb *sandbox.Sandbox, containerID string) error {
+	if s.cpuAllocator == nil {
+		return nil
+	}
+
+	count := exclusiveCPUs(specgen, sb.CgroupParent())
+	if count == 0 {
+		setSharedCPUs(specgen, s.cpuAllocator.Shared())
+
+		return nil
+	}
+
+	cpus, err := s.cpuAllocator.Allocate(containerID, sb.ID(), count)
+	if err != nil {
+		return fmt.Errorf("allocate %d exclusive CPUs: %w", count, err)
+	}
+
+	log.Infof(ctx, "Allocated exclusive CPUs %s for container %s", cpus, containerID)
+	specgen.SetLinuxResourcesCPUCpus(cpus.String())
+
+	return nil
+}
+
+// releaseContainerCPUs releases the exclusive CPUs of a container.
+func (s *Server) releaseContainerCPUs(ctx context.Context, containerID string) {
+	if s.cpuAllocator == nil {
+		return
+	}
+
+	if err := s.cpuAllocator.Release(containerID); err != nil {
+		log.Warnf(ctx, "Unable to release exclusive CPUs of container %s: %v", containerID, err)
+	}
+}
+
+// restoreCPUAllocations reconciles the allocation table with the restored
+// containers.
+func (s *Server) restoreCPUAllocations(ctx context.Context) {
+	if s.cpuAllocator == nil {
+		return
+	}
+
+	containers, err := s.ContainerServer.ListContainers()
+	if err != nil {
+		log.Warnf(ctx, "Unable to list containers to restore CPU allocations: %v", err)
+
+		return
+	}
+
+	ids := make([]string, 0, len(containers))
+
+	for _, ctr := range containers {
+		ids = append(ids, ctr.ID())
+	}
+
+	if err := s.cpuAllocator.Restore(ids); err != nil {
+		log.Warnf(ctx, "Unable to restore CPU allocations: %v", err)
+	}
+}
+
+// newCPUAllocator creates the CPU allocator for the configured pool using the
+// online CPUs and the NUMA topology of the host.
+func newCPUAllocator(pool, path string) (*cpuallocator.Allocator, error) {
+	cpus, err := cpuset.Parse(pool)
+	if err != nil {
+		return nil, fmt.Errorf("parse CPU allocator pool: %w", err)
+	}
+
+	online, err := cpuallocator.ReadOnlineCPUs(cpuallocator.SysOnlineCPUsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	topology, err := cpuallocator.ReadTopology(cpuallocator.SysNodeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return cpuallocator.New(path, cpus, online, topology)
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/opencontainers/runtime-tools/generate"
+	"k8s.io/utils/cpuset"
+)
+
+func TestExclusiveCPUs(t *testing.T) {
+	cases := []struct {
+		name         string
+		cgroupParent string
+		quota        int64
+		shares       uint64
+		cpus         string
+		memory       int64
+		expected     int
+	}{
+		{
+			name:     "integer CPUs",
+			quota:    200000,
+			shares:   2048,
+			memory:   1 << 30,
+			expected: 2,
+		},
+		{
+			name:   "fractional CPUs",
+			quota:  150000,
+			shares: 1536,
+			memory: 1 << 30,
+		},
+		{
+			name:   "request below limit",
+			quota:  200000,
+			shares: 1024,
+			memory: 1 << 30,
+		},
+		{
+			name:   "cpuset assigned by the kubelet",
+			quota:  200000,
+			shares: 2048,
+			cpus:   "2-3",
+			memory: 1 << 30,
+		},
+		{
+			name:   "no memory limit",
+			quota:  200000,
+			shares: 2048,
+		},
+		{
+			name:         "burstable pod",
+			cgroupParent: "kubepods-burstable-pod123.slice",
+			quota:        200000,
+			shares:       2048,
+			memory:       1 << 30,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			specgen, err := generate.New("linux")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			specgen.SetLinuxResourcesCPUQuota(tc.quota)
+			specgen.SetLinuxResourcesCPUPeriod(100000)
+			specgen.SetLinuxResourcesCPUShares(tc.shares)
+			specgen.SetLinuxResourcesCPUCpus(tc.cpus)
+
+			if tc.memory > 0 {
+				specgen.SetLinuxResourcesMemoryLimit(tc.memory)
+			}
+
+			if res := exclusiveCPUs(&specgen, tc.cgroupParent); res != tc.expected {
+				t.Errorf("expected %d exclusive CPUs, got %d", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestSetSharedCPUs(t *testing.T) {
+	shared := cpuset.New(0, 1, 8, 9)
+
+	cases := []struct {
+		name     string
+		cpus     string
+		expected string
+	}{
+		{
+			name:     "no cpuset",
+			expected: "0-1,8-9",
+		},
+		{
+			name:     "cpuset assigned by the kubelet",
+			cpus:     "2-3",
+			expected: "2-3",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			specgen, err := generate.New("linux")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.cpus != "" {
+				specgen.SetLinuxResourcesCPUCpus(tc.cpus)
+			}
+
+			setSharedCPUs(&specgen, shared)
+
+			if res := specgen.Config.Linux.Resources.CPU.Cpus; res != tc.expected {
+				t.Errorf("expected cpuset %q, got %q", tc.expected, res)
+			}
+		})
+	}
+}
@@ -26,9 +26,11 @@ import (
 	"go.podman.io/storage/pkg/stringid"
 	"go.podman.io/storage/pkg/unshare"
 	"golang.org/x/sys/unix"
+	"google.golang.org/protobuf/proto"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 	kubeletTypes "k8s.io/kubelet/pkg/types"
 
+	"github.com/cri-o/cri-o/internal/annotations"
 	"github.com/cri-o/cri-o/internal/config/node"
 	"github.com/cri-o/cri-o/internal/config/rdt"
 	"github.com/cri-o/cri-o/internal/factory/container"
@@ -447,12 +449,27 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 		return nil, fmt.Errorf("specified sandbox not found: %s: %w", req.GetPodSandboxId(), err)
 	}
 
+	createConfig := req.GetConfig()
+
+	// Containers of a sandbox restored from a pod checkpoint archive are
+	// restored from their checkpoint in that archive when they get created
+	// for the first time.
+	if !checkpointImage && createConfig.GetMetadata().GetAttempt() == 0 {
+		if archivePath, ok := s.podCheckpointContainerArchive(sb, createConfig.GetMetadata().GetName()); ok {
+			log.Debugf(ctx, "Restoring container from pod checkpoint archive %s", archivePath)
+
+			createConfig = proto.CloneOf(createConfig)
+			createConfig.Image = &types.ImageSpec{Image: archivePath}
+			checkpointImage = true
+		}
+	}
+
 	if checkpointImage {
 		// This might be a checkpoint image. Let's pass
 		// it to the checkpoint code.
 		ctrID, err := s.CRImportCheckpoint(
 			ctx,
-			req.GetConfig(),
+			createConfig,
 			sb,
 			req.GetSandboxConfig().GetMetadata().GetUid(),
 		)
@@ -570,7 +587,10 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 
 	s.resourceStore.SetStageForResource(ctx, ctr.Name(), "container runtime creation")
 
-	if err := s.createContainerPlatform(ctx, newContainer, sb.CgroupParent(), mappings); err != nil {
+	err = s.createContainerPlatform(ctx, newContainer, sb.CgroupParent(), mappings)
+	s.runtimeHandlerContainersInc(sb, newContainer, "create", err)
+
+	if err != nil {
 		return nil, err
 	}
 
@@ -840,11 +860,29 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr container.Conta
 		return nil, err
 	}
 
+	runtimeHandler := sb.RuntimeHandler()
+	if imgInfo.imgResult.IsWasm() {
+		runtimeHandler, runtimePath, err = s.setupWasmContainer(ctx, ctr, sb, imgInfo.imgResult, runtimePath, specgen)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = ctr.SpecAddAnnotations(ctx, sb, containerVolumes, mountPoint, stopSignal, imgInfo.imgResult, s.config.CgroupManager().IsSystemd(), seccompRef, runtimePath)
 	if err != nil {
 		return nil, err
 	}
 
+	// WebAssembly containers may run with another runtime handler than the
+	// pod sandbox, which has to be known when restoring them.
+	specgen.AddAnnotation(annotations.RuntimeHandler, runtimeHandler)
+
+	// Set up pids limit if pids cgroup is mounted, before the workloads may
+	// override it.
+	if node.CgroupHasPid() {
+		specgen.SetLinuxResourcesPidsLimit(s.config.PidsLimit)
+	}
+
 	if err := s.config.Workloads.MutateSpecGivenAnnotations(ctr.Config().GetMetadata().GetName(), ctr.Spec(), sb.Annotations()); err != nil {
 		return nil, err
 	}
@@ -874,11 +912,6 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr container.Conta
 		return nil, err
 	}
 
-	// Set up pids limit if pids cgroup is mounted
-	if node.CgroupHasPid() {
-		specgen.SetLinuxResourcesPidsLimit(s.config.PidsLimit)
-	}
-
 	// by default, the root path is an empty string. set it now.
 	specgen.SetRootPath(mountPoint)
 
@@ -889,7 +922,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr container.Conta
 		Attempt: metadata.GetAttempt(),
 	}
 
-	ociContainer, err := oci.NewContainer(containerID, containerName, containerInfo.RunDir, logPath, labels, crioAnnotations, ctr.Config().GetAnnotations(), imgInfo.userRequestedImage, imgInfo.someNameOfTheImage, &imgInfo.imageID, imgInfo.someRepoDigest, criMetadata, sb.ID(), containerConfig.GetTty(), containerConfig.GetStdin(), containerConfig.GetStdinOnce(), sb.RuntimeHandler(), containerInfo.Dir, created, stopSignal)
+	ociContainer, err := oci.NewContainer(containerID, containerName, containerInfo.RunDir, logPath, labels, crioAnnotations, ctr.Config().GetAnnotations(), imgInfo.userRequestedImage, imgInfo.someNameOfTheImage, &imgInfo.imageID, imgInfo.someRepoDigest, criMetadata, sb.ID(), containerConfig.GetTty(), containerConfig.GetStdin(), containerConfig.GetStdinOnce(), runtimeHandler, containerInfo.Dir, created, stopSignal)
 	if err != nil {
 		return nil, err
 	}
@@ -917,6 +950,24 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr container.Conta
 		makeOCIConfigurationRootless(specgen)
 	}
 
+	if err := s.allocateContainerCPUs(ctx, specgen, sb, containerID); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if retErr != nil {
+			s.releaseContainerCPUs(ctx, containerID)
+		}
+	}()
+
+	if err := s.setupContainerNUMAPlacement(ctx, specgen, sb, metadata.GetName(), cdiDeviceNames(ctx, containerConfig)); err != nil {
+		return nil, err
+	}
+
+	if err := s.setupContainerIOLimits(ctx, specgen, sb, metadata.GetName()); err != nil {
+		return nil, err
+	}
+
 	hooks := s.hooksRetriever.Get(ctx, sb.RuntimeHandler(), sb.Annotations())
 
 	if err := s.nri.createContainer(ctx, specgen, sb, ociContainer); err != nil {
@@ -941,6 +992,10 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr container.Conta
 		}
 	}
 
+	if err := s.ContainerServer.Runtime().ValidateRuntimeFeatures(runtimeHandler, runtimePath, specgen.Config); err != nil {
+		return nil, err
+	}
+
 	saveOptions := generate.ExportOptions{}
 	if err := specgen.SaveToFile(filepath.Join(containerInfo.Dir, "config.json"), saveOptions); err != nil {
 		return nil, err
@@ -1584,6 +1639,11 @@ func (s *Server) setupContainerRuntimeAndStopSignal(ctx context.Context, ctr con
 		return "", "", err
 	}
 
+	// Containers of pod sandboxes using the canary runtime use it as well.
+	if runtimePath == "" && sb.InfraContainer() != nil {
+		runtimePath = sb.InfraContainer().RuntimePath()
+	}
+
 	// Determine the stop signal for the container. If a custom stop signal is provided
 	// via CRI API, use it. Otherwise, fall back to the image's default stop signal as
 	// defined in its configuration.
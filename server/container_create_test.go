@@ -3,10 +3,18 @@ package server_test
 import (
 	"context"
 	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/opencontainers/runtime-tools/generate"
+	"go.uber.org/mock/gomock"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/cri-o/cri-o/internal/mockutils"
+	"github.com/cri-o/cri-o/internal/storage"
+	"github.com/cri-o/cri-o/pkg/config"
 )
 
 // The actual test suite.
@@ -223,3 +231,93 @@ var _ = t.Describe("ContainerCreate", func() {
 		})
 	})
 })
+
+var _ = t.Describe("ContainerCreate with workloads", func() {
+	const (
+		activationAnnotation = "workload.crio.io/test"
+		workloadPidsLimit    = 1024
+	)
+
+	// Prepare the sut
+	BeforeEach(func() {
+		beforeEach()
+		mockRuntimeInLibConfig()
+
+		serverConfig.PidsLimit = -1
+		serverConfig.Workloads = config.Workloads{
+			"test": {
+				ActivationAnnotation: activationAnnotation,
+				AnnotationPrefix:     "resources.workload.crio.io",
+				Resources:            &config.Resources{PidsLimit: workloadPidsLimit},
+			},
+		}
+
+		setupSUT()
+	})
+
+	AfterEach(afterEach)
+
+	It("should apply the workload pids limit over the global one", func() {
+		// Given
+		ctx := context.TODO()
+		sb := newTestSandbox("workloadSandboxID", "", map[string]string{activationAnnotation: ""})
+		Expect(sut.AddSandbox(ctx, sb)).To(Succeed())
+		Expect(sut.PodIDIndex().Add(sb.ID())).To(Succeed())
+		Expect(sb.SetInfraContainer(testContainer)).To(Succeed())
+		sb.SetCreated()
+
+		imageID, err := storage.ParseStorageImageIDFromOutOfProcessData("8a788232037eaf17794408ff3df6b922a1aedf9ef8de36afdae3ed0b0381907b")
+		Expect(err).ToNot(HaveOccurred())
+
+		containerDir := t.MustTempDir("container")
+
+		// The image is looked up for the checkpoint detection, too.
+		imageServerMock.EXPECT().HeuristicallyTryResolvingStringAsIDPrefix("image").
+			Return(&imageID).AnyTimes()
+		imageServerMock.EXPECT().ImageStatusByID(gomock.Any(), imageID).
+			Return(&storage.ImageResult{ID: imageID}, nil).AnyTimes()
+		mockutils.InOrder(
+			runtimeServerMock.EXPECT().CreateContainer(gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), imageID, gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any()).
+				Return(storage.ContainerInfo{
+					Dir:    containerDir,
+					RunDir: containerDir,
+					Config: &v1.Image{
+						Config: v1.ImageConfig{Entrypoint: []string{"sh"}},
+					},
+				}, nil),
+			runtimeServerMock.EXPECT().StartContainer(gomock.Any()).
+				Return(t.MustTempDir("rootfs"), nil),
+		)
+		// The echo runtime fails to create the container afterwards.
+		runtimeServerMock.EXPECT().StopContainer(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		runtimeServerMock.EXPECT().DeleteContainer(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+		// When
+		_, err = sut.CreateContainer(ctx, &types.CreateContainerRequest{
+			PodSandboxId: sb.ID(),
+			Config: &types.ContainerConfig{
+				Metadata: &types.ContainerMetadata{Name: "ctr"},
+				Image:    &types.ImageSpec{Image: "image"},
+				Linux: &types.LinuxContainerConfig{
+					SecurityContext: &types.LinuxContainerSecurityContext{
+						NamespaceOptions: &types.NamespaceOption{Pid: types.NamespaceMode_CONTAINER},
+					},
+				},
+			},
+			SandboxConfig: &types.PodSandboxConfig{
+				Metadata: &types.PodSandboxMetadata{},
+			},
+		})
+
+		// Then
+		Expect(err).To(HaveOccurred())
+
+		spec, err := generate.NewFromFile(filepath.Join(containerDir, "config.json"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(spec.Config.Linux.Resources.Pids).NotTo(BeNil())
+		Expect(spec.Config.Linux.Resources.Pids.Limit).To(HaveValue(BeEquivalentTo(workloadPidsLimit)))
+	})
+})
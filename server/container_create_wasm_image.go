@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/opencontainers/runtime-tools/generate"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/cri-o/cri-o/internal/factory/container"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/storage"
+)
+
+// defaultWasmVariant lets wasm-capable runtimes execute the container as
+// WebAssembly module if its entrypoint is one, and as native binary otherwise.
+const defaultWasmVariant = "compat-smart"
+
+// setupWasmContainer validates the container of a WebAssembly image and
+// returns the runtime handler and path to be used for it. If the pod sandbox
+// uses an OCI runtime handler which has no runtime path configured for the
+// platform of the image, the configured wasm runtime handler runs the
+// container, including its runtime root and monitor settings. The runtime
+// features of the spec are validated against the returned runtime handler.
+func (s *Server) setupWasmContainer(ctx context.Context, ctr container.Container, sb *sandbox.Sandbox, imgResult *storage.ImageResult, runtimePath string, specgen *generate.Generator) (runtimeHandler, wasmRuntimePath string, err error) {
+	if err := validateWasmContainer(ctx, ctr); err != nil {
+		return "", "", err
+	}
+
+	variant, ok := imgResult.Annotations[storage.WasmVariantAnnotation]
+	if !ok {
+		variant = defaultWasmVariant
+	}
+
+	specgen.AddAnnotation(storage.WasmVariantAnnotation, variant)
+
+	if imgResult.OCIConfig != nil {
+		platform := imgResult.OCIConfig.OS + "/" + imgResult.OCIConfig.Architecture
+
+		platformRuntimePath, err := s.ContainerServer.Runtime().PlatformRuntimePath(sb.RuntimeHandler(), platform)
+		if err != nil {
+			return "", "", err
+		}
+
+		if platformRuntimePath != "" {
+			return sb.RuntimeHandler(), runtimePath, nil
+		}
+	}
+
+	wasmHandler, err := s.ContainerServer.Runtime().WasmRuntimeHandler(sb.RuntimeHandler())
+	if err != nil {
+		return "", "", err
+	}
+
+	if wasmHandler == "" {
+		return sb.RuntimeHandler(), runtimePath, nil
+	}
+
+	log.Debugf(ctx, "Using wasm runtime handler %s for container %s", wasmHandler, ctr.ID())
+
+	// The runtime path of the pod sandbox, like its canary runtime path,
+	// does not apply to the wasm runtime handler.
+	return wasmHandler, "", nil
+}
+
+// validateWasmContainer rejects the features of a container which
+// WebAssembly images do not support, because they isolate the module from the
+// host: the privileged mode, host namespaces and devices.
+func validateWasmContainer(ctx context.Context, ctr container.Container) error {
+	if ctr.Privileged() {
+		return errors.New("privileged containers are not supported for WebAssembly images")
+	}
+
+	namespaceOptions := ctr.Config().GetLinux().GetSecurityContext().GetNamespaceOptions()
+
+	for _, ns := range []struct {
+		name string
+		mode types.NamespaceMode
+	}{
+		{"network", namespaceOptions.GetNetwork()},
+		{"PID", namespaceOptions.GetPid()},
+		{"IPC", namespaceOptions.GetIpc()},
+	} {
+		if ns.mode == types.NamespaceMode_NODE {
+			return fmt.Errorf("host %s namespace is not supported for WebAssembly images", ns.name)
+		}
+	}
+
+	if len(ctr.Config().GetDevices()) > 0 || len(cdiDeviceNames(ctx, ctr.Config())) > 0 {
+		return errors.New("devices are not supported for WebAssembly images")
+	}
+
+	return nil
+}
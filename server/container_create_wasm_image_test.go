@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/cri-o/cri-o/internal/factory/container"
+)
+
+func TestValidateWasmContainer(t *testing.T) {
+	cases := []struct {
+		name        string
+		privileged  bool
+		namespaces  *types.NamespaceOption
+		devices     []*types.Device
+		cdiDevices  []*types.CDIDevice
+		expectError bool
+	}{
+		{
+			name:       "pod namespaces",
+			namespaces: &types.NamespaceOption{Network: types.NamespaceMode_POD, Pid: types.NamespaceMode_CONTAINER},
+		},
+		{
+			name:        "privileged",
+			privileged:  true,
+			expectError: true,
+		},
+		{
+			name:        "host network namespace",
+			namespaces:  &types.NamespaceOption{Network: types.NamespaceMode_NODE},
+			expectError: true,
+		},
+		{
+			name:        "host PID namespace",
+			namespaces:  &types.NamespaceOption{Pid: types.NamespaceMode_NODE},
+			expectError: true,
+		},
+		{
+			name:        "host IPC namespace",
+			namespaces:  &types.NamespaceOption{Ipc: types.NamespaceMode_NODE},
+			expectError: true,
+		},
+		{
+			name:        "device",
+			devices:     []*types.Device{{HostPath: "/dev/fuse", ContainerPath: "/dev/fuse"}},
+			expectError: true,
+		},
+		{
+			name:        "CDI device",
+			cdiDevices:  []*types.CDIDevice{{Name: "vendor.com/device=dev0"}},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctr, err := container.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			config := &types.ContainerConfig{
+				Metadata: &types.ContainerMetadata{Name: "wasm"},
+				Linux: &types.LinuxContainerConfig{
+					SecurityContext: &types.LinuxContainerSecurityContext{
+						Privileged:       tc.privileged,
+						NamespaceOptions: tc.namespaces,
+					},
+				},
+				Devices:    tc.devices,
+				CDIDevices: tc.cdiDevices,
+			}
+			sandboxConfig := &types.PodSandboxConfig{
+				Linux: &types.LinuxPodSandboxConfig{
+					SecurityContext: &types.LinuxSandboxSecurityContext{Privileged: tc.privileged},
+				},
+			}
+
+			if err := ctr.SetConfig(config, sandboxConfig); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := ctr.SetPrivileged(); err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateWasmContainer(context.Background(), ctr)
+			if tc.expectError && err == nil {
+				t.Fatal("expected error")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
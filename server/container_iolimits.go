@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/opencontainers/runtime-tools/generate"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	crioann "github.com/cri-o/cri-o/pkg/annotations/v2"
+	"github.com/cri-o/cri-o/pkg/config"
+)
+
+// errGraphRootNoBlockDevice is returned when resolving iolimits devices if
+// the graph root is not stored on a block device, for which the devices
+// backing it are unknown.
+var errGraphRootNoBlockDevice = errors.New("graph root is not backed by a block device")
+
+// containerIOLimits returns the IO limits requested for the container. The
+// annotation takes precedence over the workload.
+func (s *Server) containerIOLimits(sb *sandbox.Sandbox, ctrName string) (map[string]*config.IODeviceLimit, error) {
+	value, ok := sb.Annotations()[crioann.IOLimits+"/"+ctrName]
+	if !ok {
+		value, ok = sb.Annotations()[crioann.IOLimits]
+	}
+
+	if !ok {
+		resources, err := s.config.Workloads.ContainerResources(ctrName, sb.Annotations())
+		if err != nil || resources == nil {
+			return nil, err
+		}
+
+		return resources.IOLimits, nil
+	}
+
+	limits := map[string]*config.IODeviceLimit{}
+	if err := json.Unmarshal([]byte(value), &limits); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation value: %w", crioann.IOLimits, err)
+	}
+
+	if err := config.ValidateIOLimits(limits); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation value: %w", crioann.IOLimits, err)
+	}
+
+	return limits, nil
+}
+
+// setupContainerIOLimits sets the per-device bandwidth, IOPS and weight
+// limits of the container, if requested. The devices are resolved to the
+// whole disks backing them, which overrides the limits of the blockio class
+// for these disks. The limits are skipped with a warning if the graph root is
+// not backed by a block device.
+func (s *Server) setupContainerIOLimits(ctx context.Context, specgen *generate.Generator, sb *sandbox.Sandbox, ctrName string) error {
+	limits, err := s.containerIOLimits(sb, ctrName)
+	if err != nil || len(limits) == 0 {
+		return err
+	}
+
+	for device, limit := range limits {
+		major, minor, err := resolveIODevice(device, s.Store().GraphRoot())
+		if errors.Is(err, errGraphRootNoBlockDevice) {
+			log.Warnf(ctx, "Not setting IO limits of container %s: %v", ctrName, err)
+
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("resolve iolimits device %q of container %s: %w", device, ctrName, err)
+		}
+
+		log.Debugf(ctx, "Setting IO limits of container %s on block device %d:%d", ctrName, major, minor)
+
+		if limit.ReadBps > 0 {
+			specgen.AddLinuxResourcesBlockIOThrottleReadBpsDevice(major, minor, limit.ReadBps)
+		}
+
+		if limit.WriteBps > 0 {
+			specgen.AddLinuxResourcesBlockIOThrottleWriteBpsDevice(major, minor, limit.WriteBps)
+		}
+
+		if limit.ReadIOPS > 0 {
+			specgen.AddLinuxResourcesBlockIOThrottleReadIOPSDevice(major, minor, limit.ReadIOPS)
+		}
+
+		if limit.WriteIOPS > 0 {
+			specgen.AddLinuxResourcesBlockIOThrottleWriteIOPSDevice(major, minor, limit.WriteIOPS)
+		}
+
+		if limit.Weight > 0 {
+			specgen.AddLinuxResourcesBlockIOWeightDevice(major, minor, limit.Weight)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/cri-o/cri-o/pkg/config"
+)
+
+// errNoBlockDevice is returned if device numbers do not belong to a block
+// device.
+var errNoBlockDevice = errors.New("not a block device")
+
+// resolveIODevice returns the major and minor numbers of the whole disk
+// backing an iolimits device, which is either "graphroot", a block device
+// path or "major:minor". The device has to back the graph root, which fails
+// with errGraphRootNoBlockDevice if it is not stored on a block device.
+func resolveIODevice(device, graphRoot string) (major, minor int64, err error) {
+	major, minor, err = config.ParseIODevice(device)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	var stat unix.Stat_t
+	if err := unix.Stat(graphRoot, &stat); err != nil {
+		return -1, -1, fmt.Errorf("stat graph root: %w", err)
+	}
+
+	// File systems like btrfs or tmpfs use anonymous device numbers.
+	graphMajor, graphMinor, err := sysfsBlockDevice(sysDevDir, int64(unix.Major(stat.Dev)), int64(unix.Minor(stat.Dev)))
+	if errors.Is(err, errNoBlockDevice) {
+		return -1, -1, fmt.Errorf("%w: %s", errGraphRootNoBlockDevice, graphRoot)
+	} else if err != nil {
+		return -1, -1, err
+	}
+
+	if device == config.IODeviceGraphRoot {
+		return graphMajor, graphMinor, nil
+	}
+
+	if major < 0 {
+		if err := unix.Stat(device, &stat); err != nil {
+			return -1, -1, fmt.Errorf("stat device: %w", err)
+		}
+
+		if stat.Mode&unix.S_IFMT != unix.S_IFBLK {
+			return -1, -1, fmt.Errorf("%s is no block device", device)
+		}
+
+		major, minor = int64(unix.Major(stat.Rdev)), int64(unix.Minor(stat.Rdev))
+	}
+
+	major, minor, err = sysfsBlockDevice(sysDevDir, major, minor)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	backs, err := sysfsBacksBlockDevice(sysDevDir, graphMajor, graphMinor, major, minor)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	if !backs {
+		return -1, -1, fmt.Errorf("block device %d:%d does not back the graph root %s", major, minor, graphRoot)
+	}
+
+	return major, minor, nil
+}
+
+// sysfsBacksBlockDevice returns whether the disk with the major and minor
+// numbers is the disk with the diskMajor and diskMinor numbers, or one of the
+// disks it is stacked on, like the ones of a device mapper or RAID device.
+func sysfsBacksBlockDevice(dir string, diskMajor, diskMinor, major, minor int64) (bool, error) {
+	if diskMajor == major && diskMinor == minor {
+		return true, nil
+	}
+
+	slavesDir := filepath.Join(dir, "block", fmt.Sprintf("%d:%d", diskMajor, diskMinor), "slaves")
+
+	slaves, err := os.ReadDir(slavesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	for _, slave := range slaves {
+		content, err := os.ReadFile(filepath.Join(slavesDir, slave.Name(), "dev"))
+		if err != nil {
+			return false, fmt.Errorf("read slave %s of disk %d:%d: %w", slave.Name(), diskMajor, diskMinor, err)
+		}
+
+		slaveMajor, slaveMinor, err := config.ParseIODevice(strings.TrimSpace(string(content)))
+		if err != nil {
+			return false, err
+		}
+
+		slaveMajor, slaveMinor, err = sysfsBlockDevice(dir, slaveMajor, slaveMinor)
+		if err != nil {
+			return false, err
+		}
+
+		backs, err := sysfsBacksBlockDevice(dir, slaveMajor, slaveMinor, major, minor)
+		if err != nil || backs {
+			return backs, err
+		}
+	}
+
+	return false, nil
+}
+
+// sysfsBlockDevice validates that the device numbers belong to a block device
+// and returns the numbers of its disk if it is a partition, because the IO
+// controller only limits whole disks.
+func sysfsBlockDevice(dir string, major, minor int64) (diskMajor, diskMinor int64, err error) {
+	path := filepath.Join(dir, "block", fmt.Sprintf("%d:%d", major, minor))
+
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return -1, -1, fmt.Errorf("device %d:%d: %w", major, minor, errNoBlockDevice)
+		}
+
+		return -1, -1, err
+	}
+
+	if _, err := os.Stat(filepath.Join(path, "partition")); errors.Is(err, os.ErrNotExist) {
+		return major, minor, nil
+	} else if err != nil {
+		return -1, -1, err
+	}
+
+	partition, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	content, err := os.ReadFile(filepath.Join(filepath.Dir(partition), "dev"))
+	if err != nil {
+		return -1, -1, fmt.Errorf("read disk of partition %d:%d: %w", major, minor, err)
+	}
+
+	diskMajor, diskMinor, err = config.ParseIODevice(strings.TrimSpace(string(content)))
+	if err != nil {
+		return -1, -1, err
+	}
+
+	return diskMajor, diskMinor, nil
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSysfsBlockDevice(t *testing.T) {
+	dir := t.TempDir()
+
+	diskDir := filepath.Join(dir, "devices", "sda")
+	partitionDir := filepath.Join(diskDir, "sda1")
+
+	if err := os.MkdirAll(partitionDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(diskDir, "dev"), []byte("8:0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(partitionDir, "partition"), []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "block"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for link, target := range map[string]string{"8:0": diskDir, "8:1": partitionDir} {
+		if err := os.Symlink(target, filepath.Join(dir, "block", link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name          string
+		minor         int64
+		expectedMinor int64
+		expectError   bool
+	}{
+		{
+			name:          "disk",
+			minor:         0,
+			expectedMinor: 0,
+		},
+		{
+			name:          "partition",
+			minor:         1,
+			expectedMinor: 0,
+		},
+		{
+			name:        "no block device",
+			minor:       2,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			major, minor, err := sysfsBlockDevice(dir, 8, tc.minor)
+			if tc.expectError {
+				if !errors.Is(err, errNoBlockDevice) {
+					t.Fatalf("expected no block device error, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if major != 8 || minor != tc.expectedMinor {
+				t.Errorf("expected block device 8:%d, got %d:%d", tc.expectedMinor, major, minor)
+			}
+		})
+	}
+}
+
+func TestSysfsBacksBlockDevice(t *testing.T) {
+	dir := t.TempDir()
+
+	devices := map[string]string{
+		"8:0":   filepath.Join(dir, "devices", "sda"),
+		"8:1":   filepath.Join(dir, "devices", "sda", "sda1"),
+		"8:16":  filepath.Join(dir, "devices", "sdb"),
+		"253:0": filepath.Join(dir, "devices", "dm-0"),
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "block"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for numbers, deviceDir := range devices {
+		if err := os.MkdirAll(deviceDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(deviceDir, "dev"), []byte(numbers+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Symlink(deviceDir, filepath.Join(dir, "block", numbers)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(devices["8:1"], "partition"), []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The device mapper device dm-0 is stacked on the partition sda1.
+	if err := os.MkdirAll(filepath.Join(devices["253:0"], "slaves"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink(devices["8:1"], filepath.Join(devices["253:0"], "slaves", "sda1")); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name       string
+		diskMajor  int64
+		diskMinor  int64
+		major      int64
+		minor      int64
+		expectBack bool
+	}{
+		{
+			name:       "same disk",
+			diskMajor:  8,
+			diskMinor:  0,
+			major:      8,
+			minor:      0,
+			expectBack: true,
+		},
+		{
+			name:       "disk of stacked device",
+			diskMajor:  253,
+			diskMinor:  0,
+			major:      8,
+			minor:      0,
+			expectBack: true,
+		},
+		{
+			name:      "other disk",
+			diskMajor: 8,
+			diskMinor: 0,
+			major:     8,
+			minor:     16,
+		},
+		{
+			name:      "other disk of stacked device",
+			diskMajor: 253,
+			diskMinor: 0,
+			major:     8,
+			minor:     16,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			backs, err := sysfsBacksBlockDevice(dir, tc.diskMajor, tc.diskMinor, tc.major, tc.minor)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if backs != tc.expectBack {
+				t.Errorf("expected %d:%d backing %d:%d to be %v", tc.major, tc.minor, tc.diskMajor, tc.diskMinor, tc.expectBack)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package server
+
+import "errors"
+
+// resolveIODevice is not supported on this platform.
+func resolveIODevice(string, string) (major, minor int64, err error) {
+	return -1, -1, errors.New("iolimits are not supported on this platform")
+}
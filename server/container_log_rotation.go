@@ -0,0 +1,293 @@
+package server
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/pkg/config"
+)
+
+const (
+	// logRotationInterval is the interval in which the log files of the
+	// containers are checked for rotation.
+	logRotationInterval = 10 * time.Second
+
+	// rotatedLogSeparator separates the log path and the timestamp of rotated
+	// log files. The kubelet claims every file matching "<log path>.*" for its
+	// own log rotation, so the rotated files of CRI-O must not use a dot.
+	rotatedLogSeparator = "-"
+
+	// rotatedLogTimeFormat is the timestamp suffix of rotated log files.
+	rotatedLogTimeFormat = "20060102-150405"
+
+	// compressedLogSuffix is the suffix of compressed rotated log files.
+	compressedLogSuffix = ".gz"
+
+	// logCompressionQueueSize is the number of log paths whose rotated files
+	// may wait for their compression.
+	logCompressionQueueSize = 64
+)
+
+// logCompression requests to compress the rotated files of a log path.
+type logCompression struct {
+	logPath  string
+	maxFiles int
+}
+
+// startLogRotation periodically rotates the log files of the running
+// containers whose runtime handler or namespace configures a log rotation.
+func (s *Server) startLogRotation(ctx context.Context) {
+	// Compressing the rotated files may take a while for large logs, so it
+	// happens outside of the rotation loop.
+	compressions := make(chan logCompression, logCompressionQueueSize)
+
+	go func() {
+		for {
+			select {
+			case <-s.monitorsChan:
+				return
+			case compression := <-compressions:
+				if err := compressRotatedLogs(compression.logPath, compression.maxFiles); err != nil {
+					log.Warnf(ctx, "Unable to compress rotated logs of %s: %v", compression.logPath, err)
+				}
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(logRotationInterval)
+		defer ticker.Stop()
+
+		// The time of the last rotation per container, for the rotation by age.
+		lastRotation := map[string]time.Time{}
+
+		for {
+			select {
+			case <-s.monitorsChan:
+				return
+			case <-ticker.C:
+				lastRotation = s.rotateContainerLogs(ctx, lastRotation, compressions)
+			}
+		}
+	}()
+}
+
+// rotateContainerLogs rotates the log files of the running containers which
+// are due for rotation. It returns the time of the last rotation of the
+// containers with a log rotation.
+func (s *Server) rotateContainerLogs(ctx context.Context, lastRotation map[string]time.Time, compressions chan<- logCompression) map[string]time.Time {
+	now := time.Now()
+	rotations := map[string]time.Time{}
+
+	for _, sb := range s.ContainerServer.ListSandboxes() {
+		rotation := s.config.ContainerLogRotation(sb.RuntimeHandler(), sb.Namespace())
+		if rotation == nil {
+			continue
+		}
+
+		for _, ctr := range sb.Containers().List() {
+			if ctr.LogPath() == "" || ctr.State().Status != oci.ContainerStateRunning {
+				continue
+			}
+
+			last, ok := lastRotation[ctr.ID()]
+			if !ok {
+				last = ctr.State().Started
+			}
+
+			rotations[ctr.ID()] = last
+
+			due, err := logRotationDue(ctr.LogPath(), rotation, last, now)
+			if err != nil {
+				log.Debugf(ctx, "Unable to check log rotation of container %s: %v", ctr.ID(), err)
+
+				continue
+			}
+
+			if !due {
+				continue
+			}
+
+			if err := s.rotateContainerLog(ctx, ctr, rotation, now, compressions); err != nil {
+				log.Warnf(ctx, "Unable to rotate log of container %s: %v", ctr.ID(), err)
+
+				continue
+			}
+
+			rotations[ctr.ID()] = now
+		}
+	}
+
+	return rotations
+}
+
+// logRotationDue returns whether the non-empty log file exceeds the size or
+// the age of the log rotation.
+func logRotationDue(logPath string, rotation *config.ContainerLogRotation, lastRotation, now time.Time) (bool, error) {
+	info, err := os.Stat(logPath)
+	if err != nil {
+		return false, err
+	}
+
+	if info.Size() == 0 {
+		return false, nil
+	}
+
+	if rotation.RotateSize() > 0 && info.Size() >= rotation.RotateSize() {
+		return true, nil
+	}
+
+	return rotation.RotateAge() > 0 && now.Sub(lastRotation) >= rotation.RotateAge(), nil
+}
+
+// rotateContainerLog renames the log file of the container and lets the
+// runtime reopen the container log. The rotated files exceeding the maximum
+// number of files are removed, and the ones except the newest get compressed
+// in the background if configured.
+func (s *Server) rotateContainerLog(ctx context.Context, ctr *oci.Container, rotation *config.ContainerLogRotation, now time.Time, compressions chan<- logCompression) error {
+	rotatedPath := ctr.LogPath() + rotatedLogSeparator + now.Format(rotatedLogTimeFormat)
+
+	if err := os.Rename(ctr.LogPath(), rotatedPath); err != nil {
+		return fmt.Errorf("rename log file: %w", err)
+	}
+
+	if err := s.ContainerServer.Runtime().ReopenContainerLog(ctx, ctr); err != nil {
+		// Restore the log file, which the runtime still writes to.
+		if renameErr := os.Rename(rotatedPath, ctr.LogPath()); renameErr != nil {
+			log.Warnf(ctx, "Unable to restore log file of container %s: %v", ctr.ID(), renameErr)
+		}
+
+		return fmt.Errorf("reopen container log: %w", err)
+	}
+
+	log.Debugf(ctx, "Rotated log of container %s to %s", ctr.ID(), rotatedPath)
+
+	if !rotation.LogRotateCompress {
+		return removeRotatedLogs(ctr.LogPath(), rotation.RotateMaxFiles())
+	}
+
+	// The files which are not compressed because of a full queue get
+	// compressed after the next rotation.
+	select {
+	case compressions <- logCompression{logPath: ctr.LogPath(), maxFiles: rotation.RotateMaxFiles()}:
+	default:
+		log.Debugf(ctx, "Postponing compression of the rotated logs of container %s", ctr.ID())
+	}
+
+	return nil
+}
+
+// compressRotatedLogs removes the rotated files of the log path exceeding the
+// maximum number of files and compresses the remaining ones except the newest,
+// which may still be written by the runtime or read by log collectors.
+func compressRotatedLogs(logPath string, maxFiles int) error {
+	if err := removeRotatedLogs(logPath, maxFiles); err != nil {
+		return err
+	}
+
+	rotated, err := rotatedLogs(logPath)
+	if err != nil {
+		return err
+	}
+
+	if len(rotated) == 0 {
+		return nil
+	}
+
+	for _, path := range rotated[:len(rotated)-1] {
+		if strings.HasSuffix(path, compressedLogSuffix) {
+			continue
+		}
+
+		if err := compressLogFile(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compressLogFile replaces the log file by its gzip compressed version.
+func compressLogFile(path string) (retErr error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+compressedLogSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := dst.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+
+		if retErr != nil {
+			os.Remove(dst.Name())
+		}
+	}()
+
+	writer := gzip.NewWriter(dst)
+
+	if _, err := io.Copy(writer, src); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// removeRotatedLogs removes the oldest rotated log files of the log path
+// exceeding the maximum number of files.
+func removeRotatedLogs(logPath string, maxFiles int) error {
+	rotated, err := rotatedLogs(logPath)
+	if err != nil {
+		return err
+	}
+
+	for len(rotated) > maxFiles {
+		if err := os.Remove(rotated[0]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		rotated = rotated[1:]
+	}
+
+	return nil
+}
+
+// rotatedLogs returns the rotated files of the log path, sorted from the
+// oldest to the newest.
+func rotatedLogs(logPath string) ([]string, error) {
+	matches, err := filepath.Glob(logPath + rotatedLogSeparator + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	rotated := slices.DeleteFunc(matches, func(path string) bool {
+		timestamp := strings.TrimSuffix(strings.TrimPrefix(path, logPath+rotatedLogSeparator), compressedLogSuffix)
+		_, err := time.Parse(rotatedLogTimeFormat, timestamp)
+
+		return err != nil
+	})
+
+	// The timestamps sort the rotated files from the oldest to the newest.
+	slices.Sort(rotated)
+
+	return rotated, nil
+}
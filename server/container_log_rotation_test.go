@@ -0,0 +1,162 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cri-o/cri-o/pkg/config"
+)
+
+func TestLogRotationDue(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "0.log")
+	if err := os.WriteFile(logPath, make([]byte, 10000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+
+	cases := []struct {
+		name         string
+		rotation     config.ContainerLogRotation
+		lastRotation time.Time
+		expected     bool
+	}{
+		{
+			name:         "below size",
+			rotation:     config.ContainerLogRotation{LogRotateSize: "20KiB"},
+			lastRotation: now,
+			expected:     false,
+		},
+		{
+			name:         "exceeding size",
+			rotation:     config.ContainerLogRotation{LogRotateSize: "9KiB"},
+			lastRotation: now,
+			expected:     true,
+		},
+		{
+			name:         "below age",
+			rotation:     config.ContainerLogRotation{LogRotateAge: "1h"},
+			lastRotation: now.Add(-time.Minute),
+			expected:     false,
+		},
+		{
+			name:         "exceeding age",
+			rotation:     config.ContainerLogRotation{LogRotateAge: "1h"},
+			lastRotation: now.Add(-2 * time.Hour),
+			expected:     true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.rotation.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			due, err := logRotationDue(logPath, &tc.rotation, tc.lastRotation, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if due != tc.expected {
+				t.Fatalf("Expected rotation due to be %v, got %v", tc.expected, due)
+			}
+		})
+	}
+}
+
+func TestRemoveRotatedLogs(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "0.log")
+
+	files := []string{
+		"0.log",
+		"0.log-20250101-100000.gz",
+		"0.log-20250101-110000",
+		"0.log-20250101-120000.gz",
+		"0.log-unrelated",
+		"0.log.20250101-090000",
+	}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := removeRotatedLogs(logPath, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"0.log", "0.log-20250101-110000", "0.log-20250101-120000.gz", "0.log-unrelated", "0.log.20250101-090000"}
+	if remaining := dirEntryNames(t, dir); !slices.Equal(remaining, expected) {
+		t.Fatalf("Expected files %v, got %v", expected, remaining)
+	}
+}
+
+func TestCompressRotatedLogs(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "0.log")
+
+	files := []string{
+		"0.log",
+		"0.log-20250101-100000",
+		"0.log-20250101-110000.gz",
+		"0.log-20250101-120000",
+		"0.log-20250101-130000",
+		"0.log.20250101-090000",
+	}
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("log\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := compressRotatedLogs(logPath, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	// The kubelet rotated file is left alone and the newest rotated file
+	// stays uncompressed.
+	expected := []string{"0.log", "0.log-20250101-110000.gz", "0.log-20250101-120000.gz", "0.log-20250101-130000", "0.log.20250101-090000"}
+	if remaining := dirEntryNames(t, dir); !slices.Equal(remaining, expected) {
+		t.Fatalf("Expected files %v, got %v", expected, remaining)
+	}
+}
+
+func dirEntryNames(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	return names
+}
+
+func TestCompressLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "0.log-20250101-100000")
+	if err := os.WriteFile(path, []byte("log\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := compressLogFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Expected rotated log to be removed, got %v", err)
+	}
+
+	if _, err := os.Stat(path + compressedLogSuffix); err != nil {
+		t.Fatal(err)
+	}
+}
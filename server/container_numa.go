@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/opencontainers/runtime-tools/generate"
+	cri "k8s.io/cri-api/pkg/apis/runtime/v1"
+	"k8s.io/utils/cpuset"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+
+	"github.com/cri-o/cri-o/internal/annotations"
+	"github.com/cri-o/cri-o/internal/cpuallocator"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	crioann "github.com/cri-o/cri-o/pkg/annotations/v2"
+	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/pkg/types"
+)
+
+// numaMemoryPlacementPolicy returns the NUMA memory placement requested for
+// the container. The annotation takes precedence over the workload.
+func (s *Server) numaMemoryPlacementPolicy(sb *sandbox.Sandbox, ctrName string) (string, error) {
+	policy, ok := sb.Annotations()[crioann.NUMAMemoryPlacement+"/"+ctrName]
+	if !ok {
+		policy, ok = sb.Annotations()[crioann.NUMAMemoryPlacement]
+	}
+
+	if !ok {
+		resources, err := s.config.Workloads.ContainerResources(ctrName, sb.Annotations())
+		if err != nil || resources == nil {
+			return "", err
+		}
+
+		return resources.NUMAMemoryPlacement, nil
+	}
+
+	switch policy {
+	case config.NUMAMemoryPlacementCPUs, config.NUMAMemoryPlacementCPUsAndDevices:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid %s annotation value %q", crioann.NUMAMemoryPlacement, policy)
+	}
+}
+
+// setupContainerNUMAPlacement restricts the memory nodes of the container to
+// the NUMA nodes of its CPUs, and optionally of its CDI devices, if requested.
+// The chosen placement is recorded in the container annotations.
+func (s *Server) setupContainerNUMAPlacement(ctx context.Context, specgen *generate.Generator, sb *sandbox.Sandbox, ctrName string, cdiDevices []string) error {
+	policy, err := s.numaMemoryPlacementPolicy(sb, ctrName)
+	if err != nil || policy == "" {
+		return err
+	}
+
+	lspec := specgen.Config.Linux
+	if lspec == nil || lspec.Resources == nil || lspec.Resources.CPU == nil || lspec.Resources.CPU.Cpus == "" {
+		log.Warnf(ctx, "Skipping NUMA memory placement of container %s without cpuset", ctrName)
+
+		return nil
+	}
+
+	cpus, err := cpuset.Parse(lspec.Resources.CPU.Cpus)
+	if err != nil {
+		return fmt.Errorf("parse cpuset of container %s: %w", ctrName, err)
+	}
+
+	topology, err := cpuallocator.ReadTopology(cpuallocator.SysNodeDir)
+	if err != nil {
+		return err
+	}
+
+	if len(topology) == 0 {
+		log.Warnf(ctx, "Skipping NUMA memory placement of container %s: no NUMA topology found", ctrName)
+
+		return nil
+	}
+
+	nodes := topology.NodesOf(cpus)
+
+	if policy == config.NUMAMemoryPlacementCPUsAndDevices {
+		nodes = append(nodes, cdiDeviceNUMANodes(ctx, cdiDevices)...)
+	}
+
+	memoryNodes, err := numaMemoryNodes(nodes, lspec.Resources.CPU.Mems)
+	if err != nil {
+		return fmt.Errorf("parse memory nodes of container %s: %w", ctrName, err)
+	}
+
+	if memoryNodes.IsEmpty() {
+		log.Warnf(ctx, "Skipping NUMA memory placement of container %s: no NUMA node of its CPUs in the requested memory nodes %s", ctrName, lspec.Resources.CPU.Mems)
+
+		return nil
+	}
+
+	placement := &types.NUMAPlacement{
+		Policy:      policy,
+		CPUs:        cpus.String(),
+		MemoryNodes: memoryNodes.String(),
+	}
+
+	content, err := json.Marshal(placement)
+	if err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "Placing memory of container %s on NUMA nodes %s", ctrName, placement.MemoryNodes)
+	specgen.SetLinuxResourcesCPUMems(placement.MemoryNodes)
+	specgen.AddAnnotation(annotations.NUMAPlacement, string(content))
+
+	return nil
+}
+
+// numaMemoryNodes returns the memory nodes for the given NUMA nodes. If the
+// memory nodes were already requested, for example by the memory manager of
+// the kubelet, they are restricted to the requested ones.
+func numaMemoryNodes(nodes []int, requested string) (cpuset.CPUSet, error) {
+	res := cpuset.New(nodes...)
+	if requested == "" {
+		return res, nil
+	}
+
+	mems, err := cpuset.Parse(requested)
+	if err != nil {
+		return cpuset.New(), err
+	}
+
+	return res.Intersection(mems), nil
+}
+
+// cdiDeviceNames returns the names of the CDI devices requested for the
+// container, either using the CRI field or annotations.
+func cdiDeviceNames(ctx context.Context, ctrConfig *cri.ContainerConfig) []string {
+	res := []string{}
+	for _, device := range ctrConfig.GetCDIDevices() {
+		res = append(res, device.GetName())
+	}
+
+	_, annotated, err := cdi.ParseAnnotations(ctrConfig.GetAnnotations())
+	if err != nil {
+		log.Warnf(ctx, "Unable to parse CDI device annotations: %v", err)
+	}
+
+	for _, name := range annotated {
+		if !slices.Contains(res, name) {
+			res = append(res, name)
+		}
+	}
+
+	return res
+}
+
+// cdiDeviceNUMANodes returns the NUMA nodes of the device nodes of the CDI
+// devices. Devices without NUMA affinity are skipped.
+func cdiDeviceNUMANodes(ctx context.Context, cdiDevices []string) []int {
+	res := []int{}
+	cache := cdi.GetDefaultCache()
+
+	for _, name := range cdiDevices {
+		device := cache.GetDevice(name)
+		if device == nil {
+			continue
+		}
+
+		for _, node := range device.ContainerEdits.DeviceNodes {
+			path := node.HostPath
+			if path == "" {
+				path = node.Path
+			}
+
+			numaNode, err := deviceNUMANode(path)
+			if err != nil {
+				log.Warnf(ctx, "Unable to get NUMA node of device %s: %v", path, err)
+
+				continue
+			}
+
+			if numaNode >= 0 {
+				res = append(res, numaNode)
+			}
+		}
+	}
+
+	return res
+}
+
+// containerNUMAPlacement returns the NUMA memory placement recorded for the
+// container, nil if none.
+func containerNUMAPlacement(ctx context.Context, ctr *oci.Container) *types.NUMAPlacement {
+	value, ok := ctr.CrioAnnotations()[annotations.NUMAPlacement]
+	if !ok {
+		return nil
+	}
+
+	placement := &types.NUMAPlacement{}
+	if err := json.Unmarshal([]byte(value), placement); err != nil {
+		log.Warnf(ctx, "Unable to parse NUMA placement of container %s: %v", ctr.ID(), err)
+
+		return nil
+	}
+
+	return placement
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// sysDevDir is the sysfs directory containing the device numbers of the host.
+const sysDevDir = "/sys/dev"
+
+// deviceNUMANode returns the NUMA node of a device node, -1 if the device has
+// no NUMA affinity.
+func deviceNUMANode(path string) (int, error) {
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return -1, fmt.Errorf("stat device: %w", err)
+	}
+
+	var class string
+
+	switch stat.Mode & unix.S_IFMT {
+	case unix.S_IFCHR:
+		class = "char"
+	case unix.S_IFBLK:
+		class = "block"
+	default:
+		return -1, fmt.Errorf("%s is no device node", path)
+	}
+
+	return sysfsDeviceNUMANode(sysDevDir, class, unix.Major(stat.Rdev), unix.Minor(stat.Rdev))
+}
+
+// sysfsDeviceNUMANode reads the NUMA node of a device from sysfs. Devices
+// without NUMA information, like virtual devices, have no NUMA affinity.
+func sysfsDeviceNUMANode(dir, class string, major, minor uint32) (int, error) {
+	content, err := os.ReadFile(filepath.Join(dir, class, fmt.Sprintf("%d:%d", major, minor), "device", "numa_node"))
+	if os.IsNotExist(err) {
+		return -1, nil
+	}
+
+	if err != nil {
+		return -1, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(content)))
+}
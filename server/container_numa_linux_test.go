@@ -0,0 +1,53 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSysfsDeviceNUMANode(t *testing.T) {
+	dir := t.TempDir()
+
+	deviceDir := filepath.Join(dir, "char", "195:0", "device")
+	if err := os.MkdirAll(deviceDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(deviceDir, "numa_node"), []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		class    string
+		major    uint32
+		expected int
+	}{
+		{
+			name:     "device with NUMA affinity",
+			class:    "char",
+			major:    195,
+			expected: 1,
+		},
+		{
+			name:     "device without NUMA information",
+			class:    "block",
+			major:    8,
+			expected: -1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := sysfsDeviceNUMANode(dir, tc.class, tc.major, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res != tc.expected {
+				t.Errorf("expected NUMA node %d, got %d", tc.expected, res)
+			}
+		})
+	}
+}
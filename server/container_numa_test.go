@@ -0,0 +1,60 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestNUMAMemoryNodes(t *testing.T) {
+	cases := []struct {
+		name      string
+		nodes     []int
+		requested string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "no requested memory nodes",
+			nodes:    []int{0, 1},
+			expected: "0-1",
+		},
+		{
+			name:      "requested memory nodes",
+			nodes:     []int{0, 1},
+			requested: "1-2",
+			expected:  "1",
+		},
+		{
+			name:      "disjoint requested memory nodes",
+			nodes:     []int{0},
+			requested: "1",
+			expected:  "",
+		},
+		{
+			name:      "invalid requested memory nodes",
+			nodes:     []int{0},
+			requested: "invalid",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := numaMemoryNodes(tc.nodes, tc.requested)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.String() != tc.expected {
+				t.Errorf("expected memory nodes %q, got %q", tc.expected, res.String())
+			}
+		})
+	}
+}
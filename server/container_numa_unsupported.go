@@ -0,0 +1,8 @@
+//go:build !linux
+
+package server
+
+// deviceNUMANode is not supported on this platform.
+func deviceNUMANode(string) (int, error) {
+	return -1, nil
+}
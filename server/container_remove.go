@@ -83,6 +83,7 @@ func (s *Server) removeContainerInPod(ctx context.Context, sb *sandbox.Sandbox,
 
 	s.ReleaseContainerName(ctx, c.Name())
 	s.removeContainer(ctx, c)
+	s.releaseContainerCPUs(ctx, c.ID())
 
 	if err := s.ContainerServer.CtrIDIndex().Delete(c.ID()); err != nil {
 		return fmt.Errorf("failed to delete container %s in pod sandbox %s from index: %w", c.Name(), sb.ID(), err)
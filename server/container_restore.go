@@ -16,6 +16,7 @@ import (
 
 	"github.com/cri-o/cri-o/internal/annotations"
 	"github.com/cri-o/cri-o/internal/factory/container"
+	"github.com/cri-o/cri-o/internal/lib"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/storage"
@@ -108,9 +109,31 @@ func (s *Server) CRImportCheckpoint(
 			}
 		}()
 	} else {
+		mountPoint, err = os.MkdirTemp("", "checkpoint")
+		if err != nil {
+			return "", err
+		}
+
+		defer func() {
+			if err := os.RemoveAll(mountPoint); err != nil {
+				log.Errorf(ctx, "Could not recursively remove %s: %q", mountPoint, err)
+			}
+		}()
+
+		decryptConfig, err := getDecryptionKeys(s.config.DecryptionKeysPath)
+		if err != nil {
+			return "", fmt.Errorf("get checkpoint decryption keys: %w", err)
+		}
+
+		// Encrypted archives are decrypted next to the unpacked files
+		archivePath, err := lib.DecryptCheckpointArchive(decryptConfig, inputImage, mountPoint)
+		if err != nil {
+			return "", err
+		}
+
 		// First get the container definition from the
 		// tarball to a temporary directory
-		archiveFile, err := os.Open(inputImage)
+		archiveFile, err := os.Open(archivePath)
 		if err != nil {
 			return "", fmt.Errorf("failed to open checkpoint archive %s for import: %w", inputImage, err)
 		}
@@ -133,17 +156,6 @@ func (s *Server) CRImportCheckpoint(
 			},
 		}
 
-		mountPoint, err = os.MkdirTemp("", "checkpoint")
-		if err != nil {
-			return "", err
-		}
-
-		defer func() {
-			if err := os.RemoveAll(mountPoint); err != nil {
-				log.Errorf(ctx, "Could not recursively remove %s: %q", mountPoint, err)
-			}
-		}()
-
 		err = archive.Untar(archiveFile, mountPoint, options)
 		if err != nil {
 			return "", fmt.Errorf("unpacking of checkpoint archive %s failed: %w", mountPoint, err)
@@ -32,13 +32,25 @@ func (s *Server) StartContainer(ctx context.Context, req *types.StartContainerRe
 		// into the restore code.
 		log.Debugf(ctx, "Restoring container %q", req.GetContainerId())
 
+		decryptConfig, err := getDecryptionKeys(s.config.DecryptionKeysPath)
+		if err != nil {
+			return nil, fmt.Errorf("get checkpoint decryption keys: %w", err)
+		}
+
 		ctr, err := s.ContainerRestore(
 			ctx,
 			&metadata.ContainerConfig{
 				ID: c.ID(),
 			},
-			&lib.ContainerCheckpointOptions{},
+			&lib.ContainerCheckpointOptions{
+				DecryptConfig: decryptConfig,
+			},
 		)
+
+		if sb := s.getSandbox(ctx, c.Sandbox()); sb != nil {
+			s.removePodCheckpointContainerArchive(ctx, sb, c.RestoreArchivePath())
+		}
+
 		if err != nil {
 			ociContainer, err1 := s.GetContainerFromShortID(ctx, c.ID())
 			if err1 != nil {
@@ -109,7 +121,10 @@ func (s *Server) StartContainer(ctx context.Context, req *types.StartContainerRe
 		}
 	}
 
-	if err := s.ContainerServer.Runtime().StartContainer(ctx, c); err != nil {
+	err = s.ContainerServer.Runtime().StartContainer(ctx, c)
+	s.runtimeHandlerContainersInc(sandbox, c, "start", err)
+
+	if err != nil {
 		return nil, fmt.Errorf("failed to start container %s: %w", c.ID(), err)
 	}
 
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"math"
 	"net/http"
 	"net/http/pprof"
 	"os"
 	"runtime/debug"
+	"slices"
 
 	"github.com/go-chi/chi/v5"
 	json "github.com/json-iterator/go"
@@ -55,6 +57,27 @@ func (s *Server) getInfo() types.CrioInfo {
 	}
 }
 
+// getRuntimesInfo returns the configured runtime handlers sorted by name.
+func (s *Server) getRuntimesInfo() []types.RuntimeHandlerInfo {
+	info := []types.RuntimeHandlerInfo{}
+
+	for _, name := range slices.Sorted(maps.Keys(s.config.Runtimes)) {
+		handler := s.config.Runtimes[name]
+
+		info = append(info, types.RuntimeHandlerInfo{
+			Name:                    name,
+			Default:                 name == s.config.DefaultRuntime,
+			RuntimePath:             handler.RuntimePath,
+			RuntimeType:             handler.RuntimeType,
+			RecursiveReadOnlyMounts: handler.RuntimeSupportsRROMounts(),
+			UserNamespaces:          handler.RuntimeSupportsIDMap(),
+			Features:                handler.RuntimeFeatures(),
+		})
+	}
+
+	return info
+}
+
 var (
 	errCtrNotFound     = errors.New("container not found")
 	errCtrStateNil     = errors.New("container state is nil")
@@ -128,6 +151,7 @@ func (s *Server) getContainerInfo(ctx context.Context, id string, getContainerFu
 		Sandbox:         ctr.Sandbox(),
 		IPs:             sb.IPs(),
 		HostNetwork:     new(sb.HostNetwork()),
+		NUMAPlacement:   containerNUMAPlacement(ctx, ctr),
 	}, nil
 }
 
@@ -137,8 +161,13 @@ const (
 	InspectInfoEndpoint       = "/info"
 	InspectPauseEndpoint      = "/pause"
 	InspectUnpauseEndpoint    = "/unpause"
+	InspectFreezeEndpoint     = "/freeze"
+	InspectThawEndpoint       = "/thaw"
+	InspectCheckpointEndpoint = "/checkpoint"
 	InspectGoRoutinesEndpoint = "/debug/goroutines"
 	InspectHeapEndpoint       = "/debug/heap"
+	InspectUsernsEndpoint     = "/userns"
+	InspectRuntimesEndpoint   = "/runtimes"
 )
 
 // GetExtendInterfaceMux returns the mux used to serve extend interface requests.
@@ -177,6 +206,36 @@ func (s *Server) GetExtendInterfaceMux(enableProfile bool) *chi.Mux {
 		}
 	}))
 
+	mux.Get(InspectUsernsEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		js, err := json.Marshal(s.getUsernsInfo())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectRuntimesEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		js, err := json.Marshal(s.getRuntimesInfo())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
 	mux.Get(InspectContainersEndpoint+"/{id}", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := context.TODO()
 		containerID := chi.URLParam(req, "id")
@@ -289,6 +348,44 @@ func (s *Server) GetExtendInterfaceMux(enableProfile bool) *chi.Mux {
 		}
 	}))
 
+	mux.Get(InspectFreezeEndpoint+"/{id}", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.handleSandboxFreezer(w, chi.URLParam(req, "id"), true)
+	}))
+
+	mux.Get(InspectThawEndpoint+"/{id}", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.handleSandboxFreezer(w, chi.URLParam(req, "id"), false)
+	}))
+
+	mux.Get(InspectCheckpointEndpoint+"/{id}", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sandboxID := chi.URLParam(req, "id")
+		ctx := context.TODO()
+
+		if _, err := s.getPodSandboxFromRequest(ctx, sandboxID); err != nil {
+			http.Error(w, "can't find the sandbox with id "+sandboxID, http.StatusNotFound)
+
+			return
+		}
+
+		location := req.URL.Query().Get("location")
+		if location == "" {
+			http.Error(w, "no checkpoint location provided", http.StatusBadRequest)
+
+			return
+		}
+
+		if err := s.CheckpointPodSandbox(s.stream.ctx, sandboxID, location); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+
+		if _, err := w.Write([]byte("200 OK")); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
 	mux.Get(InspectGoRoutinesEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
 
@@ -337,3 +434,66 @@ func (s *Server) GetExtendInterfaceMux(enableProfile bool) *chi.Mux {
 
 	return mux
 }
+
+// handleSandboxFreezer freezes or thaws the pod sandbox with the provided ID
+// and responds with the resulting freezer state of the sandbox.
+func (s *Server) handleSandboxFreezer(w http.ResponseWriter, sandboxID string, freeze bool) {
+	ctx := context.TODO()
+
+	sb, err := s.getPodSandboxFromRequest(ctx, sandboxID)
+	if err != nil {
+		http.Error(w, "can't find the sandbox with id "+sandboxID, http.StatusNotFound)
+
+		return
+	}
+
+	if sb.Stopped() {
+		http.Error(w, "sandbox is stopped", http.StatusConflict)
+
+		return
+	}
+
+	frozen, err := s.sandboxFrozen(sb)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	if freeze && frozen {
+		http.Error(w, "sandbox is already frozen", http.StatusConflict)
+
+		return
+	}
+
+	if !freeze && !frozen {
+		http.Error(w, "sandbox is not frozen", http.StatusConflict)
+
+		return
+	}
+
+	if freeze {
+		err = s.freezePodSandbox(s.stream.ctx, sb)
+	} else {
+		err = s.thawPodSandbox(s.stream.ctx, sb)
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	js, err := json.Marshal(types.SandboxFreezerInfo{ID: sb.ID(), Frozen: freeze})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, err := w.Write(js); err != nil {
+		logrus.Errorf("Unable to write response JSON: %v", err)
+	}
+}
@@ -255,5 +255,82 @@ var _ = t.Describe("Inspect", func() {
 			Expect(request).NotTo(BeNil())
 			Expect(recorder.Code).To(BeEquivalentTo(http.StatusConflict))
 		})
+
+		It("should fail with empty on /freeze route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/freeze", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusNotFound))
+		})
+
+		It("should fail with invalid sandbox ID on /freeze route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/freeze/123", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusNotFound))
+		})
+
+		It("should fail with invalid sandbox ID on /thaw route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/thaw/123", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusNotFound))
+		})
+
+		It("should fail with invalid sandbox ID on /checkpoint route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/checkpoint/123?location=/tmp/pod.tar", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusNotFound))
+		})
+
+		It("should fail without location on /checkpoint route", func() {
+			// Given
+			addContainerAndSandbox()
+
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/checkpoint/"+testSandbox.ID(), http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusBadRequest))
+		})
+
+		It("should fail if pod checkpointing is disabled on /checkpoint route", func() {
+			// Given
+			addContainerAndSandbox()
+
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/checkpoint/"+testSandbox.ID()+"?location=/tmp/pod.tar", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusInternalServerError))
+			Expect(recorder.Body.String()).To(ContainSubstring("not available"))
+		})
 	})
 })
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/config/node"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/pkg/config"
+)
+
+const (
+	// memoryHighInterval is the interval in which the memory pressure of the
+	// containers is checked, matching the window of the used average.
+	memoryHighInterval = 10 * time.Second
+
+	// memoryHighSteps is the number of steps between no throttling and the
+	// memory limit, in which memory.high gets adjusted.
+	memoryHighSteps = 10
+)
+
+// startMemoryHighMonitor starts a routine adjusting the memory.high of the
+// containers whose workload configures a memory pressure threshold. The
+// memory.high is relaxed towards the memory limit while the container stalls
+// above the threshold, and tightened back towards the configured value once
+// the pressure eased, to throttle the container before it gets OOM killed.
+func (s *Server) startMemoryHighMonitor(ctx context.Context) {
+	if len(s.config.Workloads) == 0 || !node.CgroupIsV2() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(memoryHighInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.monitorsChan:
+				return
+			case <-ticker.C:
+				s.adjustMemoryHigh(ctx)
+			}
+		}
+	}()
+}
+
+// adjustMemoryHigh adjusts the memory.high of the running containers based
+// on their memory pressure.
+func (s *Server) adjustMemoryHigh(ctx context.Context) {
+	for _, sb := range s.ContainerServer.ListSandboxes() {
+		for _, ctr := range sb.Containers().List() {
+			if ctr.State().Status != oci.ContainerStateRunning {
+				continue
+			}
+
+			resources, err := s.config.Workloads.ContainerResources(ctr.Metadata().GetName(), sb.Annotations())
+			if err != nil || resources == nil || resources.MemoryHighPressureThreshold == 0 {
+				continue
+			}
+
+			base, limit := containerMemoryHighAndLimit(ctr)
+			if base <= 0 || limit <= 0 {
+				continue
+			}
+
+			pressure, err := s.containerMemoryPressure(sb, ctr)
+			if err != nil {
+				log.Debugf(ctx, "Unable to get memory pressure of container %s: %v", ctr.ID(), err)
+
+				continue
+			}
+
+			current, err := s.containerMemoryHigh(sb, ctr)
+			if err != nil {
+				log.Debugf(ctx, "Unable to get memory.high of container %s: %v", ctr.ID(), err)
+
+				continue
+			}
+
+			next := nextMemoryHigh(current, base, limit, pressure, resources.MemoryHighPressureThreshold)
+			if next == current {
+				continue
+			}
+
+			log.Infof(ctx, "Adjusting memory.high of container %s from %d to %d at memory pressure %.2f%%", ctr.ID(), current, next, pressure)
+
+			if err := s.setContainerMemoryHigh(sb, ctr, next); err != nil {
+				log.Warnf(ctx, "Unable to set memory.high of container %s: %v", ctr.ID(), err)
+			}
+		}
+	}
+}
+
+// containerMemoryHighAndLimit returns the memory.high and the memory limit
+// of the container spec, or zero if not set.
+func containerMemoryHighAndLimit(ctr *oci.Container) (memoryHigh, limit int64) {
+	spec := ctr.Spec()
+	if spec.Linux == nil || spec.Linux.Resources == nil {
+		return 0, 0
+	}
+
+	if memory := spec.Linux.Resources.Memory; memory != nil && memory.Limit != nil {
+		limit = *memory.Limit
+	}
+
+	if parsed, err := strconv.ParseInt(spec.Linux.Resources.Unified[config.MemoryHighFile], 10, 64); err == nil {
+		memoryHigh = parsed
+	}
+
+	return memoryHigh, limit
+}
+
+// nextMemoryHigh returns the memory.high of a container with the current
+// memory.high and pressure. The memory.high is relaxed by a step while the
+// pressure is above the threshold and tightened by a step while it is below
+// half of the threshold, staying between the base memory.high and the limit.
+func nextMemoryHigh(current, base, limit int64, pressure, threshold float64) int64 {
+	step := max(limit/memoryHighSteps, 1)
+	current = min(max(current, base), limit)
+
+	switch {
+	case pressure >= threshold:
+		return min(current+step, limit)
+	case pressure < threshold/2:
+		return max(current-step, base)
+	default:
+		return current
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/cgroups"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/pkg/config"
+)
+
+// containerMemoryHigh returns the current memory.high of the container
+// cgroup, which is math.MaxInt64 if not limited.
+func (s *Server) containerMemoryHigh(sb *sandbox.Sandbox, ctr *oci.Container) (int64, error) {
+	cgMgr, err := s.config.CgroupManager().ContainerCgroupManager(sb.CgroupParent(), ctr.ID())
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := cgroups.ReadFile(cgMgr.Path(""), config.MemoryHighFile)
+	if err != nil {
+		return 0, err
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "max" {
+		return math.MaxInt64, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// setContainerMemoryHigh sets the memory.high of the container cgroup.
+func (s *Server) setContainerMemoryHigh(sb *sandbox.Sandbox, ctr *oci.Container, memoryHigh int64) error {
+	cgMgr, err := s.config.CgroupManager().ContainerCgroupManager(sb.CgroupParent(), ctr.ID())
+	if err != nil {
+		return err
+	}
+
+	return cgroups.WriteFile(cgMgr.Path(""), config.MemoryHighFile, strconv.FormatInt(memoryHigh, 10))
+}
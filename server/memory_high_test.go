@@ -0,0 +1,66 @@
+package server
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNextMemoryHigh(t *testing.T) {
+	const (
+		base      = 600
+		limit     = 1000
+		threshold = 20
+	)
+
+	cases := []struct {
+		name     string
+		current  int64
+		pressure float64
+		expected int64
+	}{
+		{
+			name:     "relax above the threshold",
+			current:  600,
+			pressure: 25,
+			expected: 700,
+		},
+		{
+			name:     "relax up to the limit",
+			current:  950,
+			pressure: 25,
+			expected: 1000,
+		},
+		{
+			name:     "keep between half the threshold and the threshold",
+			current:  800,
+			pressure: 15,
+			expected: 800,
+		},
+		{
+			name:     "tighten below half the threshold",
+			current:  800,
+			pressure: 5,
+			expected: 700,
+		},
+		{
+			name:     "tighten down to the base",
+			current:  650,
+			pressure: 0,
+			expected: 600,
+		},
+		{
+			name:     "restore an unlimited memory.high",
+			current:  math.MaxInt64,
+			pressure: 25,
+			expected: 1000,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if next := nextMemoryHigh(tc.current, base, limit, tc.pressure, threshold); next != tc.expected {
+				t.Fatalf("Expected memory.high %d, got %d", tc.expected, next)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package server
+
+import (
+	"errors"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/oci"
+)
+
+// containerMemoryHigh is not supported on this platform.
+func (s *Server) containerMemoryHigh(*sandbox.Sandbox, *oci.Container) (int64, error) {
+	return 0, errors.New("memory.high not supported")
+}
+
+// setContainerMemoryHigh is not supported on this platform.
+func (s *Server) setContainerMemoryHigh(*sandbox.Sandbox, *oci.Container, int64) error {
+	return errors.New("memory.high not supported")
+}
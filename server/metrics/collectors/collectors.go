@@ -69,6 +69,36 @@ const (
 
 	// DefaultRuntime is the key for the default container runtime configured in CRI-O.
 	DefaultRuntime Collector = crioPrefix + "default_runtime"
+
+	// CNIOperationsLatencySeconds is the key for the CNI plugin operation latency metrics per network.
+	CNIOperationsLatencySeconds Collector = crioPrefix + "cni_operations_latency_seconds"
+
+	// CNIOperationsErrorsTotal is the key for the CNI plugin operation errors per network.
+	CNIOperationsErrorsTotal Collector = crioPrefix + "cni_operations_errors_total"
+
+	// DNSCacheQueriesTotal is the key for the DNS cache queries per pod and result.
+	DNSCacheQueriesTotal Collector = crioPrefix + "dns_cache_queries_total"
+
+	// CheckpointPhaseDurationSeconds is the key for the duration of the container checkpoint phases.
+	CheckpointPhaseDurationSeconds Collector = crioPrefix + "checkpoint_phase_duration_seconds"
+
+	// CheckpointPhaseSizeBytes is the key for the size of the images written by the container checkpoint phases.
+	CheckpointPhaseSizeBytes Collector = crioPrefix + "checkpoint_phase_size_bytes"
+
+	// RuntimeHandlerSandboxesTotal is the key for the pod sandboxes created per runtime handler.
+	RuntimeHandlerSandboxesTotal Collector = crioPrefix + "runtime_handler_sandboxes_total"
+
+	// RuntimeHandlerFallbacksTotal is the key for the pod sandboxes retried with the fallback runtime handler.
+	RuntimeHandlerFallbacksTotal Collector = crioPrefix + "runtime_handler_fallbacks_total"
+
+	// RuntimeHandlerContainersTotal is the key for the containers created and started per runtime handler.
+	RuntimeHandlerContainersTotal Collector = crioPrefix + "runtime_handler_containers_total"
+
+	// StatsCollectionDurationSeconds is the key for the duration of the pod and container stats collections.
+	StatsCollectionDurationSeconds Collector = crioPrefix + "stats_collection_duration_seconds"
+
+	// StatsCollectionCgroupsReadTotal is the key for the cgroups read by the pod and container stats collections.
+	StatsCollectionCgroupsReadTotal Collector = crioPrefix + "stats_collection_cgroups_read_total"
 )
 
 // FromSlice converts a string slice to a Collectors type.
@@ -111,6 +141,16 @@ func All() Collectors {
 		ResourcesStalledAtStage.Stripped(),
 		ContainersStoppedMonitorCount.Stripped(),
 		DefaultRuntime.Stripped(),
+		CNIOperationsLatencySeconds.Stripped(),
+		CNIOperationsErrorsTotal.Stripped(),
+		DNSCacheQueriesTotal.Stripped(),
+		CheckpointPhaseDurationSeconds.Stripped(),
+		CheckpointPhaseSizeBytes.Stripped(),
+		RuntimeHandlerSandboxesTotal.Stripped(),
+		RuntimeHandlerFallbacksTotal.Stripped(),
+		RuntimeHandlerContainersTotal.Stripped(),
+		StatsCollectionDurationSeconds.Stripped(),
+		StatsCollectionCgroupsReadTotal.Stripped(),
 	}
 }
 
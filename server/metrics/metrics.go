@@ -84,6 +84,16 @@ type Metrics struct {
 	metricResourcesStalledAtStage             *prometheus.CounterVec
 	metricContainersStoppedMonitorCount       *prometheus.CounterVec
 	metricDefaultRuntime                      *prometheus.GaugeVec
+	metricCNIOperationsLatencySeconds         *prometheus.HistogramVec
+	metricCNIOperationsErrorsTotal            *prometheus.CounterVec
+	metricDNSCacheQueriesTotal                *prometheus.CounterVec
+	metricCheckpointPhaseDurationSeconds      *prometheus.HistogramVec
+	metricCheckpointPhaseSizeBytes            *prometheus.HistogramVec
+	metricRuntimeHandlerSandboxesTotal        *prometheus.CounterVec
+	metricRuntimeHandlerFallbacksTotal        *prometheus.CounterVec
+	metricRuntimeHandlerContainersTotal       *prometheus.CounterVec
+	metricStatsCollectionDurationSeconds      *prometheus.HistogramVec
+	metricStatsCollectionCgroupsReadTotal     *prometheus.CounterVec
 }
 
 var instance *Metrics
@@ -259,6 +269,90 @@ func New(config *libconfig.MetricsConfig, apiConfig *libconfig.APIConfig) *Metri
 			},
 			[]string{"runtime"},
 		),
+		metricCNIOperationsLatencySeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.CNIOperationsLatencySeconds.String(),
+				Help:      "Latency in seconds of CNI plugin operations. Broken down by operation type (ADD, DEL, CHECK, GC), network and plugin type.",
+				Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+			},
+			[]string{"operation", "network", "plugin"},
+		),
+		metricCNIOperationsErrorsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.CNIOperationsErrorsTotal.String(),
+				Help:      "Cumulative number of CNI plugin operation failures by operation type (ADD, DEL, CHECK, GC), network and plugin type.",
+			},
+			[]string{"operation", "network", "plugin"},
+		),
+		metricDNSCacheQueriesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.DNSCacheQueriesTotal.String(),
+				Help:      "Cumulative number of DNS queries served by the DNS cache by pod and result (hit, miss, error).",
+			},
+			[]string{"namespace", "pod", "result"},
+		),
+		metricCheckpointPhaseDurationSeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.CheckpointPhaseDurationSeconds.String(),
+				Help:      "Duration in seconds of the container checkpoint phases (pre_dump, dump, export).",
+				Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+			},
+			[]string{"phase"},
+		),
+		metricCheckpointPhaseSizeBytes: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.CheckpointPhaseSizeBytes.String(),
+				Help:      "Size in bytes of the CRIU images written by the container checkpoint phases (pre_dump, dump).",
+				Buckets:   prometheus.ExponentialBuckets(1024*1024, 4, 10),
+			},
+			[]string{"phase"},
+		),
+		metricRuntimeHandlerSandboxesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.RuntimeHandlerSandboxesTotal.String(),
+				Help:      "Cumulative number of pod sandboxes created by runtime handler, whether the canary runtime got used and result (success, failure).",
+			},
+			[]string{"runtime_handler", "canary", "result"},
+		),
+		metricRuntimeHandlerFallbacksTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.RuntimeHandlerFallbacksTotal.String(),
+				Help:      "Cumulative number of pod sandboxes retried with the fallback runtime handler after a runtime error.",
+			},
+			[]string{"runtime_handler", "fallback_runtime_handler"},
+		),
+		metricRuntimeHandlerContainersTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.RuntimeHandlerContainersTotal.String(),
+				Help:      "Cumulative number of container runtime operations (create, start) by runtime handler, whether the canary runtime got used and result (success, failure).",
+			},
+			[]string{"runtime_handler", "canary", "operation", "result"},
+		),
+		metricStatsCollectionDurationSeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.StatsCollectionDurationSeconds.String(),
+				Help:      "Duration in seconds of the pod and container stats collections by mode (periodic, adaptive, on_demand).",
+				Buckets:   []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			},
+			[]string{"mode"},
+		),
+		metricStatsCollectionCgroupsReadTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.StatsCollectionCgroupsReadTotal.String(),
+				Help:      "Cumulative number of cgroups read by the pod and container stats collections by mode (periodic, adaptive, on_demand).",
+			},
+			[]string{"mode"},
+		),
 	}
 
 	return Instance()
@@ -474,6 +568,118 @@ func (m *Metrics) MetricDefaultRuntimeSet(runtime string) {
 	g.Set(1)
 }
 
+func (m *Metrics) MetricCNIOperationsLatencyObserve(operation, network, plugin string, start time.Time) {
+	o, err := m.metricCNIOperationsLatencySeconds.GetMetricWithLabelValues(operation, network, plugin)
+	if err != nil {
+		logrus.Warnf("Unable to write CNI operation latency metric: %v", err)
+
+		return
+	}
+
+	o.Observe(SinceInSeconds(start))
+}
+
+func (m *Metrics) MetricCNIOperationsErrorsInc(operation, network, plugin string) {
+	c, err := m.metricCNIOperationsErrorsTotal.GetMetricWithLabelValues(operation, network, plugin)
+	if err != nil {
+		logrus.Warnf("Unable to write CNI operation errors metric: %v", err)
+
+		return
+	}
+
+	c.Inc()
+}
+
+func (m *Metrics) MetricDNSCacheQueriesInc(namespace, pod, result string) {
+	c, err := m.metricDNSCacheQueriesTotal.GetMetricWithLabelValues(namespace, pod, result)
+	if err != nil {
+		logrus.Warnf("Unable to write DNS cache queries metric: %v", err)
+
+		return
+	}
+
+	c.Inc()
+}
+
+func (m *Metrics) MetricDNSCacheQueriesDelete(namespace, pod string) {
+	m.metricDNSCacheQueriesTotal.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "pod": pod})
+}
+
+func (m *Metrics) MetricCheckpointPhaseDurationObserve(phase string, start time.Time) {
+	o, err := m.metricCheckpointPhaseDurationSeconds.GetMetricWithLabelValues(phase)
+	if err != nil {
+		logrus.Warnf("Unable to write checkpoint phase duration metric: %v", err)
+
+		return
+	}
+
+	o.Observe(SinceInSeconds(start))
+}
+
+func (m *Metrics) MetricCheckpointPhaseSizeObserve(phase string, size int64) {
+	o, err := m.metricCheckpointPhaseSizeBytes.GetMetricWithLabelValues(phase)
+	if err != nil {
+		logrus.Warnf("Unable to write checkpoint phase size metric: %v", err)
+
+		return
+	}
+
+	o.Observe(float64(size))
+}
+
+func (m *Metrics) MetricRuntimeHandlerSandboxesInc(runtimeHandler string, canary bool, result string) {
+	c, err := m.metricRuntimeHandlerSandboxesTotal.GetMetricWithLabelValues(runtimeHandler, strconv.FormatBool(canary), result)
+	if err != nil {
+		logrus.Warnf("Unable to write runtime handler sandboxes metric: %v", err)
+
+		return
+	}
+
+	c.Inc()
+}
+
+func (m *Metrics) MetricRuntimeHandlerFallbacksInc(runtimeHandler, fallbackRuntimeHandler string) {
+	c, err := m.metricRuntimeHandlerFallbacksTotal.GetMetricWithLabelValues(runtimeHandler, fallbackRuntimeHandler)
+	if err != nil {
+		logrus.Warnf("Unable to write runtime handler fallbacks metric: %v", err)
+
+		return
+	}
+
+	c.Inc()
+}
+
+func (m *Metrics) MetricRuntimeHandlerContainersInc(runtimeHandler string, canary bool, operation, result string) {
+	c, err := m.metricRuntimeHandlerContainersTotal.GetMetricWithLabelValues(runtimeHandler, strconv.FormatBool(canary), operation, result)
+	if err != nil {
+		logrus.Warnf("Unable to write runtime handler containers metric: %v", err)
+
+		return
+	}
+
+	c.Inc()
+}
+
+func (m *Metrics) MetricStatsCollectionObserve(mode string, start time.Time, cgroupsRead int) {
+	o, err := m.metricStatsCollectionDurationSeconds.GetMetricWithLabelValues(mode)
+	if err != nil {
+		logrus.Warnf("Unable to write stats collection duration metric: %v", err)
+
+		return
+	}
+
+	o.Observe(SinceInSeconds(start))
+
+	c, err := m.metricStatsCollectionCgroupsReadTotal.GetMetricWithLabelValues(mode)
+	if err != nil {
+		logrus.Warnf("Unable to write stats collection cgroups read metric: %v", err)
+
+		return
+	}
+
+	c.Add(float64(cgroupsRead))
+}
+
 // createEndpoint creates a /metrics endpoint for prometheus monitoring.
 func (m *Metrics) createEndpoint() (*http.ServeMux, error) {
 	for collector, metric := range map[collectors.Collector]prometheus.Collector{
@@ -495,6 +701,16 @@ func (m *Metrics) createEndpoint() (*http.ServeMux, error) {
 		collectors.ResourcesStalledAtStage:             m.metricResourcesStalledAtStage,
 		collectors.ContainersStoppedMonitorCount:       m.metricContainersStoppedMonitorCount,
 		collectors.DefaultRuntime:                      m.metricDefaultRuntime,
+		collectors.CNIOperationsLatencySeconds:         m.metricCNIOperationsLatencySeconds,
+		collectors.CNIOperationsErrorsTotal:            m.metricCNIOperationsErrorsTotal,
+		collectors.DNSCacheQueriesTotal:                m.metricDNSCacheQueriesTotal,
+		collectors.CheckpointPhaseDurationSeconds:      m.metricCheckpointPhaseDurationSeconds,
+		collectors.CheckpointPhaseSizeBytes:            m.metricCheckpointPhaseSizeBytes,
+		collectors.RuntimeHandlerSandboxesTotal:        m.metricRuntimeHandlerSandboxesTotal,
+		collectors.RuntimeHandlerFallbacksTotal:        m.metricRuntimeHandlerFallbacksTotal,
+		collectors.RuntimeHandlerContainersTotal:       m.metricRuntimeHandlerContainersTotal,
+		collectors.StatsCollectionDurationSeconds:      m.metricStatsCollectionDurationSeconds,
+		collectors.StatsCollectionCgroupsReadTotal:     m.metricStatsCollectionCgroupsReadTotal,
 	} {
 		if m.config.MetricsCollectors.Contains(collector) {
 			logrus.Debugf("Enabling metric: %s", collector.Stripped())
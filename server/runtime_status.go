@@ -87,5 +87,13 @@ func (s *Server) createRuntimeInfo() (map[string]string, error) {
 		return nil, fmt.Errorf("marshal data: %w", err)
 	}
 
-	return map[string]string{"config": string(bytes)}, nil
+	runtimeHandlers, err := json.Marshal(s.getRuntimesInfo())
+	if err != nil {
+		return nil, fmt.Errorf("marshal runtime handlers: %w", err)
+	}
+
+	return map[string]string{
+		"config":          string(bytes),
+		"runtimeHandlers": string(runtimeHandlers),
+	}, nil
 }
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	v2 "github.com/cri-o/cri-o/pkg/annotations/v2"
+)
+
+var errPodCheckpointNotEnabled = errors.New("pod checkpoint/restore support not available")
+
+// podRestoreDirectoryName is the directory below the storage root into which
+// the pod checkpoint archives of restored sandboxes are extracted.
+const podRestoreDirectoryName = "crio-pod-restore"
+
+// CheckpointPodSandbox checkpoints all running containers of a pod sandbox
+// together with its metadata into one archive at the provided location.
+func (s *Server) CheckpointPodSandbox(ctx context.Context, sandboxID, location string) error {
+	if !s.config.CheckpointPodEnabled() {
+		return errPodCheckpointNotEnabled
+	}
+
+	sb, err := s.getPodSandboxFromRequest(ctx, sandboxID)
+	if err != nil {
+		return fmt.Errorf("could not find pod sandbox %q: %w", sandboxID, err)
+	}
+
+	if sb.Stopped() {
+		return fmt.Errorf("pod sandbox %s is stopped", sb.ID())
+	}
+
+	encryptConfig, err := getEncryptionKeys(s.config.EncryptionKeysPath)
+	if err != nil {
+		return fmt.Errorf("get checkpoint encryption keys: %w", err)
+	}
+
+	log.Infof(ctx, "Checkpointing pod sandbox: %s", sb.ID())
+
+	// Freezing the whole pod keeps the containers consistent with each other
+	// and with the shared memory while they are checkpointed one by one.
+	if err := s.freezePodSandbox(ctx, sb); err != nil {
+		log.Warnf(ctx, "Unable to freeze pod sandbox %s for checkpointing: %v", sb.ID(), err)
+	} else {
+		defer func() {
+			if err := s.thawPodSandbox(ctx, sb); err != nil {
+				log.Errorf(ctx, "Unable to thaw pod sandbox %s after checkpointing: %v", sb.ID(), err)
+			}
+		}()
+	}
+
+	var networks []string
+	if !sb.HostNetwork() {
+		networks = []string{s.config.CNIPlugin().GetDefaultNetworkName()}
+	}
+
+	if err := s.PodCheckpoint(ctx, sb, &lib.PodCheckpointOptions{
+		TargetFile:    location,
+		Compression:   s.config.CheckpointRestoreConfig.Compression.Archive(),
+		EncryptConfig: encryptConfig,
+		Networks:      networks,
+	}); err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "Checkpointed pod sandbox: %s", sb.ID())
+
+	return nil
+}
+
+// podRestoreDirectory returns the directory into which the pod checkpoint
+// archive of a restored sandbox is extracted. It may contain every memory page
+// of the pod, so it is placed below the storage root instead of a tmpfs.
+func (s *Server) podRestoreDirectory(sandboxID string) string {
+	return filepath.Join(s.Store().GraphRoot(), podRestoreDirectoryName, sandboxID)
+}
+
+// removePodRestoreDirectory removes the extracted pod checkpoint archive of a
+// restored sandbox.
+func (s *Server) removePodRestoreDirectory(ctx context.Context, sb *sandbox.Sandbox) {
+	if _, ok := v2.GetAnnotationValue(sb.Annotations(), v2.RestorePod); !ok {
+		return
+	}
+
+	if err := os.RemoveAll(s.podRestoreDirectory(sb.ID())); err != nil {
+		log.Warnf(ctx, "Unable to remove pod restore directory of sandbox %s: %v", sb.ID(), err)
+	}
+}
+
+// removePodCheckpointContainerArchive removes the checkpoint archive of a
+// container restored from a pod checkpoint archive after its restore, and
+// the whole extracted pod checkpoint once all containers got restored.
+func (s *Server) removePodCheckpointContainerArchive(ctx context.Context, sb *sandbox.Sandbox, archivePath string) {
+	if archivePath == "" {
+		return
+	}
+
+	dir := s.podRestoreDirectory(sb.ID())
+	containersDir := filepath.Join(dir, lib.PodCheckpointContainersDirectory)
+
+	if filepath.Dir(archivePath) != containersDir {
+		return
+	}
+
+	if err := os.Remove(archivePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warnf(ctx, "Unable to remove container checkpoint archive %s: %v", archivePath, err)
+	}
+
+	entries, err := os.ReadDir(containersDir)
+	if err != nil || len(entries) > 0 {
+		return
+	}
+
+	log.Debugf(ctx, "All containers of pod sandbox %s restored, removing %s", sb.ID(), dir)
+	s.removePodRestoreDirectory(ctx, sb)
+}
+
+// importPodCheckpoint extracts the pod checkpoint archive referenced by the
+// restore-pod.crio.io annotation of a new sandbox and applies the network
+// configuration stored in it to the sandbox config, before the sandbox and
+// its network get set up. It returns nil if the sandbox is not restored.
+func (s *Server) importPodCheckpoint(ctx context.Context, sandboxID string, kubeAnnotations map[string]string, sandboxConfig *types.PodSandboxConfig) (_ *lib.PodCheckpointConfig, retErr error) {
+	archivePath, ok := v2.GetAnnotationValue(kubeAnnotations, v2.RestorePod)
+	if !ok {
+		return nil, nil
+	}
+
+	if !s.config.RestorePodEnabled() {
+		return nil, errPodCheckpointNotEnabled
+	}
+
+	log.Infof(ctx, "Importing pod checkpoint %s for pod sandbox %s", archivePath, sandboxID)
+
+	decryptConfig, err := getDecryptionKeys(s.config.DecryptionKeysPath)
+	if err != nil {
+		return nil, fmt.Errorf("get checkpoint decryption keys: %w", err)
+	}
+
+	dir := s.podRestoreDirectory(sandboxID)
+
+	defer func() {
+		if retErr != nil {
+			if err := os.RemoveAll(dir); err != nil {
+				log.Warnf(ctx, "Unable to remove pod restore directory of sandbox %s: %v", sandboxID, err)
+			}
+		}
+	}()
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create pod restore directory: %w", err)
+	}
+
+	// The decrypted archive is removed right after its extraction to not
+	// keep a plain copy of the whole pod checkpoint.
+	plainArchivePath, err := lib.DecryptCheckpointArchive(decryptConfig, archivePath, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := lib.ImportPodCheckpoint(plainArchivePath, dir)
+
+	if plainArchivePath != archivePath {
+		if err := os.Remove(plainArchivePath); err != nil {
+			log.Warnf(ctx, "Unable to remove decrypted pod checkpoint archive %s: %v", plainArchivePath, err)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Pods in the network namespace of the host are not attached to any
+	// network.
+	network := ""
+	if sandboxConfig.GetLinux().GetSecurityContext().GetNamespaceOptions().GetNetwork() != types.NamespaceMode_NODE {
+		network = s.config.CNIPlugin().GetDefaultNetworkName()
+	}
+
+	if err := applyPodCheckpointNetwork(config, sandboxConfig, network); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// applyPodCheckpointNetwork applies the DNS configuration and port mappings
+// of the checkpointed pod to the sandbox config, unless the restore request
+// sets them itself. The restored pod gets attached to the network, which must
+// be one of the networks of the checkpointed pod, if both are attached to any.
+func applyPodCheckpointNetwork(config *lib.PodCheckpointConfig, sandboxConfig *types.PodSandboxConfig, network string) error {
+	dnsConfig := sandboxConfig.GetDnsConfig()
+	if len(dnsConfig.GetServers()) == 0 && len(dnsConfig.GetSearches()) == 0 && len(dnsConfig.GetOptions()) == 0 && config.DNSConfig != nil {
+		sandboxConfig.DnsConfig = config.DNSConfig
+	}
+
+	if len(sandboxConfig.GetPortMappings()) == 0 && len(config.PortMappings) > 0 {
+		sandboxConfig.PortMappings = criPortMappings(config.PortMappings)
+	}
+
+	if network == "" || len(config.Networks) == 0 {
+		return nil
+	}
+
+	if !slices.Contains(config.Networks, network) {
+		return fmt.Errorf("checkpointed pod sandbox %s is attached to the networks %v, but not to the network %q", config.ID, config.Networks, network)
+	}
+
+	return nil
+}
+
+// criPortMappings converts the port mappings of a sandbox back to the ones
+// of a sandbox config.
+func criPortMappings(in []*hostport.PortMapping) []*types.PortMapping {
+	out := make([]*types.PortMapping, 0, len(in))
+
+	for _, v := range in {
+		out = append(out, &types.PortMapping{
+			Protocol:      types.Protocol(types.Protocol_value[string(v.Protocol)]),
+			ContainerPort: v.ContainerPort,
+			HostPort:      v.HostPort,
+			HostIp:        v.HostIP,
+		})
+	}
+
+	return out
+}
+
+// restorePodSandbox restores the pod checkpoint imported into the newly
+// created sandbox. Only the content of /dev/shm is restored here, the network
+// configuration is applied when importing the checkpoint and the containers
+// are restored when they get created in the sandbox.
+func (s *Server) restorePodSandbox(ctx context.Context, sb *sandbox.Sandbox, config *lib.PodCheckpointConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	dir := s.podRestoreDirectory(sb.ID())
+
+	if shmPath := sb.ShmPath(); shmPath != "" && shmPath != sandbox.DevShmPath {
+		if err := lib.RestorePodShm(dir, shmPath); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Remove(filepath.Join(dir, metadata.DevShmCheckpointTar)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warnf(ctx, "Unable to remove shm checkpoint of pod sandbox %s: %v", sb.ID(), err)
+	}
+
+	log.Infof(ctx, "Restored pod sandbox %s from checkpoint of %s/%s with %d containers", sb.ID(), config.Namespace, config.Name, len(config.Containers))
+
+	return nil
+}
+
+// podCheckpointContainerArchive returns the checkpoint archive of the
+// container with the provided name if the sandbox has been restored from a
+// pod checkpoint archive containing it.
+func (s *Server) podCheckpointContainerArchive(sb *sandbox.Sandbox, name string) (string, bool) {
+	if !s.config.RestorePodEnabled() {
+		return "", false
+	}
+
+	if _, ok := v2.GetAnnotationValue(sb.Annotations(), v2.RestorePod); !ok {
+		return "", false
+	}
+
+	archivePath := lib.PodCheckpointContainerArchive(s.podRestoreDirectory(sb.ID()), name)
+	if _, err := os.Stat(archivePath); err != nil {
+		return "", false
+	}
+
+	return archivePath, true
+}
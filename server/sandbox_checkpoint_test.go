@@ -0,0 +1,109 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/cri-o/cri-o/internal/lib"
+)
+
+func TestApplyPodCheckpointNetwork(t *testing.T) {
+	dnsConfig := &types.DNSConfig{
+		Servers:  []string{"10.96.0.10"},
+		Searches: []string{"default.svc.cluster.local"},
+		Options:  []string{"ndots:5"},
+	}
+	portMappings := []*types.PortMapping{
+		{Protocol: types.Protocol_TCP, ContainerPort: 80, HostPort: 8080},
+		{Protocol: types.Protocol_UDP, ContainerPort: 53, HostPort: 5353, HostIp: "127.0.0.1"},
+	}
+
+	cases := []struct {
+		name                 string
+		sandboxConfig        *types.PodSandboxConfig
+		network              string
+		expectedDNSConfig    *types.DNSConfig
+		expectedPortMappings []*types.PortMapping
+		expectErr            bool
+	}{
+		{
+			name:                 "network configuration of the checkpoint",
+			sandboxConfig:        &types.PodSandboxConfig{DnsConfig: &types.DNSConfig{}},
+			network:              "default",
+			expectedDNSConfig:    dnsConfig,
+			expectedPortMappings: portMappings,
+		},
+		{
+			name: "network configuration of the request",
+			sandboxConfig: &types.PodSandboxConfig{
+				DnsConfig:    &types.DNSConfig{Servers: []string{"10.0.0.10"}},
+				PortMappings: []*types.PortMapping{{Protocol: types.Protocol_TCP, ContainerPort: 443, HostPort: 8443}},
+			},
+			network:              "default",
+			expectedDNSConfig:    &types.DNSConfig{Servers: []string{"10.0.0.10"}},
+			expectedPortMappings: []*types.PortMapping{{Protocol: types.Protocol_TCP, ContainerPort: 443, HostPort: 8443}},
+		},
+		{
+			name:                 "host network",
+			sandboxConfig:        &types.PodSandboxConfig{},
+			expectedDNSConfig:    dnsConfig,
+			expectedPortMappings: portMappings,
+		},
+		{
+			name:          "other network",
+			sandboxConfig: &types.PodSandboxConfig{},
+			network:       "other",
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The checkpoint config is stored as JSON in the archive.
+			content, err := json.Marshal(&lib.PodCheckpointConfig{
+				ID:           "sandbox",
+				DNSConfig:    dnsConfig,
+				PortMappings: convertPortMappings(portMappings),
+				Networks:     []string{"default"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			config := &lib.PodCheckpointConfig{}
+			if err := json.Unmarshal(content, config); err != nil {
+				t.Fatal(err)
+			}
+
+			err = applyPodCheckpointNetwork(config, tc.sandboxConfig, tc.network)
+			if tc.expectErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !proto.Equal(tc.sandboxConfig.GetDnsConfig(), tc.expectedDNSConfig) {
+				t.Errorf("expected DNS config %v, got %v", tc.expectedDNSConfig, tc.sandboxConfig.GetDnsConfig())
+			}
+
+			if len(tc.sandboxConfig.GetPortMappings()) != len(tc.expectedPortMappings) {
+				t.Fatalf("expected port mappings %v, got %v", tc.expectedPortMappings, tc.sandboxConfig.GetPortMappings())
+			}
+
+			for i, portMapping := range tc.sandboxConfig.GetPortMappings() {
+				if !proto.Equal(portMapping, tc.expectedPortMappings[i]) {
+					t.Errorf("expected port mapping %v, got %v", tc.expectedPortMappings[i], portMapping)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/opencontainers/runtime-tools/generate"
+
+	"github.com/cri-o/cri-o/internal/annotations"
+	"github.com/cri-o/cri-o/internal/dnscache"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+)
+
+// setupSandboxDNSCache points the resolv.conf of the sandbox to the DNS cache
+// and returns the replaced nameservers, which are persisted as annotation to
+// be available on restore. It returns nil if the DNS cache is not used for
+// the sandbox, which is also the case if the nameservers of the sandbox are
+// not usable as upstreams.
+func (s *Server) setupSandboxDNSCache(ctx context.Context, g *generate.Generator, resolvPath string, hostNetwork bool) ([]string, error) {
+	if s.dnsCache == nil || hostNetwork || resolvPath == "" {
+		return nil, nil
+	}
+
+	upstreams, err := dnscache.RewriteResolvConf(resolvPath, s.dnsCache.Address())
+	if errors.Is(err, dnscache.ErrInvalidUpstream) {
+		log.Warnf(ctx, "Not using the DNS cache for the pod sandbox: %v", err)
+
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("use DNS cache: %w", err)
+	}
+
+	if len(upstreams) == 0 {
+		return nil, nil
+	}
+
+	upstreamsJSON, err := json.Marshal(upstreams)
+	if err != nil {
+		return nil, err
+	}
+
+	g.AddAnnotation(annotations.DNSCacheUpstreams, string(upstreamsJSON))
+
+	return upstreams, nil
+}
+
+// registerSandboxDNSCache registers the IPs of the sandbox at the DNS cache,
+// if the sandbox uses it.
+func (s *Server) registerSandboxDNSCache(ctx context.Context, sb *sandbox.Sandbox) {
+	if s.dnsCache == nil || len(sb.DNSCacheUpstreams()) == 0 {
+		return
+	}
+
+	if err := s.dnsCache.Register(sb.ID(), sb.Namespace(), sb.KubeName(), sb.IPs(), sb.DNSCacheUpstreams()); err != nil {
+		log.Warnf(ctx, "Unable to register pod sandbox %s(%s) at the DNS cache, its DNS queries will not be answered: %v", sb.Name(), sb.ID(), err)
+	}
+}
+
+// unregisterSandboxDNSCache removes the sandbox from the DNS cache.
+func (s *Server) unregisterSandboxDNSCache(sb *sandbox.Sandbox) {
+	if s.dnsCache == nil {
+		return
+	}
+
+	s.dnsCache.Unregister(sb.ID())
+}
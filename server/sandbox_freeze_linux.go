@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opencontainers/cgroups"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+)
+
+// freezePodSandbox freezes all processes of the sandbox atomically using the
+// freezer of the pod cgroup. This includes the infra container, or the
+// containers of pods without infra container.
+func (s *Server) freezePodSandbox(ctx context.Context, sb *sandbox.Sandbox) error {
+	return s.setSandboxFreezerState(ctx, sb, cgroups.Frozen)
+}
+
+// thawPodSandbox thaws all processes of a frozen sandbox.
+func (s *Server) thawPodSandbox(ctx context.Context, sb *sandbox.Sandbox) error {
+	return s.setSandboxFreezerState(ctx, sb, cgroups.Thawed)
+}
+
+// sandboxFrozen returns whether the pod cgroup of the sandbox is frozen.
+func (s *Server) sandboxFrozen(sb *sandbox.Sandbox) (bool, error) {
+	cgMgr, err := s.sandboxCgroupManager(sb)
+	if err != nil {
+		return false, err
+	}
+
+	state, err := cgMgr.GetFreezerState()
+	if err != nil {
+		return false, fmt.Errorf("get freezer state of sandbox %s: %w", sb.ID(), err)
+	}
+
+	return state == cgroups.Frozen, nil
+}
+
+func (s *Server) setSandboxFreezerState(ctx context.Context, sb *sandbox.Sandbox, state cgroups.FreezerState) error {
+	cgMgr, err := s.sandboxCgroupManager(sb)
+	if err != nil {
+		return err
+	}
+
+	if err := cgMgr.Freeze(state); err != nil {
+		return fmt.Errorf("set freezer state of sandbox %s to %s: %w", sb.ID(), state, err)
+	}
+
+	log.Infof(ctx, "Set freezer state of sandbox %s to %s", sb.ID(), state)
+
+	return nil
+}
+
+func (s *Server) sandboxCgroupManager(sb *sandbox.Sandbox) (cgroups.Manager, error) {
+	if sb.CgroupParent() == "" {
+		return nil, fmt.Errorf("sandbox %s has no pod cgroup", sb.ID())
+	}
+
+	cgMgr, err := s.config.CgroupManager().SandboxCgroupManager(sb.CgroupParent(), sb.ID())
+	if err != nil {
+		return nil, fmt.Errorf("get cgroup manager of sandbox %s: %w", sb.ID(), err)
+	}
+
+	return cgMgr, nil
+}
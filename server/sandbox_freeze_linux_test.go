@@ -0,0 +1,191 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/cgroups"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/cri-o/cri-o/internal/config/cgmgr"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/oci"
+	crioTypes "github.com/cri-o/cri-o/pkg/types"
+)
+
+// fakeFreezer is a pod cgroup which only supports the freezer.
+type fakeFreezer struct {
+	cgroups.Manager
+
+	state cgroups.FreezerState
+}
+
+func (f *fakeFreezer) Freeze(state cgroups.FreezerState) error {
+	f.state = state
+
+	return nil
+}
+
+func (f *fakeFreezer) GetFreezerState() (cgroups.FreezerState, error) {
+	return f.state, nil
+}
+
+// fakeFreezerCgroupManager returns the fake freezer as pod cgroup of every
+// sandbox.
+type fakeFreezerCgroupManager struct {
+	cgmgr.CgroupManager
+
+	freezer *fakeFreezer
+}
+
+func (m *fakeFreezerCgroupManager) SandboxCgroupManager(string, string) (cgroups.Manager, error) {
+	return m.freezer, nil
+}
+
+// The actual test suite.
+var _ = t.Describe("SandboxFreezer", func() {
+	var (
+		recorder *httptest.ResponseRecorder
+		mux      *chi.Mux
+		freezer  *fakeFreezer
+		sb       *sandbox.Sandbox
+	)
+
+	// Prepare the sut
+	BeforeEach(func() {
+		beforeEach()
+		mockRuntimeInLibConfig()
+
+		freezer = &fakeFreezer{state: cgroups.Thawed}
+		serverConfig.SetCgroupManager(&fakeFreezerCgroupManager{
+			CgroupManager: serverConfig.CgroupManager(),
+			freezer:       freezer,
+		})
+
+		setupSUT()
+
+		recorder = httptest.NewRecorder()
+		mux = sut.GetExtendInterfaceMux(false)
+		Expect(mux).NotTo(BeNil())
+
+		sb = addSandboxWithPodCgroup()
+	})
+
+	AfterEach(afterEach)
+
+	serveFreezer := func(endpoint string) *crioTypes.SandboxFreezerInfo {
+		GinkgoHelper()
+
+		recorder = httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, endpoint+"/"+sb.ID(), http.NoBody)
+		Expect(err).ToNot(HaveOccurred())
+
+		mux.ServeHTTP(recorder, request)
+		Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		info := &crioTypes.SandboxFreezerInfo{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), info)).To(Succeed())
+
+		return info
+	}
+
+	verboseInfo := func() string {
+		GinkgoHelper()
+
+		response, err := sut.PodSandboxStatus(context.Background(),
+			&types.PodSandboxStatusRequest{PodSandboxId: sb.ID(), Verbose: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		return response.GetInfo()["info"]
+	}
+
+	t.Describe("Freeze and thaw", func() {
+		It("should freeze, report and thaw the sandbox", func() {
+			// Given
+			Expect(verboseInfo()).To(ContainSubstring(`"frozen":false`))
+
+			// When
+			frozenInfo := serveFreezer("/freeze")
+
+			// Then
+			Expect(frozenInfo.ID).To(Equal(sb.ID()))
+			Expect(frozenInfo.Frozen).To(BeTrue())
+			Expect(freezer.state).To(Equal(cgroups.Frozen))
+			Expect(verboseInfo()).To(ContainSubstring(`"frozen":true`))
+
+			// When
+			thawedInfo := serveFreezer("/thaw")
+
+			// Then
+			Expect(thawedInfo.ID).To(Equal(sb.ID()))
+			Expect(thawedInfo.Frozen).To(BeFalse())
+			Expect(freezer.state).To(Equal(cgroups.Thawed))
+			Expect(verboseInfo()).To(ContainSubstring(`"frozen":false`))
+		})
+
+		It("should fail to freeze an already frozen sandbox", func() {
+			// Given
+			freezer.state = cgroups.Frozen
+
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/freeze/"+sb.ID(), http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusConflict))
+			Expect(freezer.state).To(Equal(cgroups.Frozen))
+		})
+
+		It("should fail to thaw a sandbox which is not frozen", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/thaw/"+sb.ID(), http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusConflict))
+			Expect(freezer.state).To(Equal(cgroups.Thawed))
+		})
+	})
+})
+
+// addSandboxWithPodCgroup adds a running sandbox with a pod cgroup and an
+// infra container to the sut.
+func addSandboxWithPodCgroup() *sandbox.Sandbox {
+	GinkgoHelper()
+
+	ctx := context.TODO()
+
+	sb := newTestSandbox("freezerSandboxID", "/kubepods/freezer", nil)
+
+	infra, err := oci.NewContainer("freezerInfraID", "", "", "",
+		make(map[string]string), make(map[string]string),
+		make(map[string]string), "pauseImage", nil, nil, "",
+		&types.ContainerMetadata{}, sb.ID(), false, false,
+		false, "", "", time.Now(), "")
+	Expect(err).ToNot(HaveOccurred())
+	infra.SetStateAndSpoofPid(&oci.ContainerState{
+		State: specs.State{Status: oci.ContainerStateRunning},
+	})
+	infra.SetSpec(&specs.Spec{Version: "1.0.0"})
+
+	Expect(sut.AddSandbox(ctx, sb)).To(Succeed())
+	Expect(sb.SetInfraContainer(infra)).To(Succeed())
+	sut.AddContainer(ctx, infra)
+	Expect(sut.CtrIDIndex().Add(infra.ID())).To(Succeed())
+	Expect(sut.PodIDIndex().Add(sb.ID())).To(Succeed())
+	infra.SetCreated()
+	sb.SetCreated()
+
+	return sb
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+)
+
+var errFreezeNotSupported = errors.New("freezing pod sandboxes is not supported on this platform")
+
+func (s *Server) freezePodSandbox(context.Context, *sandbox.Sandbox) error {
+	return errFreezeNotSupported
+}
+
+func (s *Server) thawPodSandbox(context.Context, *sandbox.Sandbox) error {
+	return errFreezeNotSupported
+}
+
+func (s *Server) sandboxFrozen(*sandbox.Sandbox) (bool, error) {
+	return false, nil
+}
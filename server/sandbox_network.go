@@ -6,9 +6,11 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/containernetworking/cni/libcni"
 	cnitypes "github.com/containernetworking/cni/pkg/types"
 	cnicurrent "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/cri-o/ocicni/pkg/ocicni"
@@ -22,6 +24,24 @@ import (
 
 const (
 	cacheDir = "/var/lib/cni/results"
+
+	// CNI operation names used for metrics.
+	cniOperationAdd   = "ADD"
+	cniOperationDel   = "DEL"
+	cniOperationCheck = "CHECK"
+	cniOperationGC    = "GC"
+
+	// cniAllNetworks is the network and plugin metrics label for operations
+	// which cover all known networks, like GC.
+	cniAllNetworks = "all"
+
+	// cniUnknownPlugin is the plugin metrics label for networks whose
+	// configuration cannot be found anymore.
+	cniUnknownPlugin = "unknown"
+
+	// cniTeardownRetryBackoff is the initial delay between CNI DEL retries,
+	// which gets doubled on every attempt.
+	cniTeardownRetryBackoff = 500 * time.Millisecond
 )
 
 // networkStart sets up the sandbox's network and returns the pod IP on success
@@ -31,13 +51,14 @@ func (s *Server) networkStart(ctx context.Context, sb *sandbox.Sandbox) (podIPs
 	defer span.End()
 
 	overallStart := time.Now()
-	// Give a network Start call a full 5 minutes, independent of the context of the request.
+	// Give a network Start call the full cni_setup_timeout (5 minutes per
+	// default), independent of the context of the request.
 	// This is to prevent the CNI plugin from taking an unbounded amount of time,
 	// but to still allow a long-running sandbox creation to be cached and reused,
 	// rather than failing and recreating it.
 	// Adding on top of the specified deadline ensures this deadline will be respected, regardless of
 	// how Kubelet's runtime-request-timeout changes.
-	startTimeout := 5 * time.Minute
+	startTimeout := s.config.CNISetupTimeout
 	if initialDeadline, ok := ctx.Deadline(); ok {
 		startTimeout += time.Until(initialDeadline)
 	}
@@ -68,14 +89,19 @@ func (s *Server) networkStart(ctx context.Context, sb *sandbox.Sandbox) (podIPs
 
 	podSetUpStart := time.Now()
 
-	_, err = s.config.CNIPlugin().SetUpPodWithContext(startCtx, podNetwork)
+	setUpResults, err := s.config.CNIPlugin().SetUpPodWithContext(startCtx, podNetwork)
+	s.observeCNIOperation(cniOperationAdd, s.cniNetworkNames(podNetwork, setUpResults), podSetUpStart, err)
+
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create pod network sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
 	}
 	// metric about the CNI network setup operation
 	metrics.Instance().MetricOperationsLatencySet("network_setup_pod", podSetUpStart)
 
+	podCheckStart := time.Now()
 	podNetworkStatus, err := s.config.CNIPlugin().GetPodNetworkStatusWithContext(startCtx, podNetwork)
+	s.observeCNIOperation(cniOperationCheck, s.cniNetworkNames(podNetwork, podNetworkStatus), podCheckStart, err)
+
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get network status for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
 	}
@@ -175,9 +201,8 @@ func (s *Server) networkStop(ctx context.Context, sb *sandbox.Sandbox) error {
 	if sb.HostNetwork() || sb.NetworkStopped() {
 		return nil
 	}
-	// give a network stop call 1 minutes, half of a StopPod request timeout limit
-	stopCtx, stopCancel := context.WithTimeout(ctx, 1*time.Minute)
-	defer stopCancel()
+
+	s.unregisterSandboxDNSCache(sb)
 
 	// portMapping removal does not need the IP address
 	if err := s.hostportManager.Remove(sb.ID(), sb.PortMappings()); err != nil {
@@ -222,7 +247,7 @@ func (s *Server) networkStop(ctx context.Context, sb *sandbox.Sandbox) error {
 	}
 
 	// Always attempt CNI teardown to prevent IP leaks, even if netns is invalid.
-	if err := s.config.CNIPlugin().TearDownPodWithContext(stopCtx, podNetwork); err != nil {
+	if err := s.tearDownPodNetwork(ctx, sb, podNetwork, netnsValid); err != nil {
 		if !netnsValid {
 			// This is expected when the network namespace is missing/invalid.
 			log.Debugf(ctx, "CNI teardown failed due to missing/invalid network namespace for pod sandbox %s(%s): %v", sb.Name(), sb.ID(), err)
@@ -257,6 +282,112 @@ func (s *Server) networkStop(ctx context.Context, sb *sandbox.Sandbox) error {
 	return sb.SetNetworkStopped(ctx, true)
 }
 
+// tearDownPodNetwork calls the CNI plugin to tear down the pod network. Every
+// attempt is bounded by cni_teardown_timeout and failed attempts are retried
+// up to cni_teardown_retries times with an exponential backoff. Retries are
+// skipped if the network namespace is missing or invalid, because the plugin
+// is expected to fail on every attempt in that case. The attempts and retries
+// are bounded by the deadline of the request context.
+func (s *Server) tearDownPodNetwork(ctx context.Context, sb *sandbox.Sandbox, podNetwork ocicni.PodNetwork, retry bool) error {
+	backoff := cniTeardownRetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := s.tearDownPodNetworkOnce(ctx, podNetwork)
+		if err == nil || !retry || attempt >= s.config.CNITeardownRetries {
+			return err
+		}
+
+		log.Warnf(ctx, "Failed to destroy network for pod sandbox %s(%s) (attempt %d of %d), retrying in %v: %v",
+			sb.Name(), sb.ID(), attempt+1, s.config.CNITeardownRetries+1, backoff, err)
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= backoff {
+			log.Warnf(ctx, "Not retrying to destroy network for pod sandbox %s(%s): request deadline exceeded", sb.Name(), sb.ID())
+
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// tearDownPodNetworkOnce does a single CNI teardown attempt.
+func (s *Server) tearDownPodNetworkOnce(ctx context.Context, podNetwork ocicni.PodNetwork) error {
+	stopCtx, stopCancel := context.WithTimeout(ctx, s.config.CNITeardownTimeout)
+	defer stopCancel()
+
+	start := time.Now()
+	err := s.config.CNIPlugin().TearDownPodWithContext(stopCtx, podNetwork)
+	s.observeCNIOperation(cniOperationDel, s.cniNetworkNames(podNetwork, nil), start, err)
+
+	return err
+}
+
+// observeCNIOperation records the latency and a possible failure of a CNI
+// plugin operation for each of the provided networks.
+func (s *Server) observeCNIOperation(operation string, networks []string, start time.Time, err error) {
+	for _, network := range networks {
+		observeCNINetworkOperation(operation, network, cniPluginType(s.config.NetworkDir, network), start, err)
+	}
+}
+
+// observeCNIGC records the latency and a possible failure of a CNI plugin GC,
+// which covers all networks.
+func observeCNIGC(start time.Time, err error) {
+	observeCNINetworkOperation(cniOperationGC, cniAllNetworks, cniAllNetworks, start, err)
+}
+
+func observeCNINetworkOperation(operation, network, plugin string, start time.Time, err error) {
+	metrics.Instance().MetricCNIOperationsLatencyObserve(operation, network, plugin, start)
+
+	if err != nil {
+		metrics.Instance().MetricCNIOperationsErrorsInc(operation, network, plugin)
+	}
+}
+
+// cniPluginType returns the type of the main plugin of the network, which is
+// the first plugin of its configuration list in the networkDir.
+func cniPluginType(networkDir, network string) string {
+	confList, err := libcni.LoadConfList(networkDir, network)
+	if err != nil || len(confList.Plugins) == 0 || confList.Plugins[0].Network == nil || confList.Plugins[0].Network.Type == "" {
+		return cniUnknownPlugin
+	}
+
+	return confList.Plugins[0].Network.Type
+}
+
+// cniNetworkNames returns the names of the networks a CNI operation on the pod
+// network operated on: the networks of the results if any, otherwise the
+// networks attached to the pod, which default to the default network.
+func (s *Server) cniNetworkNames(podNetwork ocicni.PodNetwork, results []ocicni.NetResult) []string {
+	attachments := podNetwork.Networks
+	if len(results) > 0 {
+		attachments = make([]ocicni.NetAttachment, 0, len(results))
+		for i := range results {
+			attachments = append(attachments, results[i].NetAttachment)
+		}
+	}
+
+	names := make([]string, 0, len(attachments))
+
+	for _, attachment := range attachments {
+		if attachment.Name != "" && !slices.Contains(names, attachment.Name) {
+			names = append(names, attachment.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		names = append(names, s.config.CNIPlugin().GetDefaultNetworkName())
+	}
+
+	return names
+}
+
 // cleanupCNIResultFiles removes CNI result files for a given container ID.
 // This is called when CNI teardown fails to prevent stale result files from accumulating.
 func (s *Server) cleanupCNIResultFiles(ctx context.Context, containerID string) {
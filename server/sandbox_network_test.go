@@ -0,0 +1,80 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/cri-o/ocicni/pkg/ocicni"
+)
+
+func TestCNINetworkNames(t *testing.T) {
+	cases := []struct {
+		name       string
+		podNetwork ocicni.PodNetwork
+		results    []ocicni.NetResult
+		expected   []string
+	}{
+		{
+			name: "networks of the results",
+			podNetwork: ocicni.PodNetwork{
+				Networks: []ocicni.NetAttachment{{Name: "requested"}},
+			},
+			results: []ocicni.NetResult{
+				{NetAttachment: ocicni.NetAttachment{Name: "default", Ifname: "eth0"}},
+				{NetAttachment: ocicni.NetAttachment{Name: "additional", Ifname: "net1"}},
+				{NetAttachment: ocicni.NetAttachment{Name: "additional", Ifname: "net2"}},
+			},
+			expected: []string{"default", "additional"},
+		},
+		{
+			name: "networks attached to the pod",
+			podNetwork: ocicni.PodNetwork{
+				Networks: []ocicni.NetAttachment{{Name: "default"}, {Name: "additional"}},
+			},
+			expected: []string{"default", "additional"},
+		},
+	}
+
+	s := &Server{}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := s.cniNetworkNames(tc.podNetwork, tc.results)
+			if !slices.Equal(res, tc.expected) {
+				t.Errorf("expected networks %v, got %v", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestCNIPluginType(t *testing.T) {
+	dir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"10-chained.conflist": `{"cniVersion": "1.0.0", "name": "chained", "plugins": [{"type": "bridge"}, {"type": "portmap"}]}`,
+		"20-single.conf":      `{"cniVersion": "1.0.0", "name": "single", "type": "macvlan"}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		network  string
+		expected string
+	}{
+		{network: "chained", expected: "bridge"},
+		{network: "single", expected: "macvlan"},
+		{network: "missing", expected: cniUnknownPlugin},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.network, func(t *testing.T) {
+			if res := cniPluginType(dir, tc.network); res != tc.expected {
+				t.Errorf("expected plugin type %q, got %q", tc.expected, res)
+			}
+		})
+	}
+}
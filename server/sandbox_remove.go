@@ -83,6 +83,8 @@ func (s *Server) removePodSandbox(ctx context.Context, sb *sandbox.Sandbox) erro
 	}
 
 	s.ReleasePodName(sb.Name())
+	s.releaseSandboxUserns(ctx, sb.ID())
+	s.removePodRestoreDirectory(ctx, sb)
 
 	if err := s.removeSandbox(ctx, sb.ID()); err != nil {
 		log.Warnf(ctx, "Failed to remove sandbox: %v", err)
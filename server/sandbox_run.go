@@ -2,13 +2,18 @@ package server
 
 import (
 	"context"
+	"errors"
 	"os"
 
+	"google.golang.org/protobuf/proto"
 	v1 "k8s.io/api/core/v1"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/server/metrics"
 )
 
 const (
@@ -64,10 +69,81 @@ func (s *Server) runtimeHandler(req *types.RunPodSandboxRequest) (string, error)
 	return handler, nil
 }
 
+// sandboxRuntimeError is returned if the runtime failed to create or start
+// the infra container of a pod sandbox.
+type sandboxRuntimeError struct {
+	err error
+}
+
+func (e *sandboxRuntimeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *sandboxRuntimeError) Unwrap() error {
+	return e.err
+}
+
 // RunPodSandbox creates and runs a pod-level sandbox.
 func (s *Server) RunPodSandbox(ctx context.Context, req *types.RunPodSandboxRequest) (*types.RunPodSandboxResponse, error) {
 	// platform dependent call
-	return s.runPodSandbox(ctx, req)
+	resp, err := s.runPodSandbox(ctx, req)
+	if err == nil || ctx.Err() != nil {
+		return resp, err
+	}
+
+	fallback := s.runtimeFallbackHandler(req.GetRuntimeHandler(), err)
+	if fallback == "" {
+		return resp, err
+	}
+
+	log.Warnf(ctx, "Retrying to run pod sandbox with fallback runtime handler %q after runtime error: %v", fallback, err)
+	metrics.Instance().MetricRuntimeHandlerFallbacksInc(s.runtimeHandlerName(req.GetRuntimeHandler()), fallback)
+
+	fallbackReq := proto.CloneOf(req)
+	fallbackReq.RuntimeHandler = fallback
+
+	return s.runPodSandbox(ctx, fallbackReq)
+}
+
+// runtimeFallbackHandler returns the fallback runtime handler of the
+// runtimeHandler if err is a runtime error triggering it.
+func (s *Server) runtimeFallbackHandler(runtimeHandler string, err error) string {
+	var runtimeErr *sandboxRuntimeError
+	if !errors.As(err, &runtimeErr) {
+		return ""
+	}
+
+	return s.ContainerServer.Runtime().RuntimeFallbackHandler(runtimeHandler, runtimeErr.err)
+}
+
+// runtimeHandlerName returns the name of the runtime handler, which is the
+// default runtime if empty.
+func (s *Server) runtimeHandlerName(runtimeHandler string) string {
+	if runtimeHandler == "" {
+		return s.config.DefaultRuntime
+	}
+
+	return runtimeHandler
+}
+
+// runtimeHandlerContainersInc counts the result of the runtime operation on
+// the container by the runtime handler of its sandbox. Pods without an infra
+// container never retry with the fallback runtime handler, which makes this
+// the only signal about runtime errors of their containers.
+func (s *Server) runtimeHandlerContainersInc(sb *sandbox.Sandbox, c *oci.Container, operation string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	canary := false
+
+	if runtimePath := c.RuntimePath(); runtimePath != "" {
+		runtimeCanaryPath, canaryErr := s.ContainerServer.Runtime().RuntimeCanaryPath(sb.RuntimeHandler())
+		canary = canaryErr == nil && runtimePath == runtimeCanaryPath
+	}
+
+	metrics.Instance().MetricRuntimeHandlerContainersInc(s.runtimeHandlerName(sb.RuntimeHandler()), canary, operation, result)
 }
 
 func convertPortMappings(in []*types.PortMapping) []*hostport.PortMapping {
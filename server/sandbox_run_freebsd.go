@@ -327,6 +327,11 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 	g.AddAnnotation(v2.PodLinuxResources, string(resourcesJSON))
 
+	dnsCacheUpstreams, err := s.setupSandboxDNSCache(ctx, g, sbox.ResolvPath(), hostNetwork)
+	if err != nil {
+		return nil, err
+	}
+
 	sbox.SetResolvPath(sbox.ResolvPath())
 	sbox.SetHostname(hostname)
 	sbox.SetPortMappings(portMappings)
@@ -339,6 +344,8 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		return nil, err
 	}
 
+	sb.SetDNSCacheUpstreams(dnsCacheUpstreams)
+
 	if err := s.addSandbox(ctx, sb); err != nil {
 		return nil, err
 	}
@@ -486,7 +493,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 
 	s.resourceStore.SetStageForResource(ctx, sboxName, "sandbox container runtime creation")
 	if err := s.createContainerPlatform(ctx, container, sb.CgroupParent(), sandboxIDMappings); err != nil {
-		return nil, err
+		return nil, &sandboxRuntimeError{err: err}
 	}
 	resourceCleaner.Add(ctx, "runSandbox: stopping container "+container.ID(), func() error {
 		// Clean-up steps from RemovePodSandbox
@@ -551,6 +558,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		g.AddAnnotation(fmt.Sprintf("%s.%d", annotations.IP, idx), ip)
 	}
 	sb.AddIPs(ips)
+	s.registerSandboxDNSCache(ctx, sb)
 
 	if err := s.nri.runPodSandbox(ctx, sb); err != nil {
 		return nil, err
@@ -7,6 +7,7 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -40,6 +41,7 @@ import (
 	"github.com/cri-o/cri-o/internal/storage/references"
 	v2 "github.com/cri-o/cri-o/pkg/annotations/v2"
 	libconfig "github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/server/metrics"
 	"github.com/cri-o/cri-o/utils"
 )
 
@@ -348,6 +350,38 @@ func (s *Server) configurePrivateUserNS(values map[string]string, sc *types.Linu
 	return &storage.IDMappingOptions{UIDMap: uids, GIDMap: gids}, nil
 }
 
+// allocateSandboxUserns replaces the automatic user namespace of a sandbox
+// with a range allocated from a pool. Container IDs are mapped one to one to
+// the allocated range, so the user and groups of the sandbox have to be
+// within its size.
+func (s *Server) allocateSandboxUserns(sandboxID, namespace, name, pool string, autoOpts *storage.IDMappingOptions, sc *types.LinuxSandboxSecurityContext) (*storage.IDMappingOptions, error) {
+	size := autoOpts.AutoUserNsOpts.Size
+
+	ids := slices.Clone(sc.GetSupplementalGroups())
+	if sc.GetRunAsUser() != nil {
+		ids = append(ids, sc.GetRunAsUser().GetValue())
+	}
+
+	if sc.GetRunAsGroup() != nil {
+		ids = append(ids, sc.GetRunAsGroup().GetValue())
+	}
+
+	for _, id := range ids {
+		if id < 0 || id >= int64(size) {
+			return nil, fmt.Errorf("ID %d is outside of the user namespace of size %d allocated from userns pool %q", id, size, pool)
+		}
+	}
+
+	r, err := s.usernsAllocator.Allocate(sandboxID, namespace, name, pool, size)
+	if err != nil {
+		return nil, fmt.Errorf("allocate user namespace from pool %q: %w", pool, err)
+	}
+
+	mappings := []idtools.IDMap{{ContainerID: 0, HostID: int(r.HostID), Size: int(r.Size)}}
+
+	return &storage.IDMappingOptions{UIDMap: mappings, GIDMap: slices.Clone(mappings)}, nil
+}
+
 func convertToStorageIDMap(mappings []*types.IDMapping) []idtools.IDMap {
 	ret := make([]idtools.IDMap, len(mappings))
 	for i, m := range mappings {
@@ -472,11 +506,36 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 
 	sbox.SetRuntimeHandler(runtimeHandler)
 
+	runtimeCanaryPath, err := s.ContainerServer.Runtime().RuntimeCanaryPath(runtimeHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		result := "success"
+		if retErr != nil {
+			result = "failure"
+		}
+
+		metrics.Instance().MetricRuntimeHandlerSandboxesInc(s.runtimeHandlerName(runtimeHandler), runtimeCanaryPath != "", result)
+	}()
+
 	kubeAnnotations, err := s.prepareKubeAnnotations(ctx, sbox, runtimeHandler)
 	if err != nil {
 		return nil, err
 	}
 
+	podCheckpoint, err := s.importPodCheckpoint(ctx, sboxID, kubeAnnotations, sbox.Config())
+	if err != nil {
+		return nil, fmt.Errorf("import pod checkpoint: %w", err)
+	}
+
+	if podCheckpoint != nil {
+		resourceCleaner.Add(ctx, "runSandbox: removing pod restore directory of sandbox "+sboxID, func() error {
+			return os.RemoveAll(s.podRestoreDirectory(sboxID))
+		})
+	}
+
 	usernsMode, _ := v2.GetAnnotationValue(kubeAnnotations, v2.UsernsMode)
 	if usernsMode != "" {
 		log.Warnf(ctx, "Annotation 'io.kubernetes.cri-o.userns-mode' is deprecated, and will be replaced with native Kubernetes support for user namespaces in the future")
@@ -489,6 +548,18 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		return nil, err
 	}
 
+	usernsPool := s.usernsPoolFor(usernsMode, runtimeHandler, namespace)
+	if usernsPool != "" {
+		idMappingsOptions, err = s.allocateSandboxUserns(sboxID, namespace, kubeName, usernsPool, idMappingsOptions, sbox.Config().GetLinux().GetSecurityContext())
+		if err != nil {
+			return nil, err
+		}
+
+		resourceCleaner.Add(ctx, "runSandbox: releasing user namespace of sandbox "+sboxID, func() error {
+			return s.usernsAllocator.Release(sboxID)
+		})
+	}
+
 	containerName, err := s.ReserveSandboxContainerIDAndName(sbox.Config())
 	if err != nil {
 		return nil, err
@@ -543,6 +614,10 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		return s.ContainerServer.StorageRuntimeServer().DeleteContainer(ctx, sboxID)
 	})
 
+	if usernsPool == "" && idMappingsOptions != nil {
+		s.recordSandboxUserns(ctx, sboxID, namespace, kubeName)
+	}
+
 	mountLabel := podContainer.MountLabel
 	processLabel := podContainer.ProcessLabel
 	sbox.SetProcessLabel(processLabel)
@@ -644,8 +719,20 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		return nil, err
 	}
 
+	dnsCacheUpstreams, err := s.setupSandboxDNSCache(ctx, g, sbox.ResolvPath(), hostNetwork)
+	if err != nil {
+		return nil, err
+	}
+
 	hostnamePath := podContainer.RunDir + "/hostname"
 
+	dnsConfigJSON, err := json.Marshal(sbox.Config().GetDnsConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	g.AddAnnotation(annotations.DNSConfig, string(dnsConfigJSON))
+
 	sbox.SetResolvPath(sbox.ResolvPath())
 	sbox.SetDNSConfig(sbox.Config().GetDnsConfig())
 	sbox.SetHostnamePath(hostnamePath)
@@ -657,6 +744,8 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		return nil, err
 	}
 
+	sb.SetDNSCacheUpstreams(dnsCacheUpstreams)
+
 	if err := s.addSandbox(ctx, sb); err != nil {
 		return nil, err
 	}
@@ -726,6 +815,14 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		return nil, err
 	}
 
+	if runtimeCanaryPath != "" {
+		// All containers of the pod sandbox inherit the runtime path of
+		// the infra container.
+		log.Infof(ctx, "Using canary runtime %s for pod sandbox %s", runtimeCanaryPath, sboxID)
+		g.AddAnnotation(v2.PlatformRuntimePath, runtimeCanaryPath)
+		container.SetRuntimePathForPlatform(runtimeCanaryPath)
+	}
+
 	if err := s.configureAndSaveInfraContainer(ctx, sb, container, g, mountPoint, sandboxIDMappings, &podContainer, sboxID); err != nil {
 		return nil, err
 	}
@@ -739,6 +836,11 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 
 	sb.AddIPs(ips)
+	s.registerSandboxDNSCache(ctx, sb)
+
+	if err := s.restorePodSandbox(ctx, sb, podCheckpoint); err != nil {
+		return nil, fmt.Errorf("restore pod sandbox: %w", err)
+	}
 
 	if err := s.nri.runPodSandbox(ctx, sb); err != nil {
 		return nil, err
@@ -1344,7 +1446,7 @@ func (s *Server) createAndStartInfraContainer(ctx context.Context, sb *libsandbo
 	s.resourceStore.SetStageForResource(ctx, sboxName, "sandbox container runtime creation")
 
 	if err := s.createContainerPlatform(ctx, container, sb.CgroupParent(), sandboxIDMappings); err != nil {
-		return err
+		return &sandboxRuntimeError{err: err}
 	}
 
 	if hooks := s.hooksRetriever.Get(ctx, sb.RuntimeHandler(), sb.Annotations()); hooks != nil {
@@ -1356,7 +1458,7 @@ func (s *Server) createAndStartInfraContainer(ctx context.Context, sb *libsandbo
 	s.generateCRIEvent(ctx, sb.InfraContainer(), types.ContainerEventType_CONTAINER_CREATED_EVENT)
 
 	if err := s.ContainerServer.Runtime().StartContainer(ctx, container); err != nil {
-		return err
+		return &sandboxRuntimeError{err: err}
 	}
 
 	resourceCleaner.Add(ctx, "runSandbox: stopping container "+container.ID(), func() error {
@@ -73,7 +73,12 @@ func (s *Server) PodSandboxStatus(ctx context.Context, req *types.PodSandboxStat
 	}
 
 	if req.GetVerbose() {
-		info, err := createSandboxInfo(sb.InfraContainer())
+		frozen, err := s.sandboxFrozen(sb)
+		if err != nil {
+			log.Debugf(ctx, "Unable to get freezer state of sandbox %s: %v", sandboxID, err)
+		}
+
+		info, err := createSandboxInfo(sb.InfraContainer(), frozen)
 		if err != nil {
 			return nil, fmt.Errorf("creating sandbox info: %w", err)
 		}
@@ -92,23 +97,27 @@ func toPodIPs(ips []string) (result []*types.PodIP) {
 	return result
 }
 
-func createSandboxInfo(c *oci.Container) (map[string]string, error) {
+func createSandboxInfo(c *oci.Container, frozen bool) (map[string]string, error) {
 	var info any
 	if c.Spoofed() {
 		info = struct {
 			RuntimeSpec spec.Spec `json:"runtimeSpec"`
+			Frozen      bool      `json:"frozen"`
 		}{
 			c.Spec(),
+			frozen,
 		}
 	} else {
 		info = struct {
 			Image       string    `json:"image"`
 			Pid         int       `json:"pid"`
 			RuntimeSpec spec.Spec `json:"runtimeSpec"`
+			Frozen      bool      `json:"frozen"`
 		}{
 			c.UserRequestedImage(),
 			c.State().Pid,
 			c.Spec(),
+			frozen,
 		}
 	}
 
@@ -92,6 +92,7 @@ var _ = t.Describe("PodSandboxStatus", func() {
 			Expect(response.GetInfo()).NotTo(BeNil())
 			Expect(response.GetInfo()["info"]).To(ContainSubstring(`"ociVersion":"1.0.0"`))
 			Expect(response.GetInfo()["info"]).To(ContainSubstring(`"image":"pauseImage"`))
+			Expect(response.GetInfo()["info"]).To(ContainSubstring(`"frozen":false`))
 		})
 	})
 })
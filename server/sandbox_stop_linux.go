@@ -45,6 +45,13 @@ func (s *Server) stopPodSandbox(ctx context.Context, sb *sandbox.Sandbox) error
 		return nil
 	}
 
+	// Processes of a frozen sandbox would not react to the stop signal.
+	if frozen, err := s.sandboxFrozen(sb); err == nil && frozen {
+		if err := s.thawPodSandbox(ctx, sb); err != nil {
+			return fmt.Errorf("thaw sandbox before stopping: %w", err)
+		}
+	}
+
 	// Calculate the timeout once. Regular containers get most of the timeout,
 	// reserving a small amount for infra container shutdown. The infra container
 	// will use the full totalTimeout, allowing it to use any time saved from
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"go.podman.io/storage"
+	"go.podman.io/storage/pkg/idtools"
+
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/userns"
+	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/pkg/types"
+)
+
+// usernsPools converts the configured user namespace ID pools.
+func usernsPools(pools config.UsernsPools) []userns.Pool {
+	names := make([]string, 0, len(pools))
+	for name := range pools {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	res := make([]userns.Pool, 0, len(names))
+	for _, name := range names {
+		pool := pools[name]
+		res = append(res, userns.Pool{
+			Name:            name,
+			HostID:          pool.HostID,
+			Size:            pool.Size,
+			RuntimeHandlers: pool.RuntimeHandlers,
+			Namespaces:      pool.Namespaces,
+		})
+	}
+
+	return res
+}
+
+// usernsPoolFor returns the pool to allocate the user namespace of a sandbox
+// from, or an empty string if the sandbox is not allocated from a pool. Only
+// the "auto" mode without options other than the size is allocated from a
+// pool, because additional mappings are chosen by the user.
+func (s *Server) usernsPoolFor(mode, runtimeHandler, namespace string) string {
+	if s.usernsAllocator == nil {
+		return ""
+	}
+
+	parts := strings.SplitN(mode, ":", 2)
+	if parts[0] != "auto" {
+		return ""
+	}
+
+	if len(parts) > 1 {
+		for option := range strings.SplitSeq(parts[1], ";") {
+			if key, _, _ := strings.Cut(option, "="); key != "size" {
+				return ""
+			}
+		}
+	}
+
+	return s.usernsAllocator.PoolFor(runtimeHandler, namespace)
+}
+
+// storageAutoUsernsIDs returns the subordinate IDs containers/storage picks
+// the user namespaces of pods in "auto" mode from.
+func storageAutoUsernsIDs() []idtools.IDMap {
+	opts, err := storage.DefaultStoreOptions()
+	if err != nil || opts.RootAutoNsUser == "" {
+		return nil
+	}
+
+	mappings, err := idtools.NewIDMappings(opts.RootAutoNsUser, opts.RootAutoNsUser)
+	if err != nil {
+		return nil
+	}
+
+	return append(mappings.UIDs(), mappings.GIDs()...)
+}
+
+// recordSandboxUserns records the user namespace mappings of a sandbox which
+// are not allocated from a pool to detect overlaps with pool allocations. The
+// mappings are read from the storage, as those of the "auto" mode are only
+// chosen when the sandbox gets created there.
+func (s *Server) recordSandboxUserns(ctx context.Context, sandboxID, namespace, name string) {
+	if s.usernsAllocator == nil {
+		return
+	}
+
+	ctr, err := s.Store().Container(sandboxID)
+	if err != nil {
+		log.Warnf(ctx, "Unable to get user namespace of sandbox %s: %v", sandboxID, err)
+
+		return
+	}
+
+	if err := s.usernsAllocator.Record(&userns.Sandbox{
+		ID:        sandboxID,
+		Namespace: namespace,
+		Name:      name,
+		Mappings:  idtools.NewIDMappingsFromMaps(ctr.UIDMap, ctr.GIDMap),
+	}); err != nil {
+		log.Warnf(ctx, "Unable to record user namespace of sandbox %s: %v", sandboxID, err)
+	}
+}
+
+// releaseSandboxUserns releases the user namespace allocation of a sandbox.
+func (s *Server) releaseSandboxUserns(ctx context.Context, sandboxID string) {
+	if s.usernsAllocator == nil {
+		return
+	}
+
+	if err := s.usernsAllocator.Release(sandboxID); err != nil {
+		log.Warnf(ctx, "Unable to release user namespace of sandbox %s: %v", sandboxID, err)
+	}
+}
+
+// restoreUsernsAllocations reconciles the allocation table with the restored
+// sandboxes and warns about overlapping user namespaces.
+func (s *Server) restoreUsernsAllocations(ctx context.Context) {
+	if s.usernsAllocator == nil {
+		return
+	}
+
+	sandboxes := []*userns.Sandbox{}
+
+	for _, sb := range s.ListSandboxes() {
+		sandbox := &userns.Sandbox{
+			ID:        sb.ID(),
+			Namespace: sb.Namespace(),
+			Name:      sb.KubeName(),
+		}
+
+		if ic := sb.InfraContainer(); ic != nil {
+			sandbox.Mappings = ic.IDMappings()
+		}
+
+		sandboxes = append(sandboxes, sandbox)
+	}
+
+	overlaps, err := s.usernsAllocator.Restore(sandboxes)
+	if err != nil {
+		log.Warnf(ctx, "Unable to restore user namespace allocations: %v", err)
+	}
+
+	for _, overlap := range overlaps {
+		log.Warnf(ctx, "User namespaces of sandboxes %s overlap (UIDs: %v, GIDs: %v)",
+			strings.Join(overlap.SandboxIDs, ", "), overlap.Uids, overlap.Gids)
+	}
+}
+
+// getUsernsInfo returns the user namespace pools and allocations.
+func (s *Server) getUsernsInfo() types.UsernsInfo {
+	if s.usernsAllocator == nil {
+		return types.UsernsInfo{
+			Pools:       []types.UsernsPoolInfo{},
+			Allocations: []types.UsernsAllocation{},
+			Overlaps:    []types.UsernsOverlap{},
+		}
+	}
+
+	return s.usernsAllocator.Info()
+}
@@ -27,6 +27,8 @@ import (
 
 	"github.com/cri-o/cri-o/internal/cert"
 	"github.com/cri-o/cri-o/internal/config/seccomp"
+	"github.com/cri-o/cri-o/internal/cpuallocator"
+	"github.com/cri-o/cri-o/internal/dnscache"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/lib"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
@@ -38,6 +40,7 @@ import (
 	"github.com/cri-o/cri-o/internal/runtimehandlerhooks"
 	"github.com/cri-o/cri-o/internal/signals"
 	"github.com/cri-o/cri-o/internal/storage"
+	"github.com/cri-o/cri-o/internal/userns"
 	"github.com/cri-o/cri-o/internal/version"
 	"github.com/cri-o/cri-o/internal/watchdog"
 	libconfig "github.com/cri-o/cri-o/pkg/config"
@@ -101,6 +104,18 @@ type Server struct {
 	hooksRetriever *runtimehandlerhooks.HooksRetriever
 
 	artifactStore *ociartifact.Store
+
+	// dnsCache is the node-local caching DNS resolver for pods, nil if
+	// disabled.
+	dnsCache *dnscache.Resolver
+
+	// usernsAllocator allocates the user namespaces of pods from the
+	// configured ID pools, nil if no pools are configured.
+	usernsAllocator *userns.Allocator
+
+	// cpuAllocator allocates exclusive CPUs for containers from the
+	// configured pool, nil if no pool is configured.
+	cpuAllocator *cpuallocator.Allocator
 }
 
 // pullArguments are used to identify a pullOperation via an input image name and
@@ -318,6 +333,7 @@ func (s *Server) restore(ctx context.Context) []storage.StorageImageID {
 		}
 
 		sb.AddIPs(ips)
+		s.registerSandboxDNSCache(ctx, sb)
 	}
 
 	// Return a slice of images to remove, if internal_wipe is set.
@@ -334,6 +350,10 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	s.config.CNIManagerShutdown()
 	s.resourceStore.Close()
 
+	if s.dnsCache != nil {
+		s.dnsCache.Shutdown()
+	}
+
 	if err := s.ContainerServer.Shutdown(); err != nil {
 		return err
 	}
@@ -499,8 +519,49 @@ func New(
 		return nil, fmt.Errorf("close stdin: %w", err)
 	}
 
+	if config.DNSCacheAddress != "" {
+		dnsCache, err := dnscache.New(config.DNSCacheAddress, config.DNSCacheUpstreams, config.DNSCacheSize, config.DNSCacheMaxTTL)
+		if err != nil {
+			return nil, fmt.Errorf("create DNS cache: %w", err)
+		}
+
+		if err := dnsCache.Start(ctx); err != nil {
+			return nil, fmt.Errorf("start DNS cache: %w", err)
+		}
+
+		s.dnsCache = dnsCache
+	}
+
+	if len(config.UsernsPools) > 0 {
+		usernsAllocator, err := userns.New(config.UsernsAllocationsFile, usernsPools(config.UsernsPools))
+		if err != nil {
+			return nil, fmt.Errorf("create userns allocator: %w", err)
+		}
+
+		if err := usernsAllocator.CheckReserved("auto user namespaces of containers/storage", storageAutoUsernsIDs()); err != nil {
+			return nil, err
+		}
+
+		s.usernsAllocator = usernsAllocator
+	}
+
+	if config.CPUAllocatorPool != "" {
+		cpuAllocator, err := newCPUAllocator(config.CPUAllocatorPool, config.CPUAllocationsFile)
+		if err != nil {
+			return nil, fmt.Errorf("create CPU allocator: %w", err)
+		}
+
+		s.cpuAllocator = cpuAllocator
+	}
+
+	// Record every plugin GC in the CNI metrics, including the ones of the
+	// network GC during the restore.
+	s.config.CNIPluginSetGCObserver(observeCNIGC)
+
 	deletedImages := s.restore(ctx)
 	s.wipeIfAppropriate(ctx, deletedImages)
+	s.restoreUsernsAllocations(ctx)
+	s.restoreCPUAllocations(ctx)
 
 	var bindAddressStr string
 
@@ -564,6 +625,8 @@ func New(
 		return nil, errors.New("unable to create streaming server")
 	}
 
+	s.Runtime().SetStreamServer(s.stream.streamServer)
+
 	go func() {
 		defer close(s.stream.streamServerCloseCh)
 
@@ -588,6 +651,10 @@ func New(
 		log.Debugf(ctx, "Metrics are disabled")
 	}
 
+	s.startAutoCheckpointMonitor(ctx)
+	s.startLogRotation(ctx)
+	s.startMemoryHighMonitor(ctx)
+
 	if s.config.Seccomp().IsDisabled() {
 		log.Infof(ctx, "Seccomp is disabled. Not starting notifier watcher")
 	} else if err := s.startSeccompNotifierWatcher(ctx); err != nil {
@@ -246,6 +246,44 @@ func mockNewServer() {
 	Expect(serverConfig.SetCNIPlugin(cniPluginMock)).To(Succeed())
 }
 
+// newTestSandbox returns a sandbox like the test sandbox with the provided ID,
+// pod cgroup and annotations.
+func newTestSandbox(id, cgroupParent string, annotations map[string]string) *sandbox.Sandbox {
+	GinkgoHelper()
+
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	sbox := sandbox.NewBuilder()
+	sbox.SetID(id)
+	sbox.SetName(id + "Name")
+	sbox.SetLogDir("test")
+	sbox.SetShmPath("test")
+	sbox.SetNamespace("")
+	sbox.SetKubeName("")
+	sbox.SetMountLabel("")
+	sbox.SetProcessLabel("")
+	sbox.SetCgroupParent(cgroupParent)
+	sbox.SetRuntimeHandler("")
+	sbox.SetResolvPath("")
+	sbox.SetHostname("")
+	sbox.SetPortMappings([]*hostport.PortMapping{})
+	sbox.SetHostNetwork(false)
+	sbox.SetUsernsMode("")
+	sbox.SetPodLinuxOverhead(nil)
+	sbox.SetPodLinuxResources(nil)
+	sbox.SetCreatedAt(time.Now())
+	Expect(sbox.SetCRISandbox(id, make(map[string]string), annotations, &types.PodSandboxMetadata{})).To(Succeed())
+	sbox.SetPrivileged(false)
+	sbox.SetContainers(memorystore.New[*oci.Container]())
+
+	sb, err := sbox.GetSandbox()
+	Expect(err).ToNot(HaveOccurred())
+
+	return sb
+}
+
 func addContainerAndSandbox() {
 	ctx := context.TODO()
 	Expect(sut.AddSandbox(ctx, testSandbox)).To(Succeed())
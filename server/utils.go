@@ -123,6 +123,76 @@ func getDecryptionKeys(keysPath string) (*encconfig.DecryptConfig, error) {
 	return encconfig.InitDecryption(sortedDc).DecryptConfig, nil
 }
 
+// getEncryptionKeys reads the public keys and certificates of the recipients
+// from the given directory. It returns nil if the path is empty.
+func getEncryptionKeys(keysPath string) (*encconfig.EncryptConfig, error) {
+	if keysPath == "" {
+		return nil, nil
+	}
+
+	var pubKeys, x509s [][]byte
+
+	walkFn := func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		// Handle symlinks
+		if info.Mode()&os.ModeSymlink == os.ModeSymlink {
+			return errors.New("symbolic links not supported in encryption keys paths")
+		}
+
+		key, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read encryption key file: %w", err)
+		}
+
+		switch {
+		case cryptUtils.IsCertificate(key):
+			x509s = append(x509s, key)
+		case cryptUtils.IsPublicKey(key):
+			pubKeys = append(pubKeys, key)
+		default:
+			return fmt.Errorf("unsupported encryption key file %s", path)
+		}
+
+		return nil
+	}
+	if err := filepath.Walk(keysPath, walkFn); err != nil {
+		return nil, err
+	}
+
+	var cryptoConfigs []encconfig.CryptoConfig
+
+	if len(pubKeys) > 0 {
+		cc, err := encconfig.EncryptWithJwe(pubKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		cryptoConfigs = append(cryptoConfigs, cc)
+	}
+
+	if len(x509s) > 0 {
+		cc, err := encconfig.EncryptWithPkcs7(x509s)
+		if err != nil {
+			return nil, err
+		}
+
+		cryptoConfigs = append(cryptoConfigs, cc)
+	}
+
+	if len(cryptoConfigs) == 0 {
+		return nil, fmt.Errorf("no encryption keys found in %s", keysPath)
+	}
+
+	return encconfig.CombineCryptoConfigs(cryptoConfigs).EncryptConfig, nil
+}
+
 func getSourceMount(source string, mountinfos []*mount.Info) (path, optional string, _ error) {
 	var res *mount.Info
 
@@ -133,6 +133,41 @@ func TestGetDecryptionKeys(t *testing.T) {
 	}
 }
 
+func TestGetEncryptionKeys(t *testing.T) {
+	keysDir := t.TempDir()
+
+	cc, err := getEncryptionKeys("")
+	if err != nil || cc != nil {
+		t.Fatalf("Expected no keys for an empty path, got %v: %v", cc, err)
+	}
+
+	_, err = getEncryptionKeys(keysDir)
+	if err == nil {
+		t.Fatalf("Expected an error for a path without keys")
+	}
+
+	// Create a RSA public key
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unable to generate a private key %v", err)
+	}
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatalf("Unable to marshal a public key %v", err)
+	}
+
+	err = os.WriteFile(keysDir+"/public.key", publicKeyBytes, 0o644)
+	if err != nil {
+		t.Fatalf("Unable to write a public key %v", err)
+	}
+
+	cc, err = getEncryptionKeys(keysDir)
+	if err != nil || cc == nil {
+		t.Fatalf("Unable to find the expected keys: %v", err)
+	}
+}
+
 func TestGetSourceMount(t *testing.T) {
 	mountinfo := []*mount.Info{
 		{Mountpoint: "/"},